@@ -0,0 +1,68 @@
+package dicescript
+
+import (
+	"testing"
+)
+
+// 执行性能与内存分配的基准测试，使用 go test -run ^$ -bench . -benchmem 运行
+
+var benchExprs = []struct {
+	name string
+	expr string
+}{
+	{"Dice", "d20+5"},
+	{"Arith", "(1 + 2) * 3 - 4 / 2 + 力量"},
+	{"Array", "[1, 2, 3, 4, 5].sum() + [d6, d6, d6].kh()"},
+	{"While", "i = 0; s = 0; while i < 100 { i += 1; s += i }; s"},
+	{"FString", "`结果: {d20 + 力量}, {'a' + 'b'}`"},
+	{"FuncCall", "func f(a, b) { a + b }; f(1, 2) + f(3, 4)"},
+	{"Recursion", "func fib(n) { if n < 2 { return n }; return fib(n - 1) + fib(n - 2) }; fib(10)"},
+}
+
+func BenchmarkRun(b *testing.B) {
+	for _, item := range benchExprs {
+		b.Run(item.name, func(b *testing.B) {
+			vm := NewVM()
+			vm.Attrs.Store("力量", ni(50))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := vm.Run(item.expr); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkExec 只测量执行部分，不含解析
+func BenchmarkExec(b *testing.B) {
+	for _, item := range benchExprs {
+		b.Run(item.name, func(b *testing.B) {
+			prog, err := Compile(item.expr, RollConfig{})
+			if err != nil {
+				b.Fatal(err)
+			}
+			vm := NewVM()
+			vm.Attrs.Store("力量", ni(50))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := vm.Exec(prog); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkExecDetail(b *testing.B) {
+	prog, _ := Compile("3d6 + d20 + 5", RollConfig{})
+	vm := NewVM()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = vm.Exec(prog)
+		_ = vm.GetDetailText()
+	}
+}
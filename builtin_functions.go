@@ -2,8 +2,13 @@ package dicescript
 
 import (
 	"errors"
+	"fmt"
 	"math"
+	"math/big"
+	"regexp"
 	"strconv"
+	"sync"
+	"unicode/utf8"
 )
 
 func funcCeil(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
@@ -67,17 +72,17 @@ func funcAbs(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
 }
 
 func funcToBool(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
-	v := params[0]
-	if v.AsBool() {
-		return NewIntVal(1)
-	}
-	return NewIntVal(0)
+	return boolToVMValue(ctx, params[0].AsBool())
 }
 
 func funcToInt(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
 	switch params[0].TypeId {
 	case VMTypeInt:
 		return params[0]
+	case VMTypeBool:
+		return params[0].boolAsInt()
+	case VMTypeBigInt:
+		return params[0]
 	case VMTypeFloat:
 		v, _ := params[0].ReadFloat()
 		return NewIntVal(IntType(v))
@@ -86,6 +91,8 @@ func funcToInt(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
 		val, err := strconv.ParseInt(s, 10, 64)
 		if err == nil {
 			return NewIntVal(IntType(val))
+		} else if i, ok := new(big.Int).SetString(s, 10); ok && ctx.Config.EnableBigInt {
+			return NewBigIntVal(i)
 		} else {
 			ctx.Error = errors.New("(toInt)值错误: 无法进行 toInt() 转换: " + s)
 		}
@@ -97,9 +104,11 @@ func funcToInt(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
 
 func funcToFloat(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
 	switch params[0].TypeId {
-	case VMTypeInt:
-		v, _ := params[0].ReadInt()
+	case VMTypeInt, VMTypeBool:
+		v, _ := params[0].boolAsInt().ReadInt()
 		return NewFloatVal(float64(v))
+	case VMTypeBigInt:
+		return NewFloatVal(bigIntToFloat(params[0].Value.(*big.Int)))
 	case VMTypeFloat:
 		return params[0]
 	case VMTypeString:
@@ -183,6 +192,194 @@ func funcDir(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
 	return NewArrayValRaw(arr)
 }
 
+const (
+	regexpPatternLimit = 512 // 正则表达式的最大长度(字符数)
+	regexpCacheLimit   = 256 // 编译缓存的最大条目数，超出后整体清空
+	regexpMatchLimit   = 512 // reFindAll/reSplit 单次最多产生的项数
+)
+
+var regexpCache = struct {
+	sync.Mutex
+	items map[string]*regexp.Regexp
+}{items: map[string]*regexp.Regexp{}}
+
+// compileScriptRegexp 编译脚本中传入的正则，带缓存。因为正则来自用户输入，需要限制长度
+func compileScriptRegexp(ctx *Context, funcName string, v *VMValue) *regexp.Regexp {
+	pattern, ok := v.ReadString()
+	if !ok {
+		ctx.Error = fmt.Errorf("(%s)类型错误: 正则表达式必须为str", funcName)
+		return nil
+	}
+	if utf8.RuneCountInString(pattern) > regexpPatternLimit {
+		ctx.Error = fmt.Errorf("(%s)正则表达式过长，不能超过%d个字符", funcName, regexpPatternLimit)
+		return nil
+	}
+
+	regexpCache.Lock()
+	defer regexpCache.Unlock()
+	if re, exists := regexpCache.items[pattern]; exists {
+		return re
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		ctx.Error = fmt.Errorf("(%s)正则表达式错误: %s", funcName, err.Error())
+		return nil
+	}
+	if len(regexpCache.items) >= regexpCacheLimit {
+		regexpCache.items = map[string]*regexp.Regexp{}
+	}
+	regexpCache.items[pattern] = re
+	return re
+}
+
+// regexpCaptures 将一次匹配的结果转换为脚本值，loc 为 FindStringSubmatchIndex 的结果
+// 若正则中含有命名分组，返回字典(键为组名，'0'为整个匹配)，否则返回数组(第0项为整个匹配)
+func regexpCaptures(re *regexp.Regexp, text string, loc []int) *VMValue {
+	group := func(i int) *VMValue {
+		// 未参与匹配的分组视为null
+		if loc[2*i] < 0 {
+			return NewNullVal()
+		}
+		return NewStrVal(text[loc[2*i]:loc[2*i+1]])
+	}
+
+	names := re.SubexpNames()
+	hasNamed := false
+	for _, name := range names {
+		if name != "" {
+			hasNamed = true
+			break
+		}
+	}
+
+	if hasNamed {
+		d := NewDictVal(nil)
+		d.Store("0", group(0))
+		for i := 1; i < len(names); i++ {
+			if names[i] != "" {
+				d.Store(names[i], group(i))
+			}
+		}
+		return d.V()
+	}
+
+	arr := make([]*VMValue, len(names))
+	for i := range names {
+		arr[i] = group(i)
+	}
+	return NewArrayValRaw(arr)
+}
+
+func readRegexpText(ctx *Context, funcName string, v *VMValue) (string, bool) {
+	text, ok := v.ReadString()
+	if !ok {
+		ctx.Error = fmt.Errorf("(%s)类型错误: 文本必须为str", funcName)
+	}
+	return text, ok
+}
+
+func funcReMatch(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
+	re := compileScriptRegexp(ctx, "reMatch", params[0])
+	if re == nil {
+		return nil
+	}
+	text, ok := readRegexpText(ctx, "reMatch", params[1])
+	if !ok {
+		return nil
+	}
+	return boolToVMValue(ctx, re.MatchString(text))
+}
+
+func funcReFind(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
+	re := compileScriptRegexp(ctx, "reFind", params[0])
+	if re == nil {
+		return nil
+	}
+	text, ok := readRegexpText(ctx, "reFind", params[1])
+	if !ok {
+		return nil
+	}
+
+	loc := re.FindStringSubmatchIndex(text)
+	if loc == nil {
+		return NewNullVal()
+	}
+	return regexpCaptures(re, text, loc)
+}
+
+func funcReFindAll(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
+	re := compileScriptRegexp(ctx, "reFindAll", params[0])
+	if re == nil {
+		return nil
+	}
+	text, ok := readRegexpText(ctx, "reFindAll", params[1])
+	if !ok {
+		return nil
+	}
+
+	// 多取一项，用于判断是否超出上限
+	locs := re.FindAllStringSubmatchIndex(text, regexpMatchLimit+1)
+	if len(locs) > regexpMatchLimit {
+		ctx.Error = fmt.Errorf("(reFindAll)匹配过多，不能超过%d项", regexpMatchLimit)
+		return nil
+	}
+	var arr []*VMValue
+	for _, loc := range locs {
+		arr = append(arr, regexpCaptures(re, text, loc))
+	}
+	if !ctx.allocArray(IntType(len(arr))) {
+		return nil
+	}
+	return NewArrayValRaw(arr)
+}
+
+func funcReReplace(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
+	re := compileScriptRegexp(ctx, "reReplace", params[0])
+	if re == nil {
+		return nil
+	}
+	text, ok := readRegexpText(ctx, "reReplace", params[1])
+	if !ok {
+		return nil
+	}
+	repl, ok := params[2].ReadString()
+	if !ok {
+		ctx.Error = errors.New("(reReplace)类型错误: 替换内容必须为str")
+		return nil
+	}
+	ret := re.ReplaceAllString(text, repl)
+	if !ctx.allocString(len(ret)) {
+		return nil
+	}
+	return NewStrVal(ret)
+}
+
+func funcReSplit(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
+	re := compileScriptRegexp(ctx, "reSplit", params[0])
+	if re == nil {
+		return nil
+	}
+	text, ok := readRegexpText(ctx, "reSplit", params[1])
+	if !ok {
+		return nil
+	}
+
+	parts := re.Split(text, regexpMatchLimit+1)
+	if len(parts) > regexpMatchLimit {
+		ctx.Error = fmt.Errorf("(reSplit)拆分结果过多，不能超过%d项", regexpMatchLimit)
+		return nil
+	}
+	if !ctx.allocArray(IntType(len(parts))) {
+		return nil
+	}
+	arr := make([]*VMValue, len(parts))
+	for i, part := range parts {
+		arr[i] = NewStrVal(part)
+	}
+	return NewArrayValRaw(arr)
+}
+
 //
 // func funcHelp(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
 //	// 函数名，参数，说明
@@ -209,6 +406,12 @@ var builtinValues = map[string]*VMValue{
 	"loadRaw": nnf(&ndf{"loadRaw", []string{"value"}, nil, nil, nil}),
 	"store":   nnf(&ndf{"store", []string{"name", "value"}, nil, nil, nil}),
 
+	"reMatch":   nnf(&ndf{"reMatch", []string{"pattern", "text"}, nil, nil, funcReMatch}),
+	"reFind":    nnf(&ndf{"reFind", []string{"pattern", "text"}, nil, nil, funcReFind}),
+	"reFindAll": nnf(&ndf{"reFindAll", []string{"pattern", "text"}, nil, nil, funcReFindAll}),
+	"reReplace": nnf(&ndf{"reReplace", []string{"pattern", "text", "repl"}, nil, nil, funcReReplace}),
+	"reSplit":   nnf(&ndf{"reSplit", []string{"pattern", "text"}, nil, nil, funcReSplit}),
+
 	// TODO: roll()
 
 	// 要不要进行权限隔绝？
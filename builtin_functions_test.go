@@ -1,8 +1,10 @@
 package dicescript
 
 import (
-	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestNativeFunctionCall(t *testing.T) {
@@ -140,3 +142,83 @@ func TestNativeFunctionAbs(t *testing.T) {
 	assert.Error(t, vm.Error)
 	vm.Error = nil
 }
+
+func TestNativeFunctionRegexp(t *testing.T) {
+	vm := NewVM()
+	assert.True(t, valueEqual(funcReMatch(vm, nil, []*VMValue{ns(`^\d+$`), ns("123")}), ni(1)))
+	assert.True(t, valueEqual(funcReMatch(vm, nil, []*VMValue{ns(`^\d+$`), ns("12a")}), ni(0)))
+
+	v := funcReFind(vm, nil, []*VMValue{ns(`(\p{Han}+)(\d+)`), ns("st 力量60敏捷70")})
+	assert.True(t, valueEqual(v, na(ns("力量60"), ns("力量"), ns("60"))))
+
+	v = funcReFind(vm, nil, []*VMValue{ns(`x(\d+)`), ns("abc")})
+	assert.True(t, valueEqual(v, NewNullVal()))
+
+	v = funcReFind(vm, nil, []*VMValue{ns(`(?P<name>\p{Han}+)(?P<val>\d+)?`), ns("力量")})
+	d, _ := v.ReadDictData()
+	if assert.NotNil(t, d) {
+		assert.True(t, valueEqual(d.Dict.MustLoad("name"), ns("力量")))
+		assert.True(t, valueEqual(d.Dict.MustLoad("val"), NewNullVal()))
+		assert.True(t, valueEqual(d.Dict.MustLoad("0"), ns("力量")))
+	}
+
+	v = funcReFindAll(vm, nil, []*VMValue{ns(`(\p{Han}+)(\d+)`), ns("力量60敏捷70")})
+	assert.True(t, valueEqual(v, na(na(ns("力量60"), ns("力量"), ns("60")), na(ns("敏捷70"), ns("敏捷"), ns("70")))))
+
+	v = funcReReplace(vm, nil, []*VMValue{ns(`(\d+)d(\d+)`), ns("1d20+2d6"), ns("${2}d$1")})
+	assert.True(t, valueEqual(v, ns("20d1+6d2")))
+
+	v = funcReSplit(vm, nil, []*VMValue{ns(`[,，\s]+`), ns("力量, 敏捷，体质")})
+	assert.True(t, valueEqual(v, na(ns("力量"), ns("敏捷"), ns("体质"))))
+}
+
+func TestNativeFunctionRegexpError(t *testing.T) {
+	vm := NewVM()
+	funcReMatch(vm, nil, []*VMValue{ns(`(`), ns("")})
+	assert.Error(t, vm.Error)
+	vm.Error = nil
+
+	funcReMatch(vm, nil, []*VMValue{ns(strings.Repeat("a", regexpPatternLimit+1)), ns("")})
+	assert.Error(t, vm.Error)
+	vm.Error = nil
+
+	funcReMatch(vm, nil, []*VMValue{ni(1), ns("")})
+	assert.Error(t, vm.Error)
+	vm.Error = nil
+
+	funcReFind(vm, nil, []*VMValue{ns("a"), ni(1)})
+	assert.Error(t, vm.Error)
+	vm.Error = nil
+}
+
+func TestNativeFunctionRegexpScript(t *testing.T) {
+	vm := NewVM()
+	err := vm.Run("m = reFind('(\\\\p{Han}+)(\\\\d+)', '力量60'); m[1] + '=' + m[2]")
+	if assert.NoError(t, err) {
+		assert.True(t, valueEqual(vm.Ret, ns("力量=60")))
+	}
+
+	vm = NewVM()
+	err = vm.Run("reFindAll('\\\\d', '1a2b3').len()")
+	if assert.NoError(t, err) {
+		assert.True(t, valueEqual(vm.Ret, ni(3)))
+	}
+
+	// 超出项数上限时报错，而不是截断
+	text := ns(strings.Repeat("a,", regexpMatchLimit))
+	vm = NewVM()
+	ret := funcReFindAll(vm, nil, []*VMValue{ns("a"), text})
+	if assert.NoError(t, vm.Error) {
+		assert.Equal(t, regexpMatchLimit, len(ret.MustReadArray().List))
+	}
+	funcReFindAll(vm, nil, []*VMValue{ns("a"), ns(strings.Repeat("a,", regexpMatchLimit+1))})
+	assert.Error(t, vm.Error)
+	vm.Error = nil
+
+	ret = funcReSplit(vm, nil, []*VMValue{ns(","), ns(strings.Repeat("a,", regexpMatchLimit-1) + "a")})
+	if assert.NoError(t, vm.Error) {
+		assert.Equal(t, regexpMatchLimit, len(ret.MustReadArray().List))
+	}
+	funcReSplit(vm, nil, []*VMValue{ns(","), text})
+	assert.Error(t, vm.Error)
+}
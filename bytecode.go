@@ -3,6 +3,7 @@ package dicescript
 import (
 	"fmt"
 	"strconv"
+	"strings"
 )
 
 type CodeType uint8
@@ -10,6 +11,7 @@ type CodeType uint8
 type ByteCode struct {
 	T     CodeType
 	Value any
+	Arg   IntType // 整数操作数，如跳转偏移、元素个数、参数个数，执行时不需要类型断言
 }
 
 const (
@@ -21,6 +23,8 @@ const (
 	typePushRange
 	typePushComputed
 	typePushNull
+	typePushBool
+	typePushBigInt // 超出 IntType 范围的整数字面量
 	typePushThis
 	typePushGlobal
 	typePushFunction
@@ -34,15 +38,25 @@ const (
 	typeStoreName
 	typeStoreNameGlobal
 	typeStoreNameLocal
+	typeStoreNameLet // let/const 声明，写入当前语句块
+	typeStoreNameConst
+	typeDeclareGlobal
+	typeImport
 
 	typeInvoke
 	typeInvokeSelf
+	typeInvokeNamed
 	typeItemGet
 	typeItemSet
 	typeAttrGet
 	typeAttrSet
 	typeSliceGet
 	typeSliceSet
+	typeStoreNameOp // 复合赋值，如 a += 1
+	typeAttrSetOp
+	typeItemSetOp
+	typeUnpackArray // 解构赋值
+	typeUnpackDict
 
 	typeAdd // 注意，修改顺序时一定要顺带修改下面的数组
 	typeSubtract
@@ -66,6 +80,10 @@ const (
 
 	typeNegation
 	typePositive
+	typeLogicNot
+
+	typeIn
+	typeNotIn
 
 	typeDiceInit
 	typeDiceSetTimes
@@ -96,6 +114,7 @@ const (
 
 	typePop
 	typePopN
+	typeDup
 
 	typeNop
 
@@ -103,6 +122,7 @@ const (
 	typeJe
 	typeJne
 	typeJeDup
+	typeJeNull // 栈顶为 null 时跳转，不出栈
 	typeReturn
 
 	typeFStringBlockPush // fstring标记 用于栈平衡
@@ -110,6 +130,11 @@ const (
 
 	typeBlockPush
 	typeBlockPop
+	typeBlockUnwind // 离开内层的 Arg 个语句块，用于 break/continue
+
+	typeTryBegin // 注册异常处理器，值为到 catch 代码的偏移
+	typeTryEnd
+	typeThrow
 
 	typeStSetName
 	typeStModify
@@ -117,6 +142,25 @@ const (
 	typeStX1
 )
 
+// span 指令记录的源码位置，出错时用于定位
+func (code *ByteCode) span() (BufferSpan, bool) {
+	switch v := code.Value.(type) {
+	case BufferSpan:
+		return v, true
+	case InvokeNamedInfo:
+		return v.Span, v.Span.End > 0
+	case AttrInfo:
+		return v.Span, v.Span.End > 0
+	case InplaceInfo:
+		return v.Span, v.Span.End > 0
+	case UnpackInfo:
+		return v.Span, v.Span.End > 0
+	case ImportInfo:
+		return v.Span, v.Span.End > 0
+	}
+	return BufferSpan{}, false
+}
+
 func (code *ByteCode) CodeString() string {
 	switch code.T {
 	case typePushIntNumber:
@@ -128,14 +172,18 @@ func (code *ByteCode) CodeString() string {
 	case typePushRange:
 		return "push.range"
 	case typePushArray:
-		return "push.arr " + strconv.FormatInt(int64(code.Value.(IntType)), 10)
+		return "push.arr " + strconv.FormatInt(int64(code.Arg), 10)
 	case typePushDict:
-		return "push.dict " + strconv.FormatInt(int64(code.Value.(IntType)), 10)
+		return "push.dict " + strconv.FormatInt(int64(code.Arg), 10)
 	case typePushComputed:
 		computed, _ := code.Value.(*VMValue).ReadComputed()
 		return "push.computed " + computed.Expr
 	case typePushNull:
 		return "push.null"
+	case typePushBool:
+		return fmt.Sprintf("push.bool %v", code.Value)
+	case typePushBigInt:
+		return fmt.Sprintf("push.bigint %v", code.Value)
 	case typePushThis:
 		return "push.this"
 	case typePushGlobal:
@@ -145,22 +193,40 @@ func (code *ByteCode) CodeString() string {
 		return "push.func " + computed.Name
 
 	case typeInvoke:
-		return "invoke " + strconv.FormatInt(int64(code.Value.(IntType)), 10)
+		return "invoke " + strconv.FormatInt(int64(code.Arg), 10)
 
 	case typeInvokeSelf:
 		return "invoke.self " + code.Value.(string)
+	case typeInvokeNamed:
+		info := code.Value.(InvokeNamedInfo)
+		return fmt.Sprintf("invoke.named %d %s", info.Num, strings.Join(info.Names, ","))
 	case typeItemGet:
 		return "item.get"
 	case typeItemSet:
 		return "item.set"
 	case typeAttrSet:
-		return "attr.set " + code.Value.(string)
+		return "attr.set " + code.Value.(AttrInfo).Name
 	case typeAttrGet:
-		return "attr.get " + code.Value.(string)
+		return "attr.get " + code.Value.(AttrInfo).Name
 	case typeSliceGet:
 		return "slice.get"
 	case typeSliceSet:
 		return "slice.set"
+	case typeStoreNameOp:
+		info := code.Value.(InplaceInfo)
+		return fmt.Sprintf("store.op %s %s", info.Name, info.OpText())
+	case typeAttrSetOp:
+		info := code.Value.(InplaceInfo)
+		return fmt.Sprintf("attr.set.op %s %s", info.Name, info.OpText())
+	case typeItemSetOp:
+		info := code.Value.(InplaceInfo)
+		return "item.set.op " + info.OpText()
+	case typeUnpackArray:
+		info := code.Value.(UnpackInfo)
+		return fmt.Sprintf("unpack.arr %d %d", info.Count, info.RestIndex)
+	case typeUnpackDict:
+		info := code.Value.(UnpackInfo)
+		return "unpack.dict " + strings.Join(info.Keys, ",")
 
 	case typeAdd:
 		return "add"
@@ -191,6 +257,12 @@ func (code *ByteCode) CodeString() string {
 		return "neg"
 	case typePositive:
 		return "pos"
+	case typeLogicNot:
+		return "not"
+	case typeIn:
+		return "in"
+	case typeNotIn:
+		return "not.in"
 
 	case typeDiceInit:
 		return "dice.init"
@@ -246,26 +318,40 @@ func (code *ByteCode) CodeString() string {
 	case typeLoadNameRaw:
 		return "ld.raw " + code.Value.(string)
 	case typeLoadFormatString:
-		return fmt.Sprintf("ld.fs %d", code.Value)
+		return fmt.Sprintf("ld.fs %d", code.Arg)
 	case typeStoreName:
 		return fmt.Sprintf("store %s", code.Value)
 	case typeStoreNameGlobal:
 		return fmt.Sprintf("store.global %s", code.Value)
 	case typeStoreNameLocal:
 		return fmt.Sprintf("store.local %s", code.Value)
+	case typeStoreNameLet:
+		return fmt.Sprintf("store.let %s", code.Value)
+	case typeStoreNameConst:
+		return fmt.Sprintf("store.const %s", code.Value)
+	case typeDeclareGlobal:
+		return fmt.Sprintf("global %s", code.Value)
+	case typeImport:
+		info := code.Value.(ImportInfo)
+		if len(info.Keys) > 0 {
+			return fmt.Sprintf("import %s %s", info.Module, strings.Join(info.Keys, ","))
+		}
+		return "import " + info.Module
 	case typeHalt:
 		return "halt"
 	case typeDetailMark:
 		v := code.Value.(BufferSpan)
 		return fmt.Sprintf("mark.detail %d, %d", v.Begin, v.End)
 	case typeJmp:
-		return fmt.Sprintf("jmp %d", code.Value)
+		return fmt.Sprintf("jmp %d", code.Arg)
 	case typeJe:
-		return fmt.Sprintf("je %d", code.Value)
+		return fmt.Sprintf("je %d", code.Arg)
 	case typeJeDup:
-		return fmt.Sprintf("je.dup %d", code.Value)
+		return fmt.Sprintf("je.dup %d", code.Arg)
 	case typeJne:
-		return fmt.Sprintf("jne %d", code.Value)
+		return fmt.Sprintf("jne %d", code.Arg)
+	case typeJeNull:
+		return fmt.Sprintf("je.null %d", code.Arg)
 	case typeCompLT:
 		return "comp.lt"
 	case typeCompLE:
@@ -285,7 +371,9 @@ func (code *ByteCode) CodeString() string {
 	case typePop:
 		return "pop"
 	case typePopN:
-		return fmt.Sprintf("popn %d", code.Value)
+		return fmt.Sprintf("popn %d", code.Arg)
+	case typeDup:
+		return "dup"
 	case typeNop:
 		return "nop"
 	case typeReturn:
@@ -295,12 +383,21 @@ func (code *ByteCode) CodeString() string {
 		return "block.push"
 	case typeBlockPop:
 		return "block.pop"
+	case typeBlockUnwind:
+		return fmt.Sprintf("block.unwind %d", code.Arg)
 
 	case typeFStringBlockPush:
 		return "fstr.block.push"
 	case typeFStringBlockPop:
 		return "fstr.block.pop"
 
+	case typeTryBegin:
+		return fmt.Sprintf("try.begin %d", code.Arg)
+	case typeTryEnd:
+		return "try.end"
+	case typeThrow:
+		return "throw"
+
 	case typeStSetName:
 		return "st.set"
 	case typeStModify:
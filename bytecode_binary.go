@@ -0,0 +1,531 @@
+package dicescript
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/big"
+	"reflect"
+	"runtime"
+	"sync"
+	"unicode"
+)
+
+// 字节码二进制格式。改动指令的含义或编号、或是值的编码方式时，需要增加版本号
+const (
+	bytecodeMagic         = "DSBC"
+	bytecodeFormatVersion = 4
+)
+
+var (
+	ErrBytecodeVersion = errors.New("字节码的格式版本或语法与当前版本不一致，需要重新编译")
+	errBytecodeCorrupt = errors.New("字节码数据已损坏")
+)
+
+// 指令值的类型标记
+const (
+	codeValNil byte = iota
+	codeValInt
+	codeValString
+	codeValFloat
+	codeValBool
+	codeValBigInt
+	codeValSpan
+	codeValComputed
+	codeValFunction
+	codeValInvokeNamed
+	codeValInplace
+	codeValUnpack
+	codeValImport
+	codeValSt
+	codeValAttr
+)
+
+var rangeTableType = reflect.TypeOf((*unicode.RangeTable)(nil))
+
+var grammarHashOnce struct {
+	sync.Once
+	value uint64
+}
+
+// grammarHash 语法规则与指令集的摘要，语法发生变化后，旧的字节码缓存将失效
+func grammarHash() uint64 {
+	grammarHashOnce.Do(func() {
+		h := fnv.New64a()
+		var walk func(v reflect.Value)
+		walk = func(v reflect.Value) {
+			switch v.Kind() {
+			case reflect.Ptr, reflect.Interface:
+				// unicode 的字符表是标准库中的数据，不需要计入
+				if v.IsNil() || v.Type() == rangeTableType {
+					return
+				}
+				walk(v.Elem())
+			case reflect.Struct:
+				_, _ = h.Write([]byte(v.Type().Name()))
+				for i := 0; i < v.NumField(); i++ {
+					walk(v.Field(i))
+				}
+			case reflect.Slice:
+				_, _ = fmt.Fprint(h, v.Len())
+				for i := 0; i < v.Len(); i++ {
+					walk(v.Index(i))
+				}
+			case reflect.String:
+				_, _ = h.Write([]byte(v.String()))
+			case reflect.Bool:
+				_, _ = fmt.Fprint(h, v.Bool())
+			case reflect.Int, reflect.Int32, reflect.Int64:
+				_, _ = fmt.Fprint(h, v.Int())
+			case reflect.Func:
+				if fn := runtime.FuncForPC(v.Pointer()); fn != nil {
+					_, _ = h.Write([]byte(fn.Name()))
+				}
+			}
+		}
+		walk(reflect.ValueOf(g).Elem())
+		_, _ = fmt.Fprint(h, typeStX1)
+		grammarHashOnce.value = h.Sum64()
+	})
+	return grammarHashOnce.value
+}
+
+type bytecodeWriter struct {
+	buf []byte
+}
+
+func (w *bytecodeWriter) uint(n uint64) {
+	var b [binary.MaxVarintLen64]byte
+	w.buf = append(w.buf, b[:binary.PutUvarint(b[:], n)]...)
+}
+
+func (w *bytecodeWriter) int(n int64) {
+	var b [binary.MaxVarintLen64]byte
+	w.buf = append(w.buf, b[:binary.PutVarint(b[:], n)]...)
+}
+
+func (w *bytecodeWriter) uint64(n uint64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], n)
+	w.buf = append(w.buf, b[:]...)
+}
+
+func (w *bytecodeWriter) bytes(b []byte) {
+	w.uint(uint64(len(b)))
+	w.buf = append(w.buf, b...)
+}
+
+func (w *bytecodeWriter) string(s string) {
+	w.uint(uint64(len(s)))
+	w.buf = append(w.buf, s...)
+}
+
+func (w *bytecodeWriter) strings(lst []string) {
+	w.uint(uint64(len(lst)))
+	for _, s := range lst {
+		w.string(s)
+	}
+}
+
+func (w *bytecodeWriter) bool(b bool) {
+	if b {
+		w.buf = append(w.buf, 1)
+	} else {
+		w.buf = append(w.buf, 0)
+	}
+}
+
+func (w *bytecodeWriter) code(code []ByteCode) error {
+	w.uint(uint64(len(code)))
+	for _, c := range code {
+		w.uint(uint64(c.T))
+		w.int(int64(c.Arg))
+		if err := w.value(c.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *bytecodeWriter) value(val any) error {
+	switch v := val.(type) {
+	case nil:
+		w.buf = append(w.buf, codeValNil)
+	case IntType:
+		w.buf = append(w.buf, codeValInt)
+		w.int(int64(v))
+	case string:
+		w.buf = append(w.buf, codeValString)
+		w.string(v)
+	case float64:
+		w.buf = append(w.buf, codeValFloat)
+		w.uint64(math.Float64bits(v))
+	case bool:
+		w.buf = append(w.buf, codeValBool)
+		w.bool(v)
+	case *big.Int:
+		w.buf = append(w.buf, codeValBigInt)
+		w.string(v.String())
+	case BufferSpan:
+		w.buf = append(w.buf, codeValSpan)
+		w.int(int64(v.Begin))
+		w.int(int64(v.End))
+		w.strings([]string{v.Text, v.Expr, v.Tag, v.ExprSuffix})
+		w.bool(v.TextOnly)
+	case *VMValue:
+		switch v.TypeId {
+		case VMTypeComputedValue:
+			cd, _ := v.ReadComputed()
+			w.buf = append(w.buf, codeValComputed)
+			w.string(cd.Expr)
+			return w.code(cd.code[:cd.codeIndex])
+		case VMTypeFunction:
+			fd, _ := v.ReadFunctionData()
+			w.buf = append(w.buf, codeValFunction)
+			w.string(fd.Expr)
+			w.string(fd.Name)
+			w.strings(fd.Params)
+			w.uint(uint64(len(fd.Defaults)))
+			for _, d := range fd.Defaults {
+				if d == nil {
+					w.buf = append(w.buf, codeValNil)
+				} else if err := w.value(d); err != nil {
+					return err
+				}
+			}
+			return w.code(fd.code[:fd.codeIndex])
+		}
+		return fmt.Errorf("字节码中包含无法序列化的值: %s", v.GetTypeName())
+	case InvokeNamedInfo:
+		w.buf = append(w.buf, codeValInvokeNamed)
+		w.int(int64(v.Num))
+		w.strings(v.Names)
+		w.int(int64(v.Span.Begin))
+		w.int(int64(v.Span.End))
+	case InplaceInfo:
+		w.buf = append(w.buf, codeValInplace)
+		w.string(v.Name)
+		w.uint(uint64(v.Op))
+		w.int(int64(v.Span.Begin))
+		w.int(int64(v.Span.End))
+	case UnpackInfo:
+		w.buf = append(w.buf, codeValUnpack)
+		w.int(int64(v.Count))
+		w.int(int64(v.RestIndex))
+		w.strings(v.Keys)
+		w.int(int64(v.Span.Begin))
+		w.int(int64(v.Span.End))
+	case ImportInfo:
+		w.buf = append(w.buf, codeValImport)
+		w.string(v.Module)
+		w.strings(v.Keys)
+		w.int(int64(v.Span.Begin))
+		w.int(int64(v.Span.End))
+	case AttrInfo:
+		w.buf = append(w.buf, codeValAttr)
+		w.string(v.Name)
+		w.int(int64(v.Span.Begin))
+		w.int(int64(v.Span.End))
+	case StInfo:
+		w.buf = append(w.buf, codeValSt)
+		w.string(v.Op)
+		w.string(v.Text)
+	default:
+		// 如自定义骰子，其中含有宿主注册的回调函数
+		return fmt.Errorf("字节码中包含无法序列化的内容: %T", val)
+	}
+	return nil
+}
+
+type bytecodeReader struct {
+	data []byte
+	err  error
+}
+
+func (r *bytecodeReader) fail() {
+	if r.err == nil {
+		r.err = errBytecodeCorrupt
+	}
+	r.data = nil
+}
+
+func (r *bytecodeReader) byte() byte {
+	if len(r.data) == 0 {
+		r.fail()
+		return 0
+	}
+	b := r.data[0]
+	r.data = r.data[1:]
+	return b
+}
+
+func (r *bytecodeReader) uint() uint64 {
+	n, size := binary.Uvarint(r.data)
+	if size <= 0 {
+		r.fail()
+		return 0
+	}
+	r.data = r.data[size:]
+	return n
+}
+
+func (r *bytecodeReader) int() int64 {
+	n, size := binary.Varint(r.data)
+	if size <= 0 {
+		r.fail()
+		return 0
+	}
+	r.data = r.data[size:]
+	return n
+}
+
+// length 读取长度，并确认剩余数据至少还有这么多字节，避免损坏的数据导致分配过大的内存
+func (r *bytecodeReader) length() int {
+	n := r.uint()
+	if n > uint64(len(r.data)) {
+		r.fail()
+		return 0
+	}
+	return int(n)
+}
+
+func (r *bytecodeReader) bytes() []byte {
+	n := r.length()
+	if r.err != nil {
+		return nil
+	}
+	b := append([]byte{}, r.data[:n]...)
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *bytecodeReader) string() string {
+	return string(r.bytes())
+}
+
+func (r *bytecodeReader) strings() []string {
+	n := r.length()
+	if n == 0 {
+		return nil
+	}
+	lst := make([]string, 0, n)
+	for i := 0; i < n && r.err == nil; i++ {
+		lst = append(lst, r.string())
+	}
+	return lst
+}
+
+func (r *bytecodeReader) bool() bool {
+	return r.byte() != 0
+}
+
+func (r *bytecodeReader) code() []ByteCode {
+	n := r.length()
+	code := make([]ByteCode, 0, n)
+	for i := 0; i < n && r.err == nil; i++ {
+		t := CodeType(r.uint())
+		if t > typeStX1 {
+			r.fail()
+		}
+		arg := IntType(r.int())
+		c := ByteCode{T: t, Value: r.value(), Arg: arg}
+		if r.err == nil && !codeValid(c, i, n) {
+			r.fail()
+		}
+		code = append(code, c)
+	}
+	return code
+}
+
+// codeValid 检查指令的值类型与跳转目标，损坏的数据在解码时报错，而不是在执行时引发 panic
+func codeValid(c ByteCode, index int, n int) bool {
+	var ok bool
+	switch c.T {
+	case typePushIntNumber:
+		_, ok = c.Value.(IntType)
+	case typePushFloatNumber:
+		_, ok = c.Value.(float64)
+	case typePushBool:
+		_, ok = c.Value.(bool)
+	case typePushBigInt:
+		_, ok = c.Value.(*big.Int)
+	case typePushString, typeInvokeSelf,
+		typeLoadName, typeLoadNameRaw, typeLoadNameWithDetail,
+		typeStoreName, typeStoreNameGlobal, typeStoreNameLocal, typeStoreNameLet, typeStoreNameConst, typeDeclareGlobal:
+		_, ok = c.Value.(string)
+	case typePushComputed:
+		v, isVal := c.Value.(*VMValue)
+		ok = isVal && v.TypeId == VMTypeComputedValue
+	case typePushFunction:
+		v, isVal := c.Value.(*VMValue)
+		ok = isVal && v.TypeId == VMTypeFunction
+	case typeAttrGet, typeAttrSet:
+		_, ok = c.Value.(AttrInfo)
+	case typeInvoke:
+		_, ok = c.Value.(BufferSpan)
+		ok = ok || c.Value == nil
+	case typeInvokeNamed:
+		_, ok = c.Value.(InvokeNamedInfo)
+	case typeStoreNameOp, typeAttrSetOp, typeItemSetOp:
+		_, ok = c.Value.(InplaceInfo)
+	case typeUnpackArray, typeUnpackDict:
+		_, ok = c.Value.(UnpackInfo)
+	case typeImport:
+		_, ok = c.Value.(ImportInfo)
+	case typeDetailMark, typeThrow:
+		_, ok = c.Value.(BufferSpan)
+	case typeStModify:
+		_, ok = c.Value.(StInfo)
+	case typeCustomDice:
+		// 带自定义骰子的程序不会写入缓存
+		ok = false
+	case typeJmp, typeJe, typeJne, typeJeDup, typeJeNull, typeTryBegin:
+		target := index + 1 + int(c.Arg)
+		ok = target >= 0 && target <= n
+	default:
+		ok = true
+	}
+	return ok
+}
+
+func (r *bytecodeReader) value() any {
+	switch r.byte() {
+	case codeValNil:
+		return nil
+	case codeValInt:
+		return IntType(r.int())
+	case codeValString:
+		return r.string()
+	case codeValFloat:
+		if len(r.data) < 8 {
+			r.fail()
+			return nil
+		}
+		f := math.Float64frombits(binary.LittleEndian.Uint64(r.data))
+		r.data = r.data[8:]
+		return f
+	case codeValBool:
+		return r.bool()
+	case codeValBigInt:
+		i, ok := new(big.Int).SetString(r.string(), 10)
+		if !ok {
+			r.fail()
+			return nil
+		}
+		return i
+	case codeValSpan:
+		span := BufferSpan{Begin: IntType(r.int()), End: IntType(r.int())}
+		if s := r.strings(); len(s) == 4 {
+			span.Text, span.Expr, span.Tag, span.ExprSuffix = s[0], s[1], s[2], s[3]
+		} else {
+			r.fail()
+		}
+		span.TextOnly = r.bool()
+		return span
+	case codeValComputed:
+		cd := &ComputedData{Expr: r.string()}
+		cd.code = r.code()
+		cd.codeIndex = len(cd.code)
+		return NewComputedValRaw(cd)
+	case codeValFunction:
+		fd := &FunctionData{Expr: r.string(), Name: r.string(), Params: r.strings()}
+		if n := r.length(); n > 0 {
+			fd.Defaults = make([]*VMValue, n)
+			for i := 0; i < n && r.err == nil; i++ {
+				if d, ok := r.value().(*VMValue); ok {
+					fd.Defaults[i] = d
+				}
+			}
+		}
+		fd.code = r.code()
+		fd.codeIndex = len(fd.code)
+		return NewFunctionValRaw(fd)
+	case codeValInvokeNamed:
+		info := InvokeNamedInfo{Num: IntType(r.int()), Names: r.strings()}
+		info.Span = BufferSpan{Begin: IntType(r.int()), End: IntType(r.int())}
+		return info
+	case codeValInplace:
+		info := InplaceInfo{Name: r.string(), Op: CodeType(r.uint())}
+		info.Span = BufferSpan{Begin: IntType(r.int()), End: IntType(r.int())}
+		return info
+	case codeValUnpack:
+		info := UnpackInfo{Count: IntType(r.int()), RestIndex: IntType(r.int()), Keys: r.strings()}
+		info.Span = BufferSpan{Begin: IntType(r.int()), End: IntType(r.int())}
+		return info
+	case codeValImport:
+		info := ImportInfo{Module: r.string(), Keys: r.strings()}
+		info.Span = BufferSpan{Begin: IntType(r.int()), End: IntType(r.int())}
+		return info
+	case codeValAttr:
+		info := AttrInfo{Name: r.string()}
+		info.Span = BufferSpan{Begin: IntType(r.int()), End: IntType(r.int())}
+		return info
+	case codeValSt:
+		return StInfo{Op: r.string(), Text: r.string()}
+	}
+	r.fail()
+	return nil
+}
+
+// MarshalBinary 将编译结果编码为二进制，带有格式版本和语法摘要，可以保存下来供之后直接执行
+func (prog *Program) MarshalBinary() ([]byte, error) {
+	w := &bytecodeWriter{buf: []byte(bytecodeMagic)}
+	w.uint(bytecodeFormatVersion)
+	w.uint64(grammarHash())
+
+	w.bytes(prog.data)
+	w.uint(uint64(prog.parsedOffset))
+	w.bool(prog.source != nil)
+	if prog.source != nil {
+		w.bytes(prog.source)
+		w.uint(uint64(len(prog.sourceOffsets)))
+		for _, i := range prog.sourceOffsets {
+			w.uint(uint64(i))
+		}
+	}
+	if err := w.code(prog.code); err != nil {
+		return nil, err
+	}
+	return w.buf, nil
+}
+
+// UnmarshalBinary 读取 MarshalBinary 的结果，版本或语法不一致时返回 ErrBytecodeVersion
+func (prog *Program) UnmarshalBinary(data []byte) error {
+	if len(data) < len(bytecodeMagic) || string(data[:len(bytecodeMagic)]) != bytecodeMagic {
+		return errBytecodeCorrupt
+	}
+	r := &bytecodeReader{data: data[len(bytecodeMagic):]}
+	version := r.uint()
+	if r.err == nil && len(r.data) < 8 {
+		r.fail()
+	}
+	if r.err != nil {
+		return r.err
+	}
+	hash := binary.LittleEndian.Uint64(r.data)
+	r.data = r.data[8:]
+	if version != bytecodeFormatVersion || hash != grammarHash() {
+		return ErrBytecodeVersion
+	}
+
+	p := Program{data: r.bytes(), parsedOffset: int(r.uint())}
+	if r.bool() {
+		p.source = r.bytes()
+		n := r.length()
+		p.sourceOffsets = make([]int, 0, n)
+		for i := 0; i < n && r.err == nil; i++ {
+			p.sourceOffsets = append(p.sourceOffsets, int(r.uint()))
+		}
+	}
+	p.code = r.code()
+	if r.err == nil && (len(r.data) != 0 || p.parsedOffset > len(p.data)) {
+		r.fail()
+	}
+	if r.err != nil {
+		return r.err
+	}
+	*prog = p
+	return nil
+}
@@ -0,0 +1,190 @@
+package dicescript
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mapCodeCache struct {
+	data map[string][]byte
+	gets int
+	hits int
+}
+
+func (c *mapCodeCache) Get(key string) []byte {
+	c.gets++
+	if v, ok := c.data[key]; ok {
+		c.hits++
+		return v
+	}
+	return nil
+}
+
+func (c *mapCodeCache) Set(key string, data []byte) {
+	if c.data == nil {
+		c.data = map[string][]byte{}
+	}
+	c.data[key] = data
+}
+
+func TestProgramBinaryRoundTrip(t *testing.T) {
+	exprs := []string{
+		"力量 + 1d1 剩余",
+		"&x = 1d1 + 1; func f(a, b = 2) { a + b + x }; [f(1), x, 'str', 1.5, {'k': 1}]",
+		"99999999999999999999 + 1",
+		"a = [1, 2, 3]; [b, ...c] = a; a[0] += 1; c",
+		"x = null; x?.y ?? 'null'",
+		"`1 + {1}` + 1",
+		"d1 [原因]",
+	}
+	for _, expr := range exprs {
+		prog, err := Compile(expr, RollConfig{})
+		if !assert.NoError(t, err, expr) {
+			continue
+		}
+		data, err := prog.MarshalBinary()
+		if !assert.NoError(t, err, expr) {
+			continue
+		}
+		prog2 := &Program{}
+		if !assert.NoError(t, prog2.UnmarshalBinary(data), expr) {
+			continue
+		}
+
+		vm1, vm2 := NewVM(), NewVM()
+		vm1.Attrs.Store("力量", ni(3))
+		vm2.Attrs.Store("力量", ni(3))
+		vm1.Config.EnableBigInt = true
+		vm2.Config.EnableBigInt = true
+		err1, err2 := vm1.Exec(prog), vm2.Exec(prog2)
+		assert.Equal(t, err1, err2, expr)
+		assert.Equal(t, vm1.Ret.ToString(), vm2.Ret.ToString(), expr)
+		assert.Equal(t, vm1.RestInput, vm2.RestInput, expr)
+		assert.Equal(t, vm1.GetDetailText(), vm2.GetDetailText(), expr)
+	}
+
+	// 规范化后的位置信息同样会被保存
+	prog, _ := Compile("ｄ１＋１", RollConfig{InputNormalizer: NormalizeFullWidth})
+	data, _ := prog.MarshalBinary()
+	prog2 := &Program{}
+	if assert.NoError(t, prog2.UnmarshalBinary(data)) {
+		vm := NewVM()
+		if assert.NoError(t, vm.Exec(prog2)) {
+			assert.Equal(t, "ｄ１＋１", vm.Matched)
+			assert.Equal(t, "ｄ１＋１", prog2.Source())
+		}
+	}
+}
+
+func TestProgramBinaryInvalid(t *testing.T) {
+	prog, _ := Compile("1 + 2", RollConfig{})
+	data, _ := prog.MarshalBinary()
+
+	p := &Program{}
+	assert.Error(t, p.UnmarshalBinary(nil))
+	assert.Error(t, p.UnmarshalBinary([]byte("abc")))
+	assert.Error(t, p.UnmarshalBinary(data[:len(data)-1]))
+	assert.Error(t, p.UnmarshalBinary(append(append([]byte{}, data...), 0)))
+
+	// 版本不一致
+	old := append([]byte{}, data...)
+	old[len(bytecodeMagic)] = bytecodeFormatVersion + 1
+	assert.ErrorIs(t, p.UnmarshalBinary(old), ErrBytecodeVersion)
+
+	// 语法摘要不一致
+	old = append([]byte{}, data...)
+	old[len(bytecodeMagic)+1] ^= 0xff
+	assert.ErrorIs(t, p.UnmarshalBinary(old), ErrBytecodeVersion)
+
+	// 指令与值的类型不符: push.str 被改为 push.int
+	prog, _ = Compile("'abc'", RollConfig{})
+	data, _ = prog.MarshalBinary()
+	i := bytes.Index(data, []byte{byte(typePushString), 0, codeValString, 3, 'a', 'b', 'c'})
+	if assert.GreaterOrEqual(t, i, 0) {
+		bad := append([]byte{}, data...)
+		bad[i] = byte(typePushIntNumber)
+		assert.ErrorIs(t, p.UnmarshalBinary(bad), errBytecodeCorrupt)
+
+		// 缓存中的数据损坏时重新编译
+		cache := &mapCodeCache{}
+		cfg := RollConfig{CodeCache: cache}
+		vm := NewVM()
+		vm.Config = cfg
+		cache.Set(vm.codeCacheKey("'abc'"), bad)
+		prog, err := Compile("'abc'", cfg)
+		if assert.NoError(t, err) {
+			assert.NoError(t, vm.Exec(prog))
+			assert.True(t, valueEqual(vm.Ret, ns("abc")))
+		}
+	}
+
+	// 跳转目标越界
+	bad := &Program{code: []ByteCode{{T: typePushIntNumber, Value: IntType(1)}, {T: typeJne, Arg: 100}}}
+	data, err := bad.MarshalBinary()
+	if assert.NoError(t, err) {
+		assert.ErrorIs(t, p.UnmarshalBinary(data), errBytecodeCorrupt)
+	}
+
+	// 自定义骰子含有回调函数，无法序列化
+	vm := NewVM()
+	vm.RegCustomDice(`E(\d+)`, func(ctx *Context, groups []string, payload any) (*VMValue, string, error) {
+		return ni(1), "", nil
+	})
+	prog, err = vm.Compile("E5")
+	if assert.NoError(t, err) {
+		_, err = prog.MarshalBinary()
+		assert.Error(t, err)
+	}
+}
+
+func TestCodeCache(t *testing.T) {
+	cache := &mapCodeCache{}
+	cfg := RollConfig{CodeCache: cache}
+
+	_, err := Compile("1d1 + 2", cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, cache.hits)
+	assert.Len(t, cache.data, 1)
+
+	prog, err := Compile("1d1 + 2", cfg)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 1, cache.hits)
+		vm := NewVM()
+		if assert.NoError(t, vm.Exec(prog)) {
+			assert.True(t, valueEqual(vm.Ret, ni(3)))
+		}
+	}
+
+	// 影响解析的配置不同时不会命中
+	cfg.DisableNDice = true
+	_, _ = Compile("1d1 + 2", cfg)
+	assert.Equal(t, 1, cache.hits)
+	assert.Len(t, cache.data, 2)
+
+	// 损坏的缓存会被重新编译并覆盖
+	for k := range cache.data {
+		cache.data[k] = []byte("bad")
+	}
+	prog, err = Compile("1d1 + 2", cfg)
+	if assert.NoError(t, err) {
+		vm := NewVM()
+		assert.NoError(t, vm.Exec(prog))
+	}
+
+	// 反序列化得到的计算类型和函数在首次调用时编译，经过缓存
+	vm := NewVM()
+	vm.Config.CodeCache = cache
+	data, _ := json.Marshal(NewFunctionValRaw(&FunctionData{Expr: "a + 1", Name: "f", Params: []string{"a"}}))
+	f, err := VMValueFromJSON(data)
+	if assert.NoError(t, err) {
+		gets := cache.gets
+		vm.Attrs.Store("f", f)
+		if assert.NoError(t, vm.Run("f(1) + f(2)")) {
+			assert.True(t, valueEqual(vm.Ret, ni(5)))
+		}
+		assert.Equal(t, gets+1, cache.gets)
+	}
+}
@@ -0,0 +1,141 @@
+package dicescript
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrCanceled 执行被取消或超时，可以用 errors.Is 判断。
+// 具体原因可以继续用 errors.Is 与 context.Canceled、context.DeadlineExceeded 比较
+var ErrCanceled = errors.New("执行已取消")
+
+// 执行循环每隔多少条指令检查一次取消信号，解析时每隔多少个表达式检查一次
+const (
+	cancelCheckOps   = 256
+	cancelCheckExprs = 1024
+)
+
+type canceledError struct {
+	cause error
+}
+
+func (e *canceledError) Error() string {
+	if errors.Is(e.cause, context.DeadlineExceeded) {
+		return "执行超时"
+	}
+	return ErrCanceled.Error()
+}
+
+func (e *canceledError) Is(target error) bool {
+	return target == ErrCanceled
+}
+
+func (e *canceledError) Unwrap() error {
+	return e.cause
+}
+
+// checkCanceled 非阻塞地检查取消信号，已取消时返回对应错误
+func (ctx *Context) checkCanceled() error {
+	if ctx.runCtx == nil {
+		return nil
+	}
+	select {
+	case <-ctx.runCtx.Done():
+		return &canceledError{cause: ctx.runCtx.Err()}
+	default:
+		return nil
+	}
+}
+
+// StdContext 本次执行使用的 context.Context，原生函数和自定义骰子中的耗时操作可以借此响应取消。
+// 不在执行中或未设置取消信号时返回 context.Background()
+func (ctx *Context) StdContext() context.Context {
+	if ctx.runCtx == nil {
+		return context.Background()
+	}
+	return ctx.runCtx
+}
+
+// beginRun 设置本次执行的取消信号，返回值交给 endRun 恢复。
+// 函数、计算类型等子上下文已经沿用了外层的信号，此时不再叠加 Timeout
+func (ctx *Context) beginRun(c context.Context) (old context.Context, cancel context.CancelFunc) {
+	old = ctx.runCtx
+	if old != nil && c.Done() == nil {
+		return old, nil
+	}
+	if old == nil && ctx.Config.Timeout > 0 {
+		c, cancel = context.WithTimeout(c, ctx.Config.Timeout)
+	}
+	if c.Done() == nil {
+		// 不可取消，执行时跳过检查
+		c = nil
+	}
+	ctx.runCtx = c
+	return old, cancel
+}
+
+func (ctx *Context) endRun(old context.Context, cancel context.CancelFunc) {
+	if cancel != nil {
+		cancel()
+	}
+	ctx.runCtx = old
+}
+
+// RunContext 执行给定语句，c 被取消或超过 Config.Timeout 时中止执行并返回 ErrCanceled
+func (ctx *Context) RunContext(c context.Context, value string) error {
+	if ctx.IsRunning {
+		return errors.New("正在执行中，无法执行新的语句")
+	}
+	defer ctx.endRun(ctx.beginRun(c))
+	ctx.resetModules()
+
+	if err := ctx.Parse(value); err != nil {
+		return err
+	}
+	return ctx.RunAfterParsed()
+}
+
+// ExecContext 执行编译好的程序，取消规则与 RunContext 相同
+func (ctx *Context) ExecContext(c context.Context, prog *Program) error {
+	if ctx.IsRunning {
+		return errors.New("正在执行中，无法执行新的语句")
+	}
+	defer ctx.endRun(ctx.beginRun(c))
+	ctx.resetModules()
+	return ctx.exec(prog)
+}
+
+// checkCanceled 解析期间由语法中的谓词调用，每解析 cancelCheckExprs 个表达式检查一次取消信号，
+// 已取消时中止解析，由 parseGuarded 转为错误。总是返回 true，不影响匹配
+func (d *ParserCustomData) checkCanceled(exprCnt uint64) bool {
+	if d.ctx == nil || exprCnt-d.cancelCheckedAt < cancelCheckExprs {
+		return true
+	}
+	d.cancelCheckedAt = exprCnt
+	if err := d.ctx.checkCanceled(); err != nil {
+		panic(err)
+	}
+	return true
+}
+
+// parseGuarded 执行解析，把解析中途因表达式数量上限或取消而中止的 panic 转为错误返回
+func parseGuarded(p *parser) (err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			if e2, ok := e.(error); ok {
+				if errors.Is(e2, errMaxExprCnt) {
+					err = fmt.Errorf("解析的表达式数量超出上限 %d: %w", p.maxExprCnt, e2)
+					return
+				}
+				if errors.Is(e2, ErrCanceled) {
+					err = e2
+					return
+				}
+			}
+			panic(e)
+		}
+	}()
+	_, err = p.parse(nil)
+	return err
+}
@@ -0,0 +1,102 @@
+package dicescript
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunContextCanceled(t *testing.T) {
+	c, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	vm := NewVM()
+	err := vm.RunContext(c, "1 + 1")
+	assert.ErrorIs(t, err, ErrCanceled)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, "执行已取消", err.Error())
+
+	// 取消只对本次执行有效
+	err = vm.Run("1 + 1")
+	if assert.NoError(t, err) {
+		assert.True(t, valueEqual(vm.Ret, ni(2)))
+	}
+
+	prog, err := Compile("d20", RollConfig{})
+	if assert.NoError(t, err) {
+		err = vm.ExecContext(c, prog)
+		assert.ErrorIs(t, err, ErrCanceled)
+	}
+
+	// 解析较长的表达式时也会检查
+	vm = NewVM()
+	assert.NoError(t, vm.Parse(strings.Repeat("1+", 2000)+"1"))
+	err = vm.RunContext(c, strings.Repeat("1+", 2000)+"1")
+	assert.ErrorIs(t, err, ErrCanceled)
+}
+
+func TestRunTimeout(t *testing.T) {
+	vm := NewVM()
+	vm.Config.Timeout = 50 * time.Millisecond
+	start := time.Now()
+	err := vm.Run("while 1 {}")
+	assert.ErrorIs(t, err, ErrCanceled)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, "执行超时", err.Error())
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	// 不能被 try 捕获
+	err = vm.Run("n = 0; while 1 { try { while 1 {} } catch (e) { n += 1 } }")
+	assert.ErrorIs(t, err, ErrCanceled)
+	v, _ := vm.Attrs.Load("n")
+	assert.True(t, valueEqual(v, ni(0)))
+
+	// 函数调用、计算类型与原生函数回调中同样生效
+	err = vm.Run("func f(n) { f(n + 1) }; f(0)")
+	assert.ErrorIs(t, err, ErrCanceled)
+	err = vm.Run("func g() { while 1 {} }; &c = g(); c")
+	assert.ErrorIs(t, err, ErrCanceled)
+
+	vm = NewVM()
+	vm.Config.Timeout = 50 * time.Millisecond
+	vm.Attrs.Store("apply", NewNativeFunctionVal(&NativeFunctionData{
+		Name:   "apply",
+		Params: []string{"fn"},
+		NativeFunc: func(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
+			return params[0].FuncInvoke(ctx, nil)
+		},
+	}))
+	err = vm.Run("func f() { while 1 {} }; apply(f)")
+	assert.ErrorIs(t, err, ErrCanceled)
+}
+
+func TestRunContextNative(t *testing.T) {
+	// 原生函数可以通过 StdContext 响应取消
+	vm := NewVM()
+	vm.Attrs.Store("wait", NewNativeFunctionVal(&NativeFunctionData{
+		Name:   "wait",
+		Params: []string{},
+		NativeFunc: func(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
+			<-ctx.StdContext().Done()
+			ctx.Error = errors.New("等待被中断")
+			return nil
+		},
+	}))
+
+	c, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := vm.RunContext(c, "func f() { wait() }; f()")
+	assert.EqualError(t, err, "等待被中断")
+	assert.Equal(t, context.Background(), vm.StdContext())
+}
+
+func TestParseExprLimitError(t *testing.T) {
+	vm := NewVM()
+	vm.Config.ParseExprLimit = 100
+	err := vm.Run(strings.Repeat("1+", 100) + "1")
+	assert.ErrorIs(t, err, errMaxExprCnt)
+}
@@ -115,10 +115,19 @@ declare interface RollConfig {
   DisableStmts: boolean;
   DisableNDice: boolean;
 
+  EnableBoolType: boolean;
+  EnableBigInt: boolean;
+  EnableChineseKeywords: boolean;
+  EnableOptimizer: boolean;
+
   CallbackLoadVar: (name: string) => [string, VMValue];
   CallbackSt: (type: string, name: string, val: VMValue, extra: VMValue, op: string, detail: string) => void;
 
   OpCountLimit: number;
+  MemoryLimit: number;
+  MaxCallDepth: number;
+  MaxNestingDepth: number;
+  Timeout: number;
   DefaultDiceSideExpr: string;
   defaultDiceSideExprCacheFunc: VMValue;
 
@@ -152,6 +161,7 @@ export declare interface DiceScriptContext {
   stack: VMValue[];
   top: number;
   NumOpCount: number;
+  MemoryUsed: number;
   Error: GoError;
   Ret: VMValue | null;
   RestInput: string;
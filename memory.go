@@ -0,0 +1,53 @@
+package dicescript
+
+import "errors"
+
+var errMemoryLimit = errors.New("超出内存上限: 创建的字符串、数组或字典过大")
+
+// 数组元素与字典条目按固定大小估算，字符串按实际字节数计算
+const (
+	memSizeArrayItem IntType = 32
+	memSizeDictItem  IntType = 64
+)
+
+// memoryAlloc 记录新建 n 个大小为 size 的单元，超出 Config.MemoryLimit 时设置错误并返回 false。
+// 需要在实际分配前调用，以免单条指令就占满内存
+func (ctx *Context) memoryAlloc(n IntType, size IntType) bool {
+	if ctx == nil || n <= 0 {
+		return true
+	}
+	total, ok := intMul(n, size)
+	if ok {
+		total, ok = intAdd(ctx.MemoryUsed, total)
+	}
+	if !ok {
+		total = maxIntType
+	}
+	ctx.MemoryUsed = total
+	if ctx.Config.MemoryLimit > 0 && total > ctx.Config.MemoryLimit {
+		ctx.Error = errMemoryLimit
+		return false
+	}
+	return true
+}
+
+func (ctx *Context) allocString(length int) bool {
+	return ctx.memoryAlloc(IntType(length), 1)
+}
+
+func (ctx *Context) allocArray(n IntType) bool {
+	return ctx.memoryAlloc(n, memSizeArrayItem)
+}
+
+func (ctx *Context) allocDict(n IntType) bool {
+	return ctx.memoryAlloc(n, memSizeDictItem)
+}
+
+// dictStore 写入字典，新增的条目计入内存
+func (ctx *Context) dictStore(d *VMDictValue, key string, val *VMValue) bool {
+	if _, exists := d.Load(key); !exists && !ctx.allocDict(1) {
+		return false
+	}
+	d.Store(key, val)
+	return true
+}
@@ -0,0 +1,76 @@
+package dicescript
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryUsed(t *testing.T) {
+	vm := NewVM()
+	err := vm.Run("[1, 2, 3]")
+	if assert.NoError(t, err) {
+		assert.Equal(t, 3*memSizeArrayItem, vm.MemoryUsed)
+	}
+
+	err = vm.Run("'ab' + 'cd'")
+	if assert.NoError(t, err) {
+		assert.Equal(t, IntType(4), vm.MemoryUsed)
+	}
+
+	// 函数调用与计算类型中的分配计入调用方
+	err = vm.Run("func f() { [1, 2] }; &c = {'a': 1}; f(); f(); c")
+	if assert.NoError(t, err) {
+		assert.Equal(t, 4*memSizeArrayItem+memSizeDictItem, vm.MemoryUsed)
+	}
+
+	// 分片与解构的剩余项是新建的数组、字典
+	err = vm.Run("a = [1, 2, 3]; s = 'abc'; a[1:]; s[:2]")
+	if assert.NoError(t, err) {
+		assert.Equal(t, 5*memSizeArrayItem+2, vm.MemoryUsed)
+	}
+
+	err = vm.Run("[x, ...r] = [1, 2, 3]; {a, ...o} = {'a': 1, 'b': 2, 'c': 3}")
+	if assert.NoError(t, err) {
+		assert.Equal(t, 5*memSizeArrayItem+5*memSizeDictItem, vm.MemoryUsed)
+	}
+
+	err = vm.Run("[1] * -1")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "[]", vm.Ret.ToString())
+	}
+}
+
+func TestMemoryLimit(t *testing.T) {
+	vm := NewVM()
+	vm.Config.MemoryLimit = 100000
+
+	err := vm.Run("s = 'a'; while 1 { s = s + s }")
+	assert.ErrorIs(t, err, errMemoryLimit)
+	assert.Greater(t, vm.MemoryUsed, vm.Config.MemoryLimit)
+
+	err = vm.Run("a = []; while 1 { a.push(1) }")
+	assert.ErrorIs(t, err, errMemoryLimit)
+
+	err = vm.Run("m = {}; i = 0; while 1 { m[toStr(i)] = i; i += 1 }")
+	assert.ErrorIs(t, err, errMemoryLimit)
+
+	err = vm.Run("s = 'abcdefghij'.repeat(1000); while 1 { `{s}{s}` }")
+	assert.ErrorIs(t, err, errMemoryLimit)
+
+	err = vm.Run("func f(s) { s.upper() }; s = 'abcdefghij'.repeat(1000); while 1 { f(s) }")
+	assert.ErrorIs(t, err, errMemoryLimit)
+
+	err = vm.Run("a = [1..500]; while 1 { a[1:] }")
+	assert.ErrorIs(t, err, errMemoryLimit)
+
+	err = vm.Run("s = 'abcdefghij'.repeat(1000); while 1 { s[1:] }")
+	assert.ErrorIs(t, err, errMemoryLimit)
+
+	err = vm.Run("a = [1..500]; while 1 { [x, ...r] = a }")
+	assert.ErrorIs(t, err, errMemoryLimit)
+
+	// 不能被 try 捕获
+	err = vm.Run("while 1 { try { [1..10] } catch (e) { } }")
+	assert.ErrorIs(t, err, errMemoryLimit)
+}
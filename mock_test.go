@@ -6,7 +6,7 @@ import "testing"
 // 因此这个文件用来水掉没意义的函数
 
 func TestMockByteCodeString(t *testing.T) {
-	for i := 0; i < 87; i++ {
+	for i := 0; i < 108; i++ {
 		c := &ByteCode{T: CodeType(i), Value: IntType(1)}
 		switch c.T {
 		case typePushFloatNumber:
@@ -17,10 +17,20 @@ func TestMockByteCodeString(t *testing.T) {
 			c.Value = NewComputedVal("1")
 		case typePushFunction:
 			c.Value = NewFunctionValRaw(&FunctionData{Expr: "1"})
-		case typeLoadName, typeLoadNameWithDetail, typeLoadNameRaw, typeInvokeSelf, typeAttrSet, typeAttrGet:
+		case typeLoadName, typeLoadNameWithDetail, typeLoadNameRaw, typeInvokeSelf:
 			c.Value = "name"
-		case typeDetailMark:
+		case typeAttrSet, typeAttrGet:
+			c.Value = AttrInfo{Name: "name"}
+		case typeDetailMark, typeThrow:
 			c.Value = BufferSpan{}
+		case typeUnpackArray, typeUnpackDict:
+			c.Value = UnpackInfo{Count: 1, RestIndex: -1, Keys: []string{"name"}}
+		case typeImport:
+			c.Value = ImportInfo{Module: "name", Keys: []string{"name"}}
+		case typeInvokeNamed:
+			c.Value = InvokeNamedInfo{Num: 1, Names: []string{"name"}}
+		case typeStoreNameOp, typeAttrSetOp, typeItemSetOp:
+			c.Value = InplaceInfo{Name: "name", Op: typeAdd}
 		}
 		_ = c.CodeString()
 	}
@@ -0,0 +1,163 @@
+package dicescript
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// ModuleLoader 根据模块名返回模块源码，由宿主实现
+type ModuleLoader func(name string) (string, error)
+
+// moduleCache 已导入的模块，在同一次执行产生的所有上下文之间共享，每次执行开始时清空
+type moduleCache struct {
+	loaded  map[string]*VMValue
+	loading []string // 正在导入的模块，用于检测循环导入
+}
+
+func (ctx *Context) getModuleCache() *moduleCache {
+	if ctx.modules == nil {
+		ctx.modules = &moduleCache{loaded: map[string]*VMValue{}}
+	}
+	return ctx.modules
+}
+
+// 编译好的模块最多缓存的个数，超出时整体清空
+const maxCompiledModules = 256
+
+// compiledModules 编译好的模块，按源码和影响解析的配置缓存，在所有上下文之间共享
+var compiledModules = struct {
+	sync.Mutex
+	progs map[string]*Program
+}{progs: map[string]*Program{}}
+
+// compileModule 编译模块源码，相同的源码和配置只解析一次
+func (ctx *Context) compileModule(source string) (*Program, error) {
+	vm := NewVM()
+	vm.Config = ctx.Config
+	vm.runCtx = ctx.runCtx
+
+	key := vm.compileKey(source)
+	if key != "" {
+		compiledModules.Lock()
+		prog := compiledModules.progs[key]
+		compiledModules.Unlock()
+		if prog != nil {
+			return prog, nil
+		}
+	}
+
+	prog, err := vm.compileCached(source)
+	if err != nil || key == "" {
+		return prog, err
+	}
+	compiledModules.Lock()
+	if len(compiledModules.progs) >= maxCompiledModules {
+		compiledModules.progs = map[string]*Program{}
+	}
+	compiledModules.progs[key] = prog
+	compiledModules.Unlock()
+	return prog, nil
+}
+
+// resetModules 顶层执行开始时清空已导入的模块，模块中的变量不会带到下一次执行。
+// 函数、计算类型等子上下文沿用外层的模块
+func (ctx *Context) resetModules() {
+	if ctx.subThreadDepth == 0 {
+		ctx.modules = nil
+	}
+}
+
+// ImportModule 导入模块，返回由模块顶层变量组成的字典。同一次执行中每个模块只会执行一次
+func (ctx *Context) ImportModule(name string) *VMValue {
+	mc := ctx.getModuleCache()
+	if ns, ok := mc.loaded[name]; ok {
+		return ns
+	}
+	for i, n := range mc.loading {
+		if n == name {
+			chain := append(append([]string{}, mc.loading[i:]...), name)
+			ctx.Error = newRuntimeError(ErrCodeImport, "import", fmt.Errorf("循环导入: %s", strings.Join(chain, " -> ")))
+			return nil
+		}
+	}
+
+	if ctx.Config.ModuleLoader == nil {
+		ctx.Error = newRuntimeError(ErrCodeImport, "import", fmt.Errorf("未设置 ModuleLoader，无法导入模块 %s", name))
+		return nil
+	}
+	source, err := ctx.Config.ModuleLoader(name)
+	if err != nil {
+		ctx.Error = newRuntimeError(ErrCodeImport, "import", fmt.Errorf("导入模块 %s 失败: %w", name, err))
+		return nil
+	}
+
+	vm := NewVM()
+	vm.Config = ctx.Config
+	vm.GlobalValueStoreFunc = ctx.GlobalValueStoreFunc
+	vm.GlobalValueLoadFunc = ctx.GlobalValueLoadFunc
+	vm.GlobalValueLoadOverwriteFunc = ctx.GlobalValueLoadOverwriteFunc
+	vm.subThreadDepth = ctx.subThreadDepth + 1
+	vm.RandSrc = ctx.RandSrc
+	vm.CustomFlag = ctx.CustomFlag
+	vm.runCtx = ctx.runCtx
+	vm.modules = mc
+
+	mc.loading = append(mc.loading, name)
+	prog, err := ctx.compileModule(source)
+	if err == nil {
+		// 模块的执行计入导入方的算力与内存
+		vm.load(prog)
+		vm.NumOpCount = ctx.NumOpCount
+		vm.MemoryUsed = ctx.MemoryUsed
+		err = vm.RunAfterParsed()
+		ctx.NumOpCount = vm.NumOpCount
+		ctx.MemoryUsed = vm.MemoryUsed
+	}
+	mc.loading = mc.loading[:len(mc.loading)-1]
+	if err == nil && vm.RestInput != "" {
+		err = fmt.Errorf("无法解析的内容: %s", vm.RestInput)
+	}
+	if err != nil {
+		err = fmt.Errorf("导入模块 %s 失败: %w", name, err)
+		// 模块执行中的错误保留原本的错误码与位置，其余为导入错误
+		var re *RuntimeError
+		if !errors.As(err, &re) {
+			err = newRuntimeError(ErrCodeImport, "import", err)
+		}
+		ctx.Error = err
+		return nil
+	}
+
+	// 模块的顶层变量即为其命名空间，模块中定义的函数在调用时仍能访问这些变量。
+	// 函数数据来自共享的编译结果，绑定在副本上，每次导入各自指向本次的模块
+	var bound []string
+	vm.Attrs.Range(func(key string, value *VMValue) bool {
+		if fd, ok := value.ReadFunctionData(); ok && fd.module == nil {
+			bound = append(bound, key)
+		}
+		return true
+	})
+	for _, key := range bound {
+		value, _ := vm.Attrs.Load(key)
+		fd, _ := value.ReadFunctionData()
+		fd2 := *fd
+		fd2.module = vm
+		vm.Attrs.Store(key, NewFunctionValRaw(&fd2))
+	}
+	ns := NewDictVal(vm.Attrs).V()
+	mc.loaded[name] = ns
+	return ns
+}
+
+// isIdentifier 模块名能否直接作为变量名
+func isIdentifier(name string) bool {
+	for i, r := range name {
+		if !(r == '_' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r))) {
+			return false
+		}
+	}
+	return name != ""
+}
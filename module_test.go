@@ -0,0 +1,153 @@
+package dicescript
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newModuleVM(modules map[string]string, loads *int) *Context {
+	vm := NewVM()
+	vm.Config.ModuleLoader = func(name string) (string, error) {
+		if loads != nil {
+			*loads++
+		}
+		if src, ok := modules[name]; ok {
+			return src, nil
+		}
+		return "", errors.New("找不到模块")
+	}
+	return vm
+}
+
+var testModules = map[string]string{
+	"coc7":      "base = 50\nfunc helper(x) { x * 2 }\nfunc cocCheck(v) { helper(v) + base }\ncount = 0",
+	"rules/dnd": "lvl = 3",
+	"a":         "import \"b\"\nx = 1",
+	"b":         "import \"a\"\ny = 1",
+	"loop":      "i = 0; while i < 200 { i += 1 }",
+}
+
+func TestImport(t *testing.T) {
+	vm := newModuleVM(testModules, nil)
+	err := vm.Run(`import "coc7"; coc7.cocCheck(5)`)
+	if assert.NoError(t, err) {
+		assert.True(t, valueEqual(vm.Ret, ni(60)))
+	}
+
+	vm = newModuleVM(testModules, nil)
+	err = vm.Run(`import {cocCheck, base as b} from "coc7"; [cocCheck(1), b]`)
+	if assert.NoError(t, err) {
+		assert.True(t, valueEqual(vm.Ret, na(ni(52), ni(50))))
+	}
+
+	vm = newModuleVM(testModules, nil)
+	err = vm.Run(`import 'rules/dnd' as dnd; dnd.lvl`)
+	if assert.NoError(t, err) {
+		assert.True(t, valueEqual(vm.Ret, ni(3)))
+	}
+
+	vm = newModuleVM(testModules, nil)
+	err = vm.Run(`func f() { import 'coc7'; coc7.base }; f()`)
+	if assert.NoError(t, err) {
+		assert.True(t, valueEqual(vm.Ret, ni(50)))
+	}
+}
+
+func TestImportCached(t *testing.T) {
+	loads := 0
+	vm := newModuleVM(testModules, &loads)
+	err := vm.Run(`import 'coc7'; import 'coc7' as c2; c2.count = 5; coc7.count`)
+	if assert.NoError(t, err) {
+		assert.True(t, valueEqual(vm.Ret, ni(5)))
+		assert.Equal(t, 1, loads)
+	}
+
+	// 下一次执行重新导入，上一次的修改不会保留
+	err = vm.Run(`import 'coc7'; coc7.count`)
+	if assert.NoError(t, err) {
+		assert.True(t, valueEqual(vm.Ret, ni(0)))
+		assert.Equal(t, 2, loads)
+	}
+	err = vm.Run(`import {cocCheck} from 'coc7'; cocCheck(1)`)
+	if assert.NoError(t, err) {
+		assert.True(t, valueEqual(vm.Ret, ni(52)))
+	}
+
+	// 编译结果在上下文之间共享
+	vm2 := newModuleVM(testModules, nil)
+	key := vm2.compileKey(testModules["coc7"])
+	compiledModules.Lock()
+	prog := compiledModules.progs[key]
+	compiledModules.Unlock()
+	if assert.NotNil(t, prog) {
+		p2, err := vm2.compileModule(testModules["coc7"])
+		assert.NoError(t, err)
+		assert.Same(t, prog, p2)
+	}
+}
+
+func TestImportFunctionBinding(t *testing.T) {
+	modules := map[string]string{"m": "base = d1000000; func get() { base }"}
+	check := func(vm *Context) {
+		err := vm.Run(`import {get, base} from "m"; [base, get()]`)
+		if assert.NoError(t, err) {
+			arr, _ := vm.Ret.ReadArray()
+			assert.True(t, valueEqual(arr.List[0], arr.List[1]), vm.Ret.ToString())
+		}
+	}
+
+	// 模块函数访问的是本次导入的模块变量
+	vm := newModuleVM(modules, nil)
+	for i := 0; i < 3; i++ {
+		check(vm)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			check(newModuleVM(modules, nil))
+		}()
+	}
+	wg.Wait()
+}
+
+func TestImportError(t *testing.T) {
+	tests := map[string]string{
+		`import {nope} from 'coc7'`: "导入失败: 模块 coc7 中没有 nope",
+		`import "a"`:                "导入模块 a 失败: 导入模块 b 失败: 循环导入: a -> b -> a",
+		`import "missing"`:          "导入模块 missing 失败: 找不到模块",
+	}
+	for expr, msg := range tests {
+		vm := newModuleVM(testModules, nil)
+		err := vm.Run(expr)
+		if assert.Error(t, err, expr) {
+			assert.Equal(t, msg, err.Error())
+		}
+	}
+
+	vm := newModuleVM(testModules, nil)
+	err := vm.Run(`import "rules/dnd"`)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `模块名 rules/dnd 不能作为变量名`)
+	}
+
+	vm = NewVM()
+	err = vm.Run(`import "coc7"`)
+	if assert.Error(t, err) {
+		assert.Equal(t, "未设置 ModuleLoader，无法导入模块 coc7", err.Error())
+	}
+}
+
+func TestImportOpCountLimit(t *testing.T) {
+	vm := newModuleVM(testModules, nil)
+	vm.Config.OpCountLimit = 300
+	err := vm.Run(`import "loop"`)
+	if assert.Error(t, err) {
+		assert.ErrorIs(t, err, errOpCountLimit)
+	}
+}
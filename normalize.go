@@ -0,0 +1,77 @@
+package dicescript
+
+import (
+	"unicode/utf8"
+)
+
+// InputNormalizer 解析前对输入逐字符进行替换，返回值为替换后的字符。
+// 只能一对一替换字符，这样报错中的行号、列号仍与原文一致
+type InputNormalizer func(r rune) rune
+
+// NormalizeFullWidth 默认的输入规范化规则：全角字母、数字、符号(包括 ，：；（）＝ ｄ 等)转为半角，全角空格转为普通空格
+func NormalizeFullWidth(r rune) rune {
+	switch {
+	case r >= 0xFF01 && r <= 0xFF5E:
+		return r - 0xFEE0
+	case r == 0x3000:
+		return ' '
+	}
+	return r
+}
+
+// normalizeInput 对输入进行规范化，字符串字面量中的内容保持不变。
+// 同时返回规范化后每个字节对应的原文偏移，最后一项为原文长度
+func normalizeInput(text string, fn InputNormalizer) (string, []int) {
+	buf := make([]byte, 0, len(text))
+	offsets := make([]int, 0, len(text)+1)
+	var quote rune // 当前所在字符串的引号，为0时不在字符串中
+	escaped := false
+
+	for i, r := range text {
+		n := r
+		switch {
+		case quote != 0:
+			if escaped {
+				escaped = false
+			} else if r == '\\' {
+				escaped = true
+			} else if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`' || r == '\x1e':
+			quote = r
+		default:
+			n = fn(r)
+		}
+
+		before := len(buf)
+		buf = utf8.AppendRune(buf, n)
+		for j := before; j < len(buf); j++ {
+			offsets = append(offsets, i)
+		}
+	}
+	offsets = append(offsets, len(text))
+	return string(buf), offsets
+}
+
+// sourceData 用户输入的原文，未开启输入规范化时即为解析的文本
+func (ctx *Context) sourceData() []byte {
+	if ctx.source != nil {
+		return ctx.source
+	}
+	return ctx.parser.data
+}
+
+// sourceOffset 将解析文本中的偏移换算为原文中的偏移
+func (ctx *Context) sourceOffset(offset int) int {
+	if ctx.sourceOffsets == nil || offset < 0 || offset >= len(ctx.sourceOffsets) {
+		return offset
+	}
+	return ctx.sourceOffsets[offset]
+}
+
+func (ctx *Context) sourceSpan(span BufferSpan) BufferSpan {
+	span.Begin = IntType(ctx.sourceOffset(int(span.Begin)))
+	span.End = IntType(ctx.sourceOffset(int(span.End)))
+	return span
+}
@@ -0,0 +1,62 @@
+package dicescript
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeInput(t *testing.T) {
+	text, offsets := normalizeInput("１＋'，'", NormalizeFullWidth)
+	assert.Equal(t, "1+'，'", text)
+	assert.Equal(t, []int{0, 3, 6, 7, 7, 7, 10, 11}, offsets)
+}
+
+func TestInputNormalizer(t *testing.T) {
+	tests := map[string]*VMValue{
+		"１＋２＊３":      ni(7),
+		"1 ＝＝ 1":     ni(1),
+		"[1，2，3]":    na(ni(1), ni(2), ni(3)),
+		"ｄ１＋ｄ１":      ni(2),
+		"a ＝ 3；a":    ni(3),
+		"'你好，世界'":    ns("你好，世界"),
+		"（１＋２）＊２":    ni(6),
+		"{'a'： 1}.a": ni(1),
+		"１　＋　１":      ni(2),
+	}
+	for expr, ret := range tests {
+		vm := NewVM()
+		vm.Config.InputNormalizer = NormalizeFullWidth
+		err := vm.Run(expr)
+		if assert.NoError(t, err, expr) {
+			assert.True(t, valueEqual(vm.Ret, ret), expr)
+		}
+	}
+
+	vm := NewVM()
+	err := vm.Run("１＝＝１")
+	assert.Error(t, err)
+}
+
+func TestInputNormalizerOffsets(t *testing.T) {
+	vm := NewVM()
+	vm.Config.InputNormalizer = NormalizeFullWidth
+	vm.Attrs.Store("力量", ni(3))
+	err := vm.Run("２ｄ１ ＋ 力量　剩余")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "２ｄ１ ＋ 力量", vm.Matched)
+		assert.Equal(t, "　剩余", vm.RestInput)
+		assert.Equal(t, len("２ｄ１ ＋ 力量　"), vm.GetParsedOffset())
+		assert.Equal(t, "2[２ｄ１=1+1] ＋ 3[力量]", vm.GetDetailText())
+		assert.Equal(t, IntType(0), vm.DetailSpans[0].Begin)
+		assert.Equal(t, IntType(len("２ｄ１")), vm.DetailSpans[0].End)
+	}
+
+	vm = NewVM()
+	vm.Config.InputNormalizer = NormalizeFullWidth
+	err = vm.Run("ｘ ＝ １；try { throw 'x' } catch (e) { p = e.pos }; p")
+	if assert.NoError(t, err) {
+		begin := IntType(len("ｘ ＝ １；try { "))
+		assert.Equal(t, na(ni(begin), ni(begin+IntType(len("throw 'x'")))).ToRepr(), vm.Ret.ToRepr())
+	}
+}
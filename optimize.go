@@ -0,0 +1,339 @@
+package dicescript
+
+// 字节码优化，由 RollConfig.EnableOptimizer 开启，在解析完成后进行。
+// 只做与运行时配置无关的变换，优化前后的计算结果、计算过程和报错均保持一致:
+// 常量折叠、常量条件跳转、跳转链合并、删除不可达代码和 nop
+
+// 折叠得到的字符串长度上限，以免 "a" * 100000 这样的常量撑大字节码
+const optimizeMaxStringLen = 1024
+
+// 优化最多重复的轮数，每一轮的结果可能为下一轮带来新的优化机会
+const optimizeMaxPasses = 8
+
+func isJumpCode(t CodeType) bool {
+	switch t {
+	case typeJmp, typeJe, typeJne, typeJeDup, typeJeNull, typeTryBegin:
+		return true
+	}
+	return false
+}
+
+// jumpTarget 跳转的目标位置，跳转的值为相对下一条指令的偏移
+func jumpTarget(code []ByteCode, index int) int {
+	return index + 1 + int(code[index].Arg)
+}
+
+func setJumpTarget(code []ByteCode, index int, target int) {
+	code[index].Arg = IntType(target - index - 1)
+}
+
+// optimizeCode 优化一段字节码并返回新的字节码，其中的函数和计算类型也会一并优化
+func optimizeCode(code []ByteCode) []ByteCode {
+	code = append([]ByteCode(nil), code...)
+	optimizeNested(code)
+	for i := 0; i < optimizeMaxPasses; i++ {
+		changed := foldConstants(code)
+		changed = threadJumps(code) || changed
+		changed = removeUnreachable(code) || changed
+		var removed bool
+		code, removed = removeNops(code)
+		if !changed && !removed {
+			break
+		}
+	}
+	return code
+}
+
+func optimizeNested(code []ByteCode) {
+	var optimizeFunc func(fd *FunctionData)
+	optimizeFunc = func(fd *FunctionData) {
+		if fd.code != nil {
+			fd.code = optimizeCode(fd.code[:fd.codeIndex])
+			fd.codeIndex = len(fd.code)
+		}
+		for _, d := range fd.Defaults {
+			if d != nil {
+				if dd, ok := d.ReadFunctionData(); ok {
+					optimizeFunc(dd)
+				}
+			}
+		}
+	}
+
+	for _, c := range code {
+		switch c.T {
+		case typePushComputed:
+			if cd, ok := c.Value.(*VMValue).ReadComputed(); ok && cd.code != nil {
+				cd.code = optimizeCode(cd.code[:cd.codeIndex])
+				cd.codeIndex = len(cd.code)
+			}
+		case typePushFunction:
+			if fd, ok := c.Value.(*VMValue).ReadFunctionData(); ok {
+				optimizeFunc(fd)
+			}
+		}
+	}
+}
+
+// jumpTargets 标记所有跳转目标，折叠时不能越过它们
+func jumpTargets(code []ByteCode) []bool {
+	targets := make([]bool, len(code)+1)
+	for i, c := range code {
+		if isJumpCode(c.T) {
+			if t := jumpTarget(code, i); t >= 0 && t <= len(code) {
+				targets[t] = true
+			}
+		}
+	}
+	return targets
+}
+
+// constPushValue 常量入栈指令的值，withNull 为 true 时 null 和布尔值也算作常量(仅用于判断真假)
+func constPushValue(c ByteCode, withNull bool) (*VMValue, bool) {
+	switch c.T {
+	case typePushIntNumber:
+		return &VMValue{TypeId: VMTypeInt, Value: c.Value}, true
+	case typePushFloatNumber:
+		return &VMValue{TypeId: VMTypeFloat, Value: c.Value}, true
+	case typePushString:
+		return &VMValue{TypeId: VMTypeString, Value: c.Value}, true
+	case typePushBool:
+		return &VMValue{TypeId: VMTypeBool, Value: c.Value}, withNull
+	case typePushNull:
+		return NewNullVal(), withNull
+	}
+	return nil, false
+}
+
+func constPushCode(v *VMValue) (ByteCode, bool) {
+	switch v.TypeId {
+	case VMTypeInt:
+		return ByteCode{T: typePushIntNumber, Value: v.Value}, true
+	case VMTypeFloat:
+		return ByteCode{T: typePushFloatNumber, Value: v.Value}, true
+	case VMTypeString:
+		if len(v.Value.(string)) <= optimizeMaxStringLen {
+			return ByteCode{T: typePushString, Value: v.Value}, true
+		}
+	}
+	return ByteCode{}, false
+}
+
+// foldConstants 折叠常量运算和以常量为条件的跳转。
+// 比较运算的结果类型取决于 EnableBoolType，不进行折叠；运算出错(如除以0、溢出)时保留原样，留到运行时报错
+func foldConstants(code []ByteCode) bool {
+	targets := jumpTargets(code)
+	changed := false
+	ctx := &Context{}
+
+	// prev 向前查找上一条有效指令，中间不能有跳转目标。
+	// block.push/block.pop 不影响数据栈，可以越过，如 if 1 {} 的条件与 jne 之间的 block.push
+	prev := func(index int) int {
+		for j := index - 1; j >= 0; j-- {
+			if targets[j+1] {
+				return -1
+			}
+			switch code[j].T {
+			case typeNop, typeBlockPush, typeBlockPop:
+			default:
+				return j
+			}
+		}
+		return -1
+	}
+	nop := ByteCode{T: typeNop}
+
+	for i, c := range code {
+		switch c.T {
+		case typeAdd, typeSubtract, typeMultiply, typeDivide, typeModulus, typeExponentiation,
+			typeBitwiseAnd, typeBitwiseOr:
+			j1 := prev(i)
+			if j1 < 0 {
+				continue
+			}
+			j2 := prev(j1)
+			if j2 < 0 {
+				continue
+			}
+			v1, ok1 := constPushValue(code[j2], false)
+			v2, ok2 := constPushValue(code[j1], false)
+			if !ok1 || !ok2 {
+				continue
+			}
+			ctx.Error = nil
+			ret := binOperator[c.T-typeAdd](v1, ctx, v2)
+			if ctx.Error != nil || ret == nil {
+				continue
+			}
+			if folded, ok := constPushCode(ret); ok {
+				code[j2], code[j1], code[i] = folded, nop, nop
+				changed = true
+			}
+
+		case typeNegation, typePositive:
+			j1 := prev(i)
+			if j1 < 0 {
+				continue
+			}
+			v, ok := constPushValue(code[j1], false)
+			if !ok {
+				continue
+			}
+			var ret *VMValue
+			if c.T == typePositive {
+				ret = v.OpPositive()
+			} else if n, isInt := v.ReadInt(); !isInt || n != minIntType {
+				ret = v.OpNegation()
+			}
+			if ret == nil {
+				continue
+			}
+			if folded, ok := constPushCode(ret); ok {
+				code[j1], code[i] = folded, nop
+				changed = true
+			}
+
+		case typeJe, typeJne, typeJeDup, typeJeNull:
+			j1 := prev(i)
+			if j1 < 0 {
+				continue
+			}
+			v, ok := constPushValue(code[j1], true)
+			if !ok {
+				continue
+			}
+			switch c.T {
+			case typeJe, typeJne:
+				// 条件出栈，常量入栈与跳转一同去掉
+				if v.AsBool() == (c.T == typeJe) {
+					code[j1], code[i] = nop, ByteCode{T: typeJmp, Arg: c.Arg}
+				} else {
+					code[j1], code[i] = nop, nop
+				}
+				changed = true
+			case typeJeDup:
+				// 条件为假时会出栈，并被之后的 push.last 取用，只处理为真的情况
+				if v.AsBool() {
+					code[i] = ByteCode{T: typeJmp, Arg: c.Arg}
+					changed = true
+				}
+			case typeJeNull:
+				if v.TypeId == VMTypeNull {
+					code[i] = ByteCode{T: typeJmp, Arg: c.Arg}
+				} else {
+					code[i] = nop
+				}
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// threadJumps 跳转到 jmp 的跳转直接指向最终目标，跳到下一条指令的 jmp 改为 nop
+func threadJumps(code []ByteCode) bool {
+	changed := false
+	for i, c := range code {
+		switch c.T {
+		case typeJmp, typeJe, typeJne, typeJeDup, typeJeNull:
+		default:
+			continue
+		}
+
+		target := jumpTarget(code, i)
+		for steps := 0; steps < len(code); steps++ {
+			for target < len(code) && code[target].T == typeNop {
+				target++
+			}
+			if target >= len(code) || code[target].T != typeJmp || target == i {
+				break
+			}
+			target = jumpTarget(code, target)
+		}
+		if target < 0 || target > len(code) {
+			continue
+		}
+
+		if c.T == typeJmp {
+			next := i + 1
+			for next < len(code) && code[next].T == typeNop {
+				next++
+			}
+			if target == next {
+				code[i] = ByteCode{T: typeNop}
+				changed = true
+				continue
+			}
+		}
+		if target != jumpTarget(code, i) {
+			setJumpTarget(code, i, target)
+			changed = true
+		}
+	}
+	return changed
+}
+
+// removeUnreachable 将执行不到的指令替换为 nop
+func removeUnreachable(code []ByteCode) bool {
+	reachable := make([]bool, len(code))
+	queue := []int{0}
+	for len(queue) > 0 {
+		i := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		if i < 0 || i >= len(code) || reachable[i] {
+			continue
+		}
+		reachable[i] = true
+
+		switch code[i].T {
+		case typeJmp:
+			queue = append(queue, jumpTarget(code, i))
+		case typeJe, typeJne, typeJeDup, typeJeNull, typeTryBegin:
+			// try.begin 的目标为 catch 代码
+			queue = append(queue, i+1, jumpTarget(code, i))
+		case typeHalt, typeReturn, typeThrow:
+		default:
+			queue = append(queue, i+1)
+		}
+	}
+
+	changed := false
+	for i := range code {
+		if !reachable[i] && code[i].T != typeNop {
+			code[i] = ByteCode{T: typeNop}
+			changed = true
+		}
+	}
+	return changed
+}
+
+// removeNops 删除 nop 并修正跳转的偏移
+func removeNops(code []ByteCode) ([]ByteCode, bool) {
+	newIndex := make([]int, len(code)+1)
+	n := 0
+	for i, c := range code {
+		newIndex[i] = n
+		if c.T != typeNop {
+			n++
+		}
+	}
+	newIndex[len(code)] = n
+	if n == len(code) {
+		return code, false
+	}
+
+	ret := make([]ByteCode, 0, n)
+	for i, c := range code {
+		if c.T == typeNop {
+			continue
+		}
+		if isJumpCode(c.T) {
+			target := jumpTarget(code, i)
+			if target >= 0 && target <= len(code) {
+				c.Arg = IntType(newIndex[target] - newIndex[i] - 1)
+			}
+		}
+		ret = append(ret, c)
+	}
+	return ret, true
+}
@@ -0,0 +1,133 @@
+package dicescript
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 优化前后对照执行的用例，覆盖常见的语法结构
+var optimizeDiffCases = []string{
+	"1 + 2 * 3",
+	"(1 + 2) * (3 + 4) - 5 / 2 % 3",
+	"-1 + -2.5 * 2",
+	"2 ^ 10 + 0x10 & 0b11 | 4",
+	"'a' + 'b' + 'c'",
+	"'ab' * 3",
+	"1.5 + 2 - 0.25",
+	"1 / 0",
+	"9223372036854775807 + 1",
+	"-(-9223372036854775807 - 1)",
+	"1 == 1",
+	"d20 + 1 + 2",
+	"3d6kh2 + (2 + 3)d4 + 力量",
+	"(1 + 1)d(2 * 3)",
+	"力量 + 2 * 3 原因",
+	"if 1 { 2 } else { 3 }; 4",
+	"if 0 { 2 } else { 3 + 1 }",
+	"if '' { 1 } else if 2 - 2 { 2 } else { 3 }",
+	"a = 0; if a { 1 } else { 2 }",
+	"1 ? 2 : 3",
+	"a = 1; a ? (a ? 1 : 2) : 3",
+	"0 ? 2 : 3",
+	"x = 1; x ? 2 : 3",
+	"1 ? 2",
+	"0 || 5",
+	"'' || 0",
+	"3 || 4",
+	"a = 0; a || 0 || 'x'",
+	"1 && 2",
+	"0 && 2",
+	"null ?? 1 + 1",
+	"x = null; x?.y?.z",
+	"x = null; x?.[1 + 1]",
+	"x = [1, 2]; x?.[0] + 1",
+	"i = 0; while i < 3 { i = i + 1 }; i",
+	"i = 0; while 1 { i += 1; if i > 2 { break } }; i",
+	"while 0 { 1 }; 5",
+	"i = 0; s = 0; while i < 5 { i += 1; if i % 2 { continue }; s += i }; s",
+	"func f(a, b = 1 + 1) { return a + b + 2 * 3; 100 }; f(1) + f(1, 3)",
+	"func f(n) { if n <= 1 { return 1 }; return n * f(n - 1) }; f(5)",
+	"&x = 1 + 2 + 3d1; x + x",
+	"`a{1 + 2}b{'c' + 'd'}`",
+	"[1 + 1, 2 * 2, 'x' + 'y'][1]",
+	"{'a': 1 + 1}.a",
+	"a = [1, 2, 3]; a[1 + 0] = 2 * 5; a",
+	"try { 1 / 0 } catch { 'err' }",
+	"try { throw 1 + 1 } catch (e) { 5 }",
+	"try { 1 } catch { 2 }; 3",
+	"match 1 + 1 { 1 => 'a', 2 => 'b', _ => 'c' }",
+	"match 5 { 1..3 => 'a', [4, 5] => 'b', _ => 'c' }",
+	"[a, b] = [1 + 1, 2 + 2]; a * b",
+	"let a = 1 + 1; { let a = 2 * 2 }; a",
+	"1 in [1, 2] && 3 not in [1, 2]",
+	"!0 + !1",
+	"return 1 + 1; 2",
+}
+
+func runOptimizeCase(expr string, optimize bool) *Context {
+	vm := NewVM()
+	vm.Config.EnableOptimizer = optimize
+	vm.Config.DiceMaxMode = true
+	vm.Attrs.Store("力量", ni(50))
+	_ = vm.Run(expr)
+	return vm
+}
+
+func TestOptimizeDiff(t *testing.T) {
+	for _, expr := range optimizeDiffCases {
+		vm1 := runOptimizeCase(expr, false)
+		vm2 := runOptimizeCase(expr, true)
+		assert.Equal(t, vm1.Error, vm2.Error, expr)
+		if vm1.Error != nil {
+			continue
+		}
+		assert.Equal(t, vm1.Ret.ToRepr(), vm2.Ret.ToRepr(), expr)
+		assert.Equal(t, vm1.GetDetailText(), vm2.GetDetailText(), expr)
+		assert.Equal(t, vm1.RestInput, vm2.RestInput, expr)
+		assert.LessOrEqual(t, vm2.codeIndex, vm1.codeIndex, expr)
+	}
+}
+
+func TestOptimizeCode(t *testing.T) {
+	codeOf := func(expr string) []string {
+		vm := NewVM()
+		vm.Config.EnableOptimizer = true
+		if err := vm.Parse(expr); err != nil {
+			t.Errorf("%s: %s", expr, err.Error())
+			return nil
+		}
+		var ret []string
+		for _, c := range vm.code[:vm.codeIndex] {
+			ret = append(ret, c.CodeString())
+		}
+		return ret
+	}
+
+	assert.Equal(t, []string{"push.int 7", "halt"}, codeOf("1 + 2 * 3"))
+	assert.Equal(t, []string{"push.str abc", "halt"}, codeOf("'a' + 'b' + 'c'"))
+	assert.Equal(t, []string{"push.int 1", "push.int 0", "div", "halt"}, codeOf("1 / 0"))
+	assert.Equal(t, []string{"block.push", "push.int 1", "block.pop", "halt"}, codeOf("if true { 1 } else { 2 }"))
+	assert.Equal(t, []string{"push.int 1", "ret"}, codeOf("return 1; 2"))
+
+	// 比较运算依赖 EnableBoolType，不折叠
+	assert.Equal(t, []string{"push.int 1", "push.int 1", "comp.eq", "halt"}, codeOf("1 == 1"))
+
+	// 跳转链合并: 内层三目运算结束的 jmp 直接跳往外层的末尾
+	vm := NewVM()
+	vm.Config.EnableOptimizer = true
+	if assert.NoError(t, vm.Parse("a = 1; a ? (a ? 1 : 2) : 3")) {
+		code := vm.code[:vm.codeIndex]
+		for i, c := range code {
+			if isJumpCode(c.T) {
+				assert.NotEqual(t, typeJmp, code[jumpTarget(code, i)].T)
+			}
+		}
+	}
+
+	// 函数内部同样被优化
+	if assert.NoError(t, vm.Parse("func f() { 1 + 2 }")) {
+		fd, _ := vm.code[0].Value.(*VMValue).ReadFunctionData()
+		assert.Equal(t, []ByteCode{{T: typePushIntNumber, Value: IntType(3)}}, fd.code)
+	}
+}
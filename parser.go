@@ -1,8 +1,13 @@
 package dicescript
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
+	"math/big"
 	"strconv"
+	"strings"
+	"unicode"
 )
 
 type ParserData struct {
@@ -19,15 +24,36 @@ type ParserData struct {
 	loopInfo      []struct {
 		continueIndex int
 		breakIndex    int
+		blockDepth    int
 	}
-	loopLayer int // 当前loop层数
-	codeStack []struct {
+	loopLayer     int // 当前loop层数
+	blockDepth    int // 当前语句块与字符串模板的嵌套层数
+	matchStack    []matchInfo
+	optChainStack [][]IntType // 可选链中待回填的跳转，每条链一项
+	opSpanStack   []IntType   // 二元运算最左侧操作数的起点，每层优先级一项
+
+	cancelCheckedAt  uint64         // 上次检查取消信号时已解析的表达式数量
+	funcDefaultStack []*VMValue     // 函数参数默认值，与 varnameStack 中的参数名对应
+	argNamesStack    []argNamesInfo // 函数调用的命名参数，每层调用一项
+	codeStack        []struct {
 		code    []ByteCode
 		index   int
 		textPos int
 	}
 }
 
+// matchInfo match 语句解析时记录的待回填跳转
+type matchInfo struct {
+	failJumps []IntType // 当前分支匹配失败，跳往下一分支
+	bodyJumps []IntType // 数组模式中任一元素命中，跳往分支体
+	endJumps  []IntType // 分支执行完毕，跳往语句末尾
+}
+
+type argNamesInfo struct {
+	names     []string
+	misplaced bool // 命名参数之后出现了位置参数
+}
+
 type BufferSpan struct {
 	Begin IntType
 	End   IntType
@@ -45,7 +71,14 @@ func (e *ParserData) LoopBegin() {
 	e.loopInfo = append(e.loopInfo, struct {
 		continueIndex int
 		breakIndex    int
-	}{continueIndex: len(e.continueStack), breakIndex: len(e.breakStack)})
+		blockDepth    int
+	}{continueIndex: len(e.continueStack), breakIndex: len(e.breakStack), blockDepth: e.blockDepth})
+}
+
+// LoopUnwind 离开循环体内层的语句块，栈顶回到循环语句块的起点，在 break/continue 与每轮循环结束时使用
+func (e *ParserData) LoopUnwind() {
+	info := e.loopInfo[len(e.loopInfo)-1]
+	e.WriteCodeArg(typeBlockUnwind, IntType(e.blockDepth-info.blockDepth))
 }
 
 func (e *ParserData) LoopEnd() {
@@ -56,6 +89,72 @@ func (e *ParserData) LoopEnd() {
 	e.loopInfo = e.loopInfo[:len(e.loopInfo)-1]
 }
 
+func (p *ParserData) MatchBegin() {
+	p.matchStack = append(p.matchStack, matchInfo{})
+}
+
+// MatchAddJump 写入跳转指令，kind 为 fail/body/end 之一
+func (p *ParserData) MatchAddJump(op CodeType, kind string) {
+	p.AddOp(op)
+	info := &p.matchStack[len(p.matchStack)-1]
+	index := IntType(p.codeIndex) - 1
+	switch kind {
+	case "fail":
+		info.failJumps = append(info.failJumps, index)
+	case "body":
+		info.bodyJumps = append(info.bodyJumps, index)
+	case "end":
+		info.endJumps = append(info.endJumps, index)
+	}
+}
+
+func (p *ParserData) matchSetJumps(jumps []IntType) {
+	for _, codeIndex := range jumps {
+		p.code[codeIndex].Arg = IntType(p.codeIndex) - codeIndex - 1
+	}
+}
+
+// MatchSetBody 数组模式结束，此处为分支体(或守卫)的起点
+func (p *ParserData) MatchSetBody() {
+	info := &p.matchStack[len(p.matchStack)-1]
+	p.matchSetJumps(info.bodyJumps)
+	info.bodyJumps = nil
+}
+
+// MatchArmEnd 分支结束，跳往末尾，失败的跳转指向下一分支
+func (p *ParserData) MatchArmEnd() {
+	p.MatchAddJump(typeJmp, "end")
+	info := &p.matchStack[len(p.matchStack)-1]
+	p.matchSetJumps(info.failJumps)
+	info.failJumps = nil
+}
+
+// MatchEnd 所有分支均未命中时结果为 null
+func (p *ParserData) MatchEnd() {
+	p.AddOp(typePop)
+	p.PushNull()
+	info := p.matchStack[len(p.matchStack)-1]
+	p.matchSetJumps(info.endJumps)
+	p.matchStack = p.matchStack[:len(p.matchStack)-1]
+}
+
+func (p *ParserData) OptChainBegin() {
+	p.optChainStack = append(p.optChainStack, nil)
+}
+
+// OptChainJump 写入可选链的跳转，左侧为 null 时直接跳到链的末尾
+func (p *ParserData) OptChainJump() {
+	p.AddOp(typeJeNull)
+	last := len(p.optChainStack) - 1
+	p.optChainStack[last] = append(p.optChainStack[last], IntType(p.codeIndex)-1)
+}
+
+func (p *ParserData) OptChainEnd() {
+	last := len(p.optChainStack) - 1
+	p.matchSetJumps(p.optChainStack[last])
+	p.optChainStack = p.optChainStack[:last]
+}
+
 func (e *ParserData) checkStackOverflow() bool {
 	if e.codeIndex >= len(e.code) {
 		need := len(e.code) * 2
@@ -76,9 +175,17 @@ func (e *ParserData) WriteCode(T CodeType, value any) {
 		return
 	}
 
-	c := &e.code[e.codeIndex]
-	c.T = T
-	c.Value = value
+	e.code[e.codeIndex] = ByteCode{T: T, Value: value}
+	e.codeIndex += 1
+}
+
+// WriteCodeArg 写入带整数操作数的指令，如跳转、push.arr、invoke
+func (e *ParserData) WriteCodeArg(T CodeType, arg IntType) {
+	if e.checkStackOverflow() {
+		return
+	}
+
+	e.code[e.codeIndex] = ByteCode{T: T, Arg: arg}
 	e.codeIndex += 1
 }
 
@@ -86,20 +193,45 @@ func (p *ParserData) AddDiceDetail(begin IntType, end IntType) {
 	p.WriteCode(typeDetailMark, BufferSpan{Begin: begin, End: end})
 }
 
+func (p *ParserData) AddThrow(begin IntType, end IntType) {
+	p.WriteCode(typeThrow, BufferSpan{Begin: begin, End: end})
+}
+
 func (e *ParserData) AddOp(operator CodeType) {
-	var val interface{} = nil
-	if operator == typeJne || operator == typeJmp {
-		val = IntType(0)
+	switch operator {
+	case typeBlockPush, typeFStringBlockPush:
+		e.blockDepth += 1
+	case typeBlockPop, typeFStringBlockPop:
+		e.blockDepth -= 1
 	}
-	e.WriteCode(operator, val)
+	e.WriteCode(operator, nil)
 }
 
 func (e *ParserData) AddLoadName(value string) {
 	e.WriteCode(typeLoadName, value)
 }
 
+// PushIntNumber 写入整数字面量，支持 0x/0b 前缀与 _ 分隔符
 func (e *ParserData) PushIntNumber(value string) {
-	val, _ := strconv.ParseInt(value, 10, 64)
+	value = strings.ReplaceAll(value, "_", "")
+	base := 10
+	if len(value) > 2 && value[0] == '0' {
+		switch value[1] {
+		case 'x', 'X':
+			base = 16
+			value = value[2:]
+		case 'b', 'B':
+			base = 2
+			value = value[2:]
+		}
+	}
+	val, err := strconv.ParseInt(value, base, strconv.IntSize)
+	if err != nil {
+		if i, ok := new(big.Int).SetString(value, base); ok {
+			e.WriteCode(typePushBigInt, i)
+			return
+		}
+	}
 	e.WriteCode(typePushIntNumber, IntType(val))
 }
 
@@ -108,17 +240,21 @@ func (e *ParserData) PushStr(value string) {
 }
 
 func (e *ParserData) PushArray(value IntType) {
-	e.WriteCode(typePushArray, value)
+	e.WriteCodeArg(typePushArray, value)
 }
 
 func (e *ParserData) PushDict(value IntType) {
-	e.WriteCode(typePushDict, value)
+	e.WriteCodeArg(typePushDict, value)
 }
 
 func (e *ParserData) PushNull() {
 	e.WriteCode(typePushNull, nil)
 }
 
+func (e *ParserData) PushBool(value bool) {
+	e.WriteCode(typePushBool, value)
+}
+
 func (e *ParserData) PushThis() {
 	e.WriteCode(typePushThis, nil)
 }
@@ -129,11 +265,12 @@ func (e *ParserData) PushGlobal() {
 
 func (e *ParserData) AddFormatString(num IntType) {
 	// e.PushStr(value)
-	e.WriteCode(typeLoadFormatString, num) // num
+	e.WriteCodeArg(typeLoadFormatString, num)
 }
 
+// PushFloatNumber 写入浮点数字面量，支持 1e3 这样的科学计数法，超出范围时为 ±Inf
 func (e *ParserData) PushFloatNumber(value string) {
-	val, _ := strconv.ParseFloat(value, 64)
+	val, _ := strconv.ParseFloat(strings.ReplaceAll(value, "_", ""), 64)
 	e.WriteCode(typePushFloatNumber, float64(val))
 }
 
@@ -162,6 +299,56 @@ func (e *ParserData) AddStoreLocal(text string) {
 	e.WriteCode(typeStoreNameLocal, text)
 }
 
+// AddStoreLet let/const 声明，变量归属于当前语句块
+func (e *ParserData) AddStoreLet(text string, isConst bool) {
+	if isConst {
+		e.WriteCode(typeStoreNameConst, text)
+	} else {
+		e.WriteCode(typeStoreNameLet, text)
+	}
+}
+
+// ImportInfo 导入的模块名，以及需要确认存在的名字
+type ImportInfo struct {
+	Module string
+	Keys   []string
+	Span   BufferSpan // import 语句在源码中的位置
+}
+
+// AddImport import "name" [as alias]，未给出 alias 时以模块名作为变量名
+func (p *ParserData) AddImport(module string, alias string, offset int, text []byte) error {
+	if alias == "" {
+		if !isIdentifier(module) {
+			return fmt.Errorf("模块名 %s 不能作为变量名，请使用 import \"%s\" as 名字", module, module)
+		}
+		alias = module
+	}
+	p.WriteCode(typeImport, ImportInfo{Module: module, Span: BufferSpan{Begin: IntType(offset), End: spanEnd(offset, text)}})
+	p.AddStore(alias)
+	return nil
+}
+
+// AddImportFrom import {a, b as c} from "name"，名字栈中为导入名与变量名交替，语句开头由 OpSpanBegin 记录
+func (p *ParserData) AddImportFrom(module string, offset int, text []byte) {
+	span := p.stmtSpan(offset, text)
+	num := p.CounterPop()
+	pairs := p.namesPopN(num * 2)
+	info := UnpackInfo{Count: num, RestIndex: -1, Span: span}
+	var names []string
+	for i := 0; i < len(pairs); i += 2 {
+		info.Keys = append(info.Keys, pairs[i])
+		names = append(names, pairs[i+1])
+	}
+	p.WriteCode(typeImport, ImportInfo{Module: module, Keys: info.Keys, Span: span})
+	p.WriteCode(typeUnpackDict, info)
+	p.addUnpackStores(names)
+}
+
+// AddDeclareGlobal global 声明
+func (e *ParserData) AddDeclareGlobal(text string) {
+	e.WriteCode(typeDeclareGlobal, text)
+}
+
 func (e *ParserData) NamePush(test string) {
 	e.varnameStack = append(e.varnameStack, test)
 }
@@ -182,6 +369,7 @@ func (p *ParserData) ContinuePush() error {
 		if p.continueStack == nil {
 			p.continueStack = []IntType{}
 		}
+		p.LoopUnwind()
 		p.AddOp(typeJmp)
 		p.continueStack = append(p.continueStack, IntType(p.codeIndex)-1)
 	} else {
@@ -197,7 +385,7 @@ func (p *ParserData) ContinueSet(offsetB int) {
 			lastB := len(p.jmpStack) - 1 - offsetB
 			jmpIndex := p.jmpStack[lastB]
 			// 试出来的，这个是对的，那么也许while那个是错的？？还是说因为while最后多push了一个jmp呢？
-			p.code[codeIndex].Value = -(IntType(codeIndex) - jmpIndex)
+			p.code[codeIndex].Arg = -(IntType(codeIndex) - jmpIndex)
 		}
 	}
 }
@@ -206,7 +394,7 @@ func (p *ParserData) BreakSet() {
 	if p.breakStack != nil {
 		info := p.loopInfo[len(p.loopInfo)-1]
 		for _, codeIndex := range p.breakStack[info.breakIndex:] {
-			p.code[codeIndex].Value = IntType(p.codeIndex) - codeIndex - 1
+			p.code[codeIndex].Arg = IntType(p.codeIndex) - codeIndex - 1
 		}
 	}
 }
@@ -216,6 +404,7 @@ func (p *ParserData) BreakPush() error {
 		if p.breakStack == nil {
 			p.breakStack = []IntType{}
 		}
+		p.LoopUnwind()
 		p.AddOp(typeJmp)
 		p.breakStack = append(p.breakStack, IntType(p.codeIndex)-1)
 		return nil
@@ -228,7 +417,7 @@ func (e *ParserData) OffsetPopAndSet() {
 	last := len(e.jmpStack) - 1
 	codeIndex := e.jmpStack[last]
 	e.jmpStack = e.jmpStack[:last]
-	e.code[codeIndex].Value = IntType(IntType(e.codeIndex) - codeIndex - 1)
+	e.code[codeIndex].Arg = IntType(e.codeIndex) - codeIndex - 1
 	// fmt.Println("XXXX", e.Code[codeIndex], "|", e.Top, codeIndex)
 }
 
@@ -245,9 +434,9 @@ func (e *ParserData) OffsetJmpSetX(offsetA int, offsetB int, rev bool) {
 	jmpIndex := e.jmpStack[lastB]
 
 	if rev {
-		e.code[codeIndex].Value = -(IntType(e.codeIndex) - jmpIndex - 1)
+		e.code[codeIndex].Arg = -(IntType(e.codeIndex) - jmpIndex - 1)
 	} else {
-		e.code[codeIndex].Value = IntType(e.codeIndex) - jmpIndex - 1
+		e.code[codeIndex].Arg = IntType(e.codeIndex) - jmpIndex - 1
 	}
 }
 
@@ -286,17 +475,127 @@ func (e *ParserData) AddInvokeMethod(name string, paramsNum IntType) {
 
 func (e *ParserData) AddInvoke(paramsNum IntType) {
 	// e.WriteCode(typePushIntNumber, paramsNum)
-	e.WriteCode(typeInvoke, paramsNum)
+	e.WriteCodeArg(typeInvoke, paramsNum)
+}
+
+// InvokeNamedInfo 带命名参数的调用，Names 对应最后 len(Names) 个参数
+type InvokeNamedInfo struct {
+	Num   IntType
+	Names []string
+	Span  BufferSpan // 参数列表在源码中的位置
+}
+
+// SetInvokeSpan 记录刚写入的调用指令的参数列表在源码中的位置，出错时用于生成调用栈
+func (e *ParserData) SetInvokeSpan(begin IntType, end IntType) {
+	if e.codeIndex == 0 {
+		return
+	}
+	code := &e.code[e.codeIndex-1]
+	switch code.T {
+	case typeInvoke:
+		code.Value = BufferSpan{Begin: begin, End: end}
+	case typeInvokeNamed:
+		info := code.Value.(InvokeNamedInfo)
+		info.Span = BufferSpan{Begin: begin, End: end}
+		code.Value = info
+	}
+}
+
+// OpSpanBegin 记录一串二元运算最左侧操作数的起点，之后的算符指令以此作为位置的起点。
+// 解构、导入等语句也用它记录语句的开头
+func (e *ParserData) OpSpanBegin(offset int) {
+	e.opSpanStack = append(e.opSpanStack, IntType(offset))
+}
+
+func (e *ParserData) OpSpanEnd() {
+	e.opSpanStack = e.opSpanStack[:len(e.opSpanStack)-1]
+}
+
+// AddBinaryOp 写入二元算符，记录从最左侧操作数到右侧操作数末尾的位置，出错时用于定位
+func (e *ParserData) AddBinaryOp(operator CodeType, offset int, text []byte) {
+	begin := e.opSpanStack[len(e.opSpanStack)-1]
+	e.WriteCode(operator, BufferSpan{Begin: begin, End: spanEnd(offset, text)})
+}
+
+// AddOpWithSpan 写入算符，记录 text 所在的位置，出错时用于定位
+func (e *ParserData) AddOpWithSpan(operator CodeType, offset int, text []byte) {
+	e.WriteCode(operator, BufferSpan{Begin: IntType(offset), End: spanEnd(offset, text)})
+}
+
+// stmtSpan 从 OpSpanBegin 记录的语句开头到 text 末尾的位置
+func (e *ParserData) stmtSpan(offset int, text []byte) BufferSpan {
+	begin := e.opSpanStack[len(e.opSpanStack)-1]
+	e.OpSpanEnd()
+	return BufferSpan{Begin: begin, End: spanEnd(offset, text)}
+}
+
+// spanEnd 匹配到的文本去掉末尾空白后的结束位置
+func spanEnd(offset int, text []byte) IntType {
+	return IntType(offset + len(bytes.TrimRightFunc(text, unicode.IsSpace)))
+}
+
+func (p *ParserData) ArgNamesBegin() {
+	p.argNamesStack = append(p.argNamesStack, argNamesInfo{})
+}
+
+func (p *ParserData) ArgNamePush(name string) {
+	info := &p.argNamesStack[len(p.argNamesStack)-1]
+	info.names = append(info.names, name)
+}
+
+// ArgPositional 记录一个位置参数，用于检查位置参数是否出现在命名参数之后
+func (p *ParserData) ArgPositional() {
+	info := &p.argNamesStack[len(p.argNamesStack)-1]
+	if len(info.names) > 0 {
+		info.misplaced = true
+	}
+}
+
+// AddInvokeWithNames 结束一次调用，没有命名参数时与 AddInvoke 相同
+func (p *ParserData) AddInvokeWithNames(paramsNum IntType) error {
+	last := len(p.argNamesStack) - 1
+	info := p.argNamesStack[last]
+	names := info.names
+	p.argNamesStack = p.argNamesStack[:last]
+	if info.misplaced {
+		return errors.New("位置参数不能放在命名参数之后")
+	}
+	if len(names) == 0 {
+		p.AddInvoke(paramsNum)
+	} else {
+		p.WriteCode(typeInvokeNamed, InvokeNamedInfo{Num: paramsNum, Names: names})
+	}
+	return nil
 }
 
 func fixCodeByOffset(code []ByteCode, offset int) {
 	for index, i := range code {
-		switch i.T {
-		case typeDetailMark:
-			v := i.Value.(BufferSpan)
+		switch v := i.Value.(type) {
+		case BufferSpan:
+			// 计算过程标记、throw、调用与算符的位置
 			v.Begin -= IntType(offset)
 			v.End -= IntType(offset)
 			code[index].Value = v
+		case InvokeNamedInfo:
+			v.Span.Begin -= IntType(offset)
+			v.Span.End -= IntType(offset)
+			code[index].Value = v
+		case AttrInfo:
+			v.Span.Begin -= IntType(offset)
+			v.Span.End -= IntType(offset)
+			code[index].Value = v
+		case InplaceInfo:
+			v.Span.Begin -= IntType(offset)
+			v.Span.End -= IntType(offset)
+			code[index].Value = v
+		case UnpackInfo:
+			v.Span.Begin -= IntType(offset)
+			v.Span.End -= IntType(offset)
+			code[index].Value = v
+		case ImportInfo:
+			v.Span.Begin -= IntType(offset)
+			v.Span.End -= IntType(offset)
+			code[index].Value = v
 		}
 	}
 }
@@ -326,6 +625,23 @@ func (p *ParserData) AddStoreComputedOnStack(text string) {
 	p.WriteCode(typePushComputed, val)
 }
 
+// FuncDefaultPush 记录一个无默认值的参数
+func (p *ParserData) FuncDefaultPush() {
+	p.funcDefaultStack = append(p.funcDefaultStack, nil)
+}
+
+// FuncDefaultPushExpr 默认值表达式编译为函数，调用时求值
+func (p *ParserData) FuncDefaultPushExpr(text string) {
+	code, length, offset := p.CodePop()
+	fixCodeByOffset(code, offset)
+	val := NewFunctionValRaw(&FunctionData{
+		Expr:      text,
+		code:      code,
+		codeIndex: length,
+	})
+	p.funcDefaultStack = append(p.funcDefaultStack, val)
+}
+
 func (p *ParserData) AddStoreFunction(name string, paramsReversed []string, text string) {
 	code, length, offset := p.CodePop()
 	fixCodeByOffset(code, offset)
@@ -335,10 +651,21 @@ func (p *ParserData) AddStoreFunction(name string, paramsReversed []string, text
 		paramsReversed[i], paramsReversed[j] = paramsReversed[j], paramsReversed[i]
 	}
 
+	var defaults []*VMValue
+	last := len(p.funcDefaultStack) - len(paramsReversed)
+	for _, i := range p.funcDefaultStack[last:] {
+		if i != nil {
+			defaults = append([]*VMValue{}, p.funcDefaultStack[last:]...)
+			break
+		}
+	}
+	p.funcDefaultStack = p.funcDefaultStack[:last]
+
 	val := NewFunctionValRaw(&FunctionData{
 		Expr:      text,
 		Name:      name,
 		Params:    paramsReversed,
+		Defaults:  defaults,
 		code:      code,
 		codeIndex: length,
 	})
@@ -349,13 +676,137 @@ func (p *ParserData) AddStoreFunction(name string, paramsReversed []string, text
 	}
 }
 
-func (p *ParserData) AddAttrSet(objName string, attr string, isRaw bool) {
+// InplaceInfo 复合赋值的目标名称与运算，下标赋值时 Name 为空
+type InplaceInfo struct {
+	Name string
+	Op   CodeType
+	Span BufferSpan // attr.set.op 语句在源码中的位置，其他指令不记录
+}
+
+var inplaceOperators = map[string]CodeType{
+	"+=":  typeAdd,
+	"-=":  typeSubtract,
+	"*=":  typeMultiply,
+	"/=":  typeDivide,
+	"%=":  typeModulus,
+	"??=": typeNullCoalescing,
+}
+
+func (info InplaceInfo) OpText() string {
+	for k, v := range inplaceOperators {
+		if v == info.Op {
+			return k
+		}
+	}
+	return ""
+}
+
+func (p *ParserData) AddStoreNameOp(name string, op string) {
+	p.WriteCode(typeStoreNameOp, InplaceInfo{Name: name, Op: inplaceOperators[op]})
+}
+
+func (p *ParserData) AddAttrSetOp(objName string, attr string, op string, offset int, text []byte) {
+	p.WriteCode(typeLoadName, objName)
+	p.WriteCode(typeAttrSetOp, InplaceInfo{Name: attr, Op: inplaceOperators[op], Span: BufferSpan{Begin: IntType(offset), End: spanEnd(offset, text)}})
+}
+
+func (p *ParserData) AddItemSetOp(op string) {
+	p.WriteCode(typeItemSetOp, InplaceInfo{Op: inplaceOperators[op]})
+}
+
+// UnpackInfo 解构赋值，数组解构使用 Count 与 RestIndex，字典解构使用 Keys
+type UnpackInfo struct {
+	Count     IntType
+	RestIndex IntType    // 剩余元素的位置，-1 为没有
+	Keys      []string   // 字典解构时各项对应的键，剩余项为空字符串
+	Span      BufferSpan // 解构语句在源码中的位置
+}
+
+// addUnpackStores 解构出的值依次位于栈顶，倒序赋值并弹出
+func (p *ParserData) addUnpackStores(names []string) {
+	for i := len(names) - 1; i >= 0; i-- {
+		p.AddStore(names[i])
+		p.AddOp(typePop)
+	}
+}
+
+// namesPopN 弹出 num 个名字，按入栈顺序返回
+func (p *ParserData) namesPopN(num IntType) []string {
+	names := make([]string, num)
+	for i := num - 1; i >= 0; i-- {
+		names[i] = p.NamePop()
+	}
+	return names
+}
+
+// AddUnpackArray [a, b, ...rest] = expr，语句开头由 OpSpanBegin 记录
+func (p *ParserData) AddUnpackArray(offset int, text []byte) error {
+	span := p.stmtSpan(offset, text)
+	names := p.namesPopN(p.CounterPop())
+	info := UnpackInfo{Count: IntType(len(names)), RestIndex: -1, Span: span}
+	for i, name := range names {
+		if strings.HasPrefix(name, "...") {
+			if info.RestIndex != -1 {
+				return errors.New("解构赋值中只能有一个剩余元素")
+			}
+			info.RestIndex = IntType(i)
+			names[i] = name[3:]
+		}
+	}
+	p.WriteCode(typeUnpackArray, info)
+	p.addUnpackStores(names)
+	return nil
+}
+
+// AddUnpackDict {hp, mp: m, ...rest} = expr，名字栈中为键与变量名交替，语句开头由 OpSpanBegin 记录
+func (p *ParserData) AddUnpackDict(offset int, text []byte) {
+	span := p.stmtSpan(offset, text)
+	num := p.CounterPop()
+	pairs := p.namesPopN(num * 2)
+	info := UnpackInfo{Count: num, RestIndex: -1, Span: span}
+	var names []string
+	for i := 0; i < len(pairs); i += 2 {
+		key := pairs[i]
+		if key == "..." {
+			info.RestIndex = IntType(i / 2)
+			key = ""
+		}
+		info.Keys = append(info.Keys, key)
+		names = append(names, pairs[i+1])
+	}
+	p.WriteCode(typeUnpackDict, info)
+	p.addUnpackStores(names)
+}
+
+// AddUnpackTuple a, b = x, y，右侧有多项时先打包为数组
+func (p *ParserData) AddUnpackTuple(offset int, text []byte) error {
+	rightNum := p.CounterPop()
+	if rightNum > 1 {
+		p.PushArray(rightNum)
+	}
+	return p.AddUnpackArray(offset, text)
+}
+
+// AttrInfo 属性名，以及属性访问或赋值在源码中的位置
+type AttrInfo struct {
+	Name string
+	Span BufferSpan
+}
+
+// AddAttrGet 读取属性，text 为 .name 部分
+func (p *ParserData) AddAttrGet(attr string, offset int, text []byte) {
+	p.WriteCode(typeAttrGet, AttrInfo{Name: attr, Span: BufferSpan{Begin: IntType(offset), End: spanEnd(offset, text)}})
+}
+
+// AddAttrSet obj.attr = expr，语句开头由 OpSpanBegin 记录
+func (p *ParserData) AddAttrSet(objName string, attr string, isRaw bool, offset int, text []byte) {
+	span := p.stmtSpan(offset, text)
 	if isRaw {
 		p.WriteCode(typeLoadNameRaw, objName)
 	} else {
 		p.WriteCode(typeLoadName, objName)
 	}
-	p.WriteCode(typeAttrSet, attr)
+	p.WriteCode(typeAttrSet, AttrInfo{Name: attr, Span: span})
 }
 
 func (p *ParserData) CodePush(textPos int) {
@@ -28,7 +28,7 @@ const (
 	ParseErrorLanguageEnglish   = 2 // 仅英文
 )
 
-// parseErrorLanguage 当前错误消息语言设置
+// parseErrorLanguage 默认的错误消息语言。经由 Context 解析时以 RollConfig.ParseErrorLanguage 为准
 var parseErrorLanguage = ParseErrorLanguageBilingual
 
 // bilingualMsg 双语消息
@@ -55,15 +55,45 @@ func init() {
 	ErrorFormatter = formatFriendlyError
 }
 
-// SetParseErrorLanguage 设置解析错误消息的语言
+// SetParseErrorLanguage 设置解析错误消息的默认语言。
+// 不要在解析进行中调用；Context 解析时使用 RollConfig.ParseErrorLanguage，不受此设置影响
 func SetParseErrorLanguage(lang int) {
 	parseErrorLanguage = lang
 }
 
+// friendlyParseError 友好的解析错误，在输出时才按语言格式化。
+// 语言记录在错误自身上，多个 Context 同时解析时互不影响
+type friendlyParseError struct {
+	pos   position
+	input []byte
+	msg   bilingualMsg
+	char  rune
+	lang  int
+}
+
+func (e *friendlyParseError) Error() string {
+	return fmtErr(e.pos, e.input, e.msg, e.char, e.lang).Error()
+}
+
+// localizeParseError 将解析得到的友好错误设为指定的语言
+func localizeParseError(err error, lang int) {
+	list, ok := err.(errList)
+	if !ok {
+		return
+	}
+	for _, e := range list {
+		if pe, ok := e.(*parserError); ok {
+			if fe, ok := pe.Inner.(*friendlyParseError); ok {
+				fe.lang = lang
+			}
+		}
+	}
+}
+
 // formatFriendlyError 生成友好的错误消息
 func formatFriendlyError(pos position, input []byte, expected []string) error {
 	if len(input) == 0 {
-		return fmtErr(pos, input, errMsgs["empty"], 0)
+		return &friendlyParseError{pos: pos, input: input, msg: errMsgs["empty"], lang: parseErrorLanguage}
 	}
 
 	var char rune
@@ -115,15 +145,15 @@ func formatFriendlyError(pos position, input []byte, expected []string) error {
 		msg = errMsgs["syntax"]
 	}
 
-	return fmtErr(pos, input, msg, fmtChar)
+	return &friendlyParseError{pos: pos, input: input, msg: msg, char: fmtChar, lang: parseErrorLanguage}
 }
 
 // fmtErr 格式化错误输出
-func fmtErr(pos position, input []byte, msg bilingualMsg, char rune) error {
+func fmtErr(pos position, input []byte, msg bilingualMsg, char rune, lang int) error {
 	var sb strings.Builder
 
 	// 标题
-	switch parseErrorLanguage {
+	switch lang {
 	case ParseErrorLanguageChinese:
 		sb.WriteString("语法错误\n")
 	case ParseErrorLanguageEnglish:
@@ -156,7 +186,7 @@ func fmtErr(pos position, input []byte, msg bilingualMsg, char rune) error {
 	}
 
 	// 位置和消息
-	switch parseErrorLanguage {
+	switch lang {
 	case ParseErrorLanguageChinese:
 		sb.WriteString(fmt.Sprintf("  位置 %d:%d - %s", pos.line, pos.col, cn))
 	case ParseErrorLanguageEnglish:
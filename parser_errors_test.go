@@ -16,6 +16,7 @@ package dicescript
 
 import (
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -212,3 +213,27 @@ func TestGetPrevNonSpaceChar(t *testing.T) {
 		}
 	}
 }
+
+func TestLanguageOptions_Concurrent(t *testing.T) {
+	// 语言设置跟随各自的配置，并发编译时互不影响
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		lang := ParseErrorLanguageChinese
+		if i%2 == 1 {
+			lang = ParseErrorLanguageEnglish
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := Compile("/", RollConfig{ParseErrorLanguage: lang})
+			if assert.Error(t, err) {
+				if lang == ParseErrorLanguageEnglish {
+					assert.NotContains(t, err.Error(), "语法错误")
+				} else {
+					assert.NotContains(t, err.Error(), "Syntax Error")
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
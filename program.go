@@ -0,0 +1,139 @@
+package dicescript
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Program 编译后的脚本，包含字节码、原文和计算过程所需的位置信息。
+// Program 创建后不再修改，可以在多个 goroutine 中交给不同的 Context 同时执行
+type Program struct {
+	code          []ByteCode
+	data          []byte // 解析的文本
+	parsedOffset  int    // 解析结束的位置，之后的内容为 RestInput
+	source        []byte // 规范化之前的原文
+	sourceOffsets []int
+}
+
+// CodeCache 字节码缓存，由宿主实现，如存入数据库或文件，重启后可以跳过解析。
+// 键由源码和影响解析的配置算出，值为 Program.MarshalBinary 的结果
+type CodeCache interface {
+	Get(key string) []byte // 未命中时返回nil
+	Set(key string, data []byte)
+}
+
+// Compile 以给定配置编译表达式，编译结果可以用 Context.Exec 反复执行
+func Compile(expr string, cfg RollConfig) (*Program, error) {
+	vm := NewVM()
+	vm.Config = cfg
+	return vm.compileCached(expr)
+}
+
+// Compile 使用当前上下文的配置和自定义骰子编译表达式，不影响上下文本身
+func (ctx *Context) Compile(expr string) (*Program, error) {
+	vm := NewVM()
+	vm.Config = ctx.Config
+	vm.CustomDiceInfo = ctx.CustomDiceInfo
+	return vm.compileCached(expr)
+}
+
+// codeCacheKey 缓存的键。自定义骰子和输入规范化由宿主的函数决定，无法计入键中，此时不使用缓存
+func (ctx *Context) codeCacheKey(expr string) string {
+	if ctx.Config.CodeCache == nil {
+		return ""
+	}
+	return ctx.compileKey(expr)
+}
+
+// compileKey 由源码和影响解析的配置算出的键，相同的键编译结果相同。无法确定时返回空串
+func (ctx *Context) compileKey(expr string) string {
+	cfg := &ctx.Config
+	if cfg.InputNormalizer != nil || len(ctx.CustomDiceInfo) > 0 {
+		return ""
+	}
+	flags := []bool{
+		cfg.EnableDiceWoD, cfg.EnableDiceCoC, cfg.EnableDiceFate, cfg.EnableDiceDoubleCross,
+		cfg.DisableBitwiseOp, cfg.DisableStmts, cfg.DisableNDice, cfg.EnableChineseKeywords, cfg.EnableOptimizer,
+	}
+	h := sha256.New()
+	for _, f := range flags {
+		if f {
+			h.Write([]byte{1})
+		} else {
+			h.Write([]byte{0})
+		}
+	}
+	h.Write([]byte(expr))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// compileCached 编译表达式，设置了 CodeCache 时优先从缓存中读取。缓存失效或损坏时重新编译并覆盖
+func (ctx *Context) compileCached(expr string) (*Program, error) {
+	key := ctx.codeCacheKey(expr)
+	if key != "" {
+		if data := ctx.Config.CodeCache.Get(key); data != nil {
+			prog := &Program{}
+			if prog.UnmarshalBinary(data) == nil {
+				return prog, nil
+			}
+		}
+	}
+
+	prog, err := ctx.compile(expr)
+	if err != nil || key == "" {
+		return prog, err
+	}
+	if data, err := prog.MarshalBinary(); err == nil {
+		ctx.Config.CodeCache.Set(key, data)
+	}
+	return prog, nil
+}
+
+func (ctx *Context) compile(expr string) (*Program, error) {
+	if err := ctx.Parse(expr); err != nil {
+		return nil, err
+	}
+	code := make([]ByteCode, ctx.codeIndex)
+	copy(code, ctx.code)
+	return &Program{
+		code:          code,
+		data:          ctx.parser.data,
+		parsedOffset:  ctx.parser.pt.offset,
+		source:        ctx.source,
+		sourceOffsets: ctx.sourceOffsets,
+	}, nil
+}
+
+// load 载入编译好的程序，之后可以直接执行
+func (ctx *Context) load(prog *Program) {
+	p := &parser{data: prog.data}
+	p.pt.offset = prog.parsedOffset
+	ctx.parser = p
+	ctx.source = prog.source
+	ctx.sourceOffsets = prog.sourceOffsets
+	ctx.code = prog.code
+	ctx.codeIndex = len(prog.code)
+}
+
+// Source 编译时的原文
+func (prog *Program) Source() string {
+	if prog.source != nil {
+		return string(prog.source)
+	}
+	return string(prog.data)
+}
+
+// Exec 执行编译好的程序，效果与 Run 相同，但省去了解析
+func (ctx *Context) Exec(prog *Program) error {
+	return ctx.ExecContext(context.Background(), prog)
+}
+
+func (ctx *Context) exec(prog *Program) error {
+	ctx.load(prog)
+	ctx.Error = nil
+	ctx.NumOpCount = 0
+	ctx.MemoryUsed = 0
+	ctx.detailCache = ""
+	return ctx.RunAfterParsed()
+}
@@ -0,0 +1,89 @@
+package dicescript
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgramExec(t *testing.T) {
+	prog, err := Compile("力量 + 1d1 剩余", RollConfig{})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "力量 + 1d1 剩余", prog.Source())
+
+	for i := 1; i <= 3; i++ {
+		vm := NewVM()
+		vm.Attrs.Store("力量", ni(IntType(i)))
+		err := vm.Exec(prog)
+		if assert.NoError(t, err) {
+			assert.True(t, valueEqual(vm.Ret, ni(IntType(i+1))))
+			assert.Equal(t, " 剩余", vm.RestInput)
+			assert.Equal(t, "力量 + 1d1", vm.Matched)
+			assert.Equal(t, fmt.Sprintf("%d[力量] + 1[1d1]", i), vm.GetDetailText())
+		}
+	}
+
+	// 同一个上下文可以反复执行
+	vm := NewVM()
+	prog, _ = Compile("a = (a ?? 0) + 1; a", RollConfig{})
+	for i := 1; i <= 3; i++ {
+		if assert.NoError(t, vm.Exec(prog)) {
+			assert.True(t, valueEqual(vm.Ret, ni(IntType(i))))
+		}
+	}
+
+	_, err = Compile("if", RollConfig{})
+	assert.Error(t, err)
+}
+
+func TestProgramConfig(t *testing.T) {
+	prog, err := Compile("ｄ１＋１", RollConfig{InputNormalizer: NormalizeFullWidth})
+	if assert.NoError(t, err) {
+		vm := NewVM()
+		if assert.NoError(t, vm.Exec(prog)) {
+			assert.True(t, valueEqual(vm.Ret, ni(2)))
+			assert.Equal(t, "ｄ１＋１", vm.Matched)
+		}
+	}
+
+	// 运行时的配置来自执行的上下文
+	prog, _ = Compile("2^80", RollConfig{})
+	vm := NewVM()
+	vm.Config.EnableBigInt = true
+	if assert.NoError(t, vm.Exec(prog)) {
+		assert.Equal(t, "1208925819614629174706176", vm.Ret.ToString())
+	}
+}
+
+func TestProgramConcurrentExec(t *testing.T) {
+	prog, err := Compile("&x = 1d1 + 1; func f(a) { a + x }; [f(1) + f(2), 3d6 >= 3, x]", RollConfig{})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var wg sync.WaitGroup
+	results := make([]*VMValue, 16)
+	errs := make([]error, 16)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			vm := NewVM()
+			errs[i] = vm.Exec(prog)
+			results[i] = vm.Ret
+			_ = vm.GetDetailText()
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range results {
+		if assert.NoError(t, errs[i]) {
+			arr, _ := results[i].ReadArray()
+			assert.True(t, valueEqual(arr.List[0], ni(7)))
+		}
+	}
+}
@@ -47,9 +47,9 @@ var g = &grammar{
 				run: (*parser).call_ondicescript_1,
 				expr: &seqExpr{
 					exprs: []any{
-						&ruleIRefExpr{index: 123 /* sp */},
+						&ruleIRefExpr{index: 164 /* sp */},
 						&ruleIRefExpr{index: 1 /* stmtSt */},
-						&ruleIRefExpr{index: 123 /* sp */},
+						&ruleIRefExpr{index: 164 /* sp */},
 					},
 				},
 			},
@@ -61,7 +61,7 @@ var g = &grammar{
 					&seqExpr{
 						exprs: []any{
 							&litMatcher{val: "^st", want: "\"^st\""},
-							&ruleIRefExpr{index: 130 /* st_expr */},
+							&ruleIRefExpr{index: 171 /* st_expr */},
 						},
 					},
 					&ruleIRefExpr{index: 2 /* stmtRoot */},
@@ -73,7 +73,7 @@ var g = &grammar{
 			expr: &seqExpr{
 				exprs: []any{
 					&ruleIRefExpr{index: 3 /* stmtLines */},
-					&ruleIRefExpr{index: 123 /* sp */},
+					&ruleIRefExpr{index: 164 /* sp */},
 				},
 			},
 		},
@@ -100,8 +100,8 @@ var g = &grammar{
 					},
 					&seqExpr{
 						exprs: []any{
-							&ruleIRefExpr{index: 127 /* comment */},
-							&ruleIRefExpr{index: 123 /* sp */},
+							&ruleIRefExpr{index: 168 /* comment */},
+							&ruleIRefExpr{index: 164 /* sp */},
 							&zeroOrOneExpr{
 								expr: &ruleIRefExpr{index: 3 /* stmtLines */},
 							},
@@ -121,7 +121,7 @@ var g = &grammar{
 								expr: &seqExpr{
 									exprs: []any{
 										&litMatcher{val: ";", want: "\";\""},
-										&ruleIRefExpr{index: 123 /* sp */},
+										&ruleIRefExpr{index: 164 /* sp */},
 									},
 								},
 							},
@@ -141,14 +141,14 @@ var g = &grammar{
 				expr: &seqExpr{
 					exprs: []any{
 						&litMatcher{val: "//", want: "\"//\""},
-						&ruleIRefExpr{index: 123 /* sp */},
+						&ruleIRefExpr{index: 164 /* sp */},
 						&litMatcher{val: "#EnableDice", want: "\"#EnableDice\""},
-						&ruleIRefExpr{index: 125 /* sp1x */},
+						&ruleIRefExpr{index: 166 /* sp1x */},
 						&labeledExpr{
 							label: "id",
-							expr:  &ruleIRefExpr{index: 98 /* identifier */},
+							expr:  &ruleIRefExpr{index: 135 /* identifier */},
 						},
-						&ruleIRefExpr{index: 125 /* sp1x */},
+						&ruleIRefExpr{index: 166 /* sp1x */},
 						&labeledExpr{
 							label: "on",
 							expr: &choiceExpr{
@@ -159,7 +159,7 @@ var g = &grammar{
 							},
 							textCapture: true,
 						},
-						&ruleIRefExpr{index: 128 /* commentLineRest */},
+						&ruleIRefExpr{index: 169 /* commentLineRest */},
 					},
 				},
 			},
@@ -170,7 +170,12 @@ var g = &grammar{
 				alternatives: []any{
 					&ruleIRefExpr{index: 8 /* stmtBreak */},
 					&ruleIRefExpr{index: 9 /* stmtContinue */},
-					&ruleIRefExpr{index: 26 /* exprRoot */},
+					&ruleIRefExpr{index: 18 /* stmtThrow */},
+					&ruleIRefExpr{index: 10 /* stmtLet */},
+					&ruleIRefExpr{index: 11 /* stmtGlobal */},
+					&ruleIRefExpr{index: 12 /* stmtImport */},
+					&ruleIRefExpr{index: 46 /* stmtAssignTuple */},
+					&ruleIRefExpr{index: 49 /* exprRoot */},
 				},
 			},
 		},
@@ -178,10 +183,12 @@ var g = &grammar{
 			name: "stmtWithBlock",
 			expr: &choiceExpr{
 				alternatives: []any{
-					&ruleIRefExpr{index: 14 /* stmtIf */},
-					&ruleIRefExpr{index: 16 /* stmtFunc */},
-					&ruleIRefExpr{index: 11 /* stmtWhile */},
-					&ruleIRefExpr{index: 10 /* stmtReturn */},
+					&ruleIRefExpr{index: 22 /* stmtIf */},
+					&ruleIRefExpr{index: 31 /* stmtFunc */},
+					&ruleIRefExpr{index: 16 /* stmtWhile */},
+					&ruleIRefExpr{index: 15 /* stmtReturn */},
+					&ruleIRefExpr{index: 19 /* stmtTry */},
+					&ruleIRefExpr{index: 23 /* stmtMatch */},
 				},
 			},
 		},
@@ -196,19 +203,19 @@ var g = &grammar{
 									alternatives: []any{
 										&seqExpr{
 											exprs: []any{
-												&ruleIRefExpr{index: 126 /* spNoCR */},
+												&ruleIRefExpr{index: 167 /* spNoCR */},
 												&litMatcher{val: "\n", want: "\"\\n\""},
 											},
 										},
 										&seqExpr{
 											exprs: []any{
-												&ruleIRefExpr{index: 123 /* sp */},
+												&ruleIRefExpr{index: 164 /* sp */},
 												&litMatcher{val: ";", want: "\";\""},
 											},
 										},
 									},
 								},
-								&ruleIRefExpr{index: 123 /* sp */},
+								&ruleIRefExpr{index: 164 /* sp */},
 							},
 						},
 					},
@@ -224,8 +231,8 @@ var g = &grammar{
 				run: (*parser).call_onstmtBreak_1,
 				expr: &seqExpr{
 					exprs: []any{
-						&litMatcher{val: "break", want: "\"break\""},
-						&ruleIRefExpr{index: 123 /* sp */},
+						&ruleIRefExpr{index: 133 /* kwBreak */},
+						&ruleIRefExpr{index: 164 /* sp */},
 					},
 				},
 			},
@@ -236,8 +243,303 @@ var g = &grammar{
 				run: (*parser).call_onstmtContinue_1,
 				expr: &seqExpr{
 					exprs: []any{
-						&litMatcher{val: "continue", want: "\"continue\""},
-						&ruleIRefExpr{index: 123 /* sp */},
+						&ruleIRefExpr{index: 134 /* kwContinue */},
+						&ruleIRefExpr{index: 164 /* sp */},
+					},
+				},
+			},
+		},
+		{
+			name:      "stmtLet",
+			varExists: true,
+			expr: &choiceExpr{
+				alternatives: []any{
+					&actionExpr{
+						run: (*parser).call_onstmtLet_2,
+						expr: &seqExpr{
+							exprs: []any{
+								&litMatcher{val: "let", want: "\"let\""},
+								&ruleIRefExpr{index: 166 /* sp1x */},
+								&labeledExpr{
+									label: "id",
+									expr:  &ruleIRefExpr{index: 135 /* identifier */},
+								},
+								&ruleIRefExpr{index: 164 /* sp */},
+								&litMatcher{val: "=", want: "\"=\""},
+								&notExpr{
+									expr: &litMatcher{val: "=", want: "\"=\""},
+								},
+								&ruleIRefExpr{index: 164 /* sp */},
+								&ruleIRefExpr{index: 49 /* exprRoot */},
+							},
+						},
+					},
+					&actionExpr{
+						run: (*parser).call_onstmtLet_14,
+						expr: &seqExpr{
+							exprs: []any{
+								&litMatcher{val: "let", want: "\"let\""},
+								&ruleIRefExpr{index: 166 /* sp1x */},
+								&labeledExpr{
+									label: "id",
+									expr:  &ruleIRefExpr{index: 135 /* identifier */},
+								},
+								&ruleIRefExpr{index: 164 /* sp */},
+							},
+						},
+					},
+					&actionExpr{
+						run: (*parser).call_onstmtLet_21,
+						expr: &seqExpr{
+							exprs: []any{
+								&litMatcher{val: "const", want: "\"const\""},
+								&ruleIRefExpr{index: 166 /* sp1x */},
+								&labeledExpr{
+									label: "id",
+									expr:  &ruleIRefExpr{index: 135 /* identifier */},
+								},
+								&ruleIRefExpr{index: 164 /* sp */},
+								&litMatcher{val: "=", want: "\"=\""},
+								&notExpr{
+									expr: &litMatcher{val: "=", want: "\"=\""},
+								},
+								&ruleIRefExpr{index: 164 /* sp */},
+								&ruleIRefExpr{index: 49 /* exprRoot */},
+							},
+						},
+					},
+					&seqExpr{
+						exprs: []any{
+							&litMatcher{val: "const", want: "\"const\""},
+							&ruleIRefExpr{index: 166 /* sp1x */},
+							&ruleIRefExpr{index: 135 /* identifier */},
+							&ruleIRefExpr{index: 164 /* sp */},
+							&andCodeExpr{run: (*parser).call_onstmtLet_38},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:      "stmtGlobal",
+			varExists: true,
+			expr: &choiceExpr{
+				alternatives: []any{
+					&actionExpr{
+						run: (*parser).call_onstmtGlobal_2,
+						expr: &seqExpr{
+							exprs: []any{
+								&litMatcher{val: "global", want: "\"global\""},
+								&ruleIRefExpr{index: 166 /* sp1x */},
+								&labeledExpr{
+									label: "id",
+									expr:  &ruleIRefExpr{index: 135 /* identifier */},
+								},
+								&ruleIRefExpr{index: 164 /* sp */},
+								&litMatcher{val: "=", want: "\"=\""},
+								&notExpr{
+									expr: &litMatcher{val: "=", want: "\"=\""},
+								},
+								&ruleIRefExpr{index: 164 /* sp */},
+								&ruleIRefExpr{index: 49 /* exprRoot */},
+							},
+						},
+					},
+					&seqExpr{
+						exprs: []any{
+							&actionExpr{
+								run: (*parser).call_onstmtGlobal_15,
+								expr: &seqExpr{
+									exprs: []any{
+										&litMatcher{val: "global", want: "\"global\""},
+										&ruleIRefExpr{index: 166 /* sp1x */},
+										&labeledExpr{
+											label: "id",
+											expr:  &ruleIRefExpr{index: 135 /* identifier */},
+										},
+										&ruleIRefExpr{index: 164 /* sp */},
+									},
+								},
+							},
+							&actionExpr{
+								run: (*parser).call_onstmtGlobal_22,
+								expr: &zeroOrMoreExpr{
+									expr: &actionExpr{
+										run: (*parser).call_onstmtGlobal_24,
+										expr: &seqExpr{
+											exprs: []any{
+												&litMatcher{val: ",", want: "\",\""},
+												&ruleIRefExpr{index: 164 /* sp */},
+												&labeledExpr{
+													label: "id2",
+													expr:  &ruleIRefExpr{index: 135 /* identifier */},
+												},
+												&ruleIRefExpr{index: 164 /* sp */},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:      "stmtImport",
+			varExists: true,
+			expr: &choiceExpr{
+				alternatives: []any{
+					&seqExpr{
+						exprs: []any{
+							&actionExpr{
+								run: (*parser).call_onstmtImport_3,
+								expr: &seqExpr{
+									exprs: []any{
+										&litMatcher{val: "import", want: "\"import\""},
+										&ruleIRefExpr{index: 166 /* sp1x */},
+										&litMatcher{val: "{", want: "\"{\""},
+										&ruleIRefExpr{index: 164 /* sp */},
+									},
+								},
+							},
+							&actionExpr{
+								run: (*parser).call_onstmtImport_9,
+								expr: &seqExpr{
+									exprs: []any{
+										&ruleIRefExpr{index: 13 /* importItem */},
+										&zeroOrMoreExpr{
+											expr: &seqExpr{
+												exprs: []any{
+													&litMatcher{val: ",", want: "\",\""},
+													&ruleIRefExpr{index: 164 /* sp */},
+													&ruleIRefExpr{index: 13 /* importItem */},
+												},
+											},
+										},
+										&litMatcher{val: "}", want: "\"}\""},
+										&ruleIRefExpr{index: 164 /* sp */},
+										&litMatcher{val: "from", want: "\"from\""},
+										&ruleIRefExpr{index: 166 /* sp1x */},
+										&labeledExpr{
+											label: "path",
+											expr:  &ruleIRefExpr{index: 14 /* importPath */},
+										},
+										&ruleIRefExpr{index: 164 /* sp */},
+									},
+								},
+							},
+						},
+					},
+					&actionExpr{
+						run: (*parser).call_onstmtImport_24,
+						expr: &seqExpr{
+							exprs: []any{
+								&litMatcher{val: "import", want: "\"import\""},
+								&ruleIRefExpr{index: 166 /* sp1x */},
+								&labeledExpr{
+									label: "path",
+									expr:  &ruleIRefExpr{index: 14 /* importPath */},
+								},
+								&ruleIRefExpr{index: 164 /* sp */},
+								&litMatcher{val: "as", want: "\"as\""},
+								&ruleIRefExpr{index: 166 /* sp1x */},
+								&labeledExpr{
+									label: "id",
+									expr:  &ruleIRefExpr{index: 135 /* identifier */},
+								},
+								&ruleIRefExpr{index: 164 /* sp */},
+							},
+						},
+					},
+					&actionExpr{
+						run: (*parser).call_onstmtImport_36,
+						expr: &seqExpr{
+							exprs: []any{
+								&litMatcher{val: "import", want: "\"import\""},
+								&ruleIRefExpr{index: 166 /* sp1x */},
+								&labeledExpr{
+									label: "path",
+									expr:  &ruleIRefExpr{index: 14 /* importPath */},
+								},
+								&ruleIRefExpr{index: 164 /* sp */},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:      "importItem",
+			varExists: true,
+			expr: &choiceExpr{
+				alternatives: []any{
+					&actionExpr{
+						run: (*parser).call_onimportItem_2,
+						expr: &seqExpr{
+							exprs: []any{
+								&labeledExpr{
+									label: "id",
+									expr:  &ruleIRefExpr{index: 135 /* identifier */},
+								},
+								&ruleIRefExpr{index: 164 /* sp */},
+								&litMatcher{val: "as", want: "\"as\""},
+								&ruleIRefExpr{index: 166 /* sp1x */},
+								&labeledExpr{
+									label: "alias",
+									expr:  &ruleIRefExpr{index: 135 /* identifier */},
+								},
+								&ruleIRefExpr{index: 164 /* sp */},
+							},
+						},
+					},
+					&actionExpr{
+						run: (*parser).call_onimportItem_12,
+						expr: &seqExpr{
+							exprs: []any{
+								&labeledExpr{
+									label: "id",
+									expr:  &ruleIRefExpr{index: 135 /* identifier */},
+								},
+								&ruleIRefExpr{index: 164 /* sp */},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "importPath",
+			expr: &actionExpr{
+				run: (*parser).call_onimportPath_1,
+				expr: &choiceExpr{
+					alternatives: []any{
+						&seqExpr{
+							exprs: []any{
+								&litMatcher{val: "\"", want: "\"\\\"\""},
+								&zeroOrMoreExpr{
+									expr: &charClassMatcher{
+										val:      "[^\"\\n]",
+										chars:    []rune{'"', '\n'},
+										inverted: true,
+									},
+								},
+								&litMatcher{val: "\"", want: "\"\\\"\""},
+							},
+						},
+						&seqExpr{
+							exprs: []any{
+								&litMatcher{val: "'", want: "\"'\""},
+								&zeroOrMoreExpr{
+									expr: &charClassMatcher{
+										val:      "[^'\\n]",
+										chars:    []rune{'\'', '\n'},
+										inverted: true,
+									},
+								},
+								&litMatcher{val: "'", want: "\"'\""},
+							},
+						},
 					},
 				},
 			},
@@ -250,9 +552,9 @@ var g = &grammar{
 						run: (*parser).call_onstmtReturn_2,
 						expr: &seqExpr{
 							exprs: []any{
-								&litMatcher{val: "return", want: "\"return\""},
-								&ruleIRefExpr{index: 125 /* sp1x */},
-								&ruleIRefExpr{index: 26 /* exprRoot */},
+								&ruleIRefExpr{index: 132 /* kwReturn */},
+								&ruleIRefExpr{index: 166 /* sp1x */},
+								&ruleIRefExpr{index: 49 /* exprRoot */},
 							},
 						},
 					},
@@ -260,8 +562,8 @@ var g = &grammar{
 						run: (*parser).call_onstmtReturn_7,
 						expr: &seqExpr{
 							exprs: []any{
-								&litMatcher{val: "return", want: "\"return\""},
-								&ruleIRefExpr{index: 123 /* sp */},
+								&ruleIRefExpr{index: 132 /* kwReturn */},
+								&ruleIRefExpr{index: 164 /* sp */},
 							},
 						},
 					},
@@ -276,8 +578,8 @@ var g = &grammar{
 						run: (*parser).call_onstmtWhile_2,
 						expr: &seqExpr{
 							exprs: []any{
-								&litMatcher{val: "while", want: "\"while\""},
-								&ruleIRefExpr{index: 125 /* sp1x */},
+								&ruleIRefExpr{index: 130 /* kwWhile */},
+								&ruleIRefExpr{index: 166 /* sp1x */},
 							},
 						},
 					},
@@ -285,14 +587,14 @@ var g = &grammar{
 						run: (*parser).call_onstmtWhile_6,
 						expr: &seqExpr{
 							exprs: []any{
-								&ruleIRefExpr{index: 26 /* exprRoot */},
-								&ruleIRefExpr{index: 123 /* sp */},
+								&ruleIRefExpr{index: 49 /* exprRoot */},
+								&ruleIRefExpr{index: 164 /* sp */},
 							},
 						},
 					},
 					&actionExpr{
 						run:  (*parser).call_onstmtWhile_10,
-						expr: &ruleIRefExpr{index: 12 /* block */},
+						expr: &ruleIRefExpr{index: 17 /* block */},
 					},
 				},
 			},
@@ -306,200 +608,985 @@ var g = &grammar{
 							&seqExpr{
 								exprs: []any{
 									&litMatcher{val: "{", want: "\"{\""},
-									&ruleIRefExpr{index: 123 /* sp */},
+									&ruleIRefExpr{index: 164 /* sp */},
 									&litMatcher{val: "}", want: "\"}\""},
 								},
 							},
 							&seqExpr{
 								exprs: []any{
 									&litMatcher{val: "{", want: "\"{\""},
-									&ruleIRefExpr{index: 123 /* sp */},
+									&ruleIRefExpr{index: 164 /* sp */},
 									&ruleIRefExpr{index: 2 /* stmtRoot */},
 									&litMatcher{val: "}", want: "\"}\""},
 								},
 							},
 						},
 					},
-					&ruleIRefExpr{index: 123 /* sp */},
+					&ruleIRefExpr{index: 164 /* sp */},
 				},
 			},
 		},
 		{
-			name: "stmtElse",
-			expr: &seqExpr{
-				exprs: []any{
-					&litMatcher{val: "else", want: "\"else\""},
-					&choiceExpr{
-						alternatives: []any{
-							&seqExpr{
-								exprs: []any{
-									&ruleIRefExpr{index: 123 /* sp */},
-									&ruleIRefExpr{index: 12 /* block */},
-								},
-							},
-							&seqExpr{
+			name:      "stmtThrow",
+			varExists: true,
+			expr: &actionExpr{
+				run: (*parser).call_onstmtThrow_1,
+				expr: &seqExpr{
+					exprs: []any{
+						&andExpr{
+							expr: &seqExpr{
 								exprs: []any{
-									&ruleIRefExpr{index: 125 /* sp1x */},
-									&ruleIRefExpr{index: 14 /* stmtIf */},
+									&litMatcher{val: "throw", want: "\"throw\""},
+									&ruleIRefExpr{index: 166 /* sp1x */},
 								},
 							},
 						},
+						&ruleIRefExpr{index: 70 /* detailStart */},
+						&litMatcher{val: "throw", want: "\"throw\""},
+						&ruleIRefExpr{index: 166 /* sp1x */},
+						&labeledExpr{
+							label:       "text",
+							expr:        &ruleIRefExpr{index: 49 /* exprRoot */},
+							textCapture: true,
+						},
 					},
 				},
 			},
 		},
 		{
-			name: "stmtIf",
+			name: "stmtTry",
 			expr: &seqExpr{
 				exprs: []any{
-					&litMatcher{val: "if", want: "\"if\""},
-					&ruleIRefExpr{index: 125 /* sp1x */},
-					&choiceExpr{
-						alternatives: []any{
-							&seqExpr{
-								exprs: []any{
-									&actionExpr{
-										run: (*parser).call_onstmtIf_6,
-										expr: &seqExpr{
-											exprs: []any{
-												&ruleIRefExpr{index: 26 /* exprRoot */},
-												&ruleIRefExpr{index: 123 /* sp */},
-											},
-										},
-									},
-									&actionExpr{
-										run:  (*parser).call_onstmtIf_10,
-										expr: &ruleIRefExpr{index: 12 /* block */},
-									},
-									&actionExpr{
-										run: (*parser).call_onstmtIf_12,
-										expr: &zeroOrOneExpr{
-											expr: &ruleIRefExpr{index: 13 /* stmtElse */},
-										},
-									},
+					&actionExpr{
+						run: (*parser).call_onstmtTry_2,
+						expr: &seqExpr{
+							exprs: []any{
+								&litMatcher{val: "try", want: "\"try\""},
+								&ruleIRefExpr{index: 164 /* sp */},
+								&andExpr{
+									expr: &litMatcher{val: "{", want: "\"{\""},
 								},
 							},
-							&andCodeExpr{run: (*parser).call_onstmtIf_15},
 						},
 					},
+					&actionExpr{
+						run:  (*parser).call_onstmtTry_8,
+						expr: &ruleIRefExpr{index: 17 /* block */},
+					},
+					&actionExpr{
+						run:  (*parser).call_onstmtTry_10,
+						expr: &ruleIRefExpr{index: 20 /* stmtCatch */},
+					},
 				},
 			},
 		},
 		{
-			name:      "func_def_params",
+			name:      "stmtCatch",
 			varExists: true,
 			expr: &choiceExpr{
 				alternatives: []any{
-					&actionExpr{
-						run: (*parser).call_onfunc_def_params_2,
-						expr: &seqExpr{
-							exprs: []any{
-								&litMatcher{val: "(", want: "\"(\""},
-								&ruleIRefExpr{index: 123 /* sp */},
-								&litMatcher{val: ")", want: "\")\""},
-								&ruleIRefExpr{index: 123 /* sp */},
-							},
-						},
-					},
 					&seqExpr{
 						exprs: []any{
 							&actionExpr{
-								run: (*parser).call_onfunc_def_params_9,
+								run: (*parser).call_onstmtCatch_3,
 								expr: &seqExpr{
 									exprs: []any{
+										&litMatcher{val: "catch", want: "\"catch\""},
+										&ruleIRefExpr{index: 164 /* sp */},
 										&litMatcher{val: "(", want: "\"(\""},
-										&ruleIRefExpr{index: 123 /* sp */},
-									},
-								},
-							},
-							&actionExpr{
-								run: (*parser).call_onfunc_def_params_13,
-								expr: &seqExpr{
-									exprs: []any{
+										&ruleIRefExpr{index: 164 /* sp */},
 										&labeledExpr{
 											label: "id",
-											expr:  &ruleIRefExpr{index: 98 /* identifier */},
+											expr:  &ruleIRefExpr{index: 135 /* identifier */},
 										},
-										&ruleIRefExpr{index: 123 /* sp */},
+										&ruleIRefExpr{index: 164 /* sp */},
+										&litMatcher{val: ")", want: "\")\""},
+										&ruleIRefExpr{index: 164 /* sp */},
 									},
 								},
 							},
-							&seqExpr{
-								exprs: []any{
-									&zeroOrMoreExpr{
-										expr: &seqExpr{
-											exprs: []any{
-												&actionExpr{
-													run: (*parser).call_onfunc_def_params_21,
-													expr: &seqExpr{
-														exprs: []any{
-															&litMatcher{val: ",", want: "\",\""},
-															&ruleIRefExpr{index: 123 /* sp */},
-															&labeledExpr{
-																label: "id2",
-																expr:  &ruleIRefExpr{index: 98 /* identifier */},
+							&ruleIRefExpr{index: 17 /* block */},
+						},
+					},
+					&seqExpr{
+						exprs: []any{
+							&litMatcher{val: "catch", want: "\"catch\""},
+							&ruleIRefExpr{index: 164 /* sp */},
+							&andExpr{
+								expr: &litMatcher{val: "{", want: "\"{\""},
+							},
+							&ruleIRefExpr{index: 17 /* block */},
+						},
+					},
+					&andCodeExpr{run: (*parser).call_onstmtCatch_21},
+				},
+			},
+		},
+		{
+			name: "stmtElse",
+			expr: &seqExpr{
+				exprs: []any{
+					&ruleIRefExpr{index: 129 /* kwElse */},
+					&choiceExpr{
+						alternatives: []any{
+							&seqExpr{
+								exprs: []any{
+									&ruleIRefExpr{index: 164 /* sp */},
+									&ruleIRefExpr{index: 17 /* block */},
+								},
+							},
+							&seqExpr{
+								exprs: []any{
+									&ruleIRefExpr{index: 166 /* sp1x */},
+									&ruleIRefExpr{index: 22 /* stmtIf */},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "stmtIf",
+			expr: &choiceExpr{
+				alternatives: []any{
+					&seqExpr{
+						exprs: []any{
+							&actionExpr{
+								run: (*parser).call_onstmtIf_3,
+								expr: &seqExpr{
+									exprs: []any{
+										&ruleIRefExpr{index: 128 /* kwIf */},
+										&ruleIRefExpr{index: 166 /* sp1x */},
+										&ruleIRefExpr{index: 49 /* exprRoot */},
+										&ruleIRefExpr{index: 164 /* sp */},
+									},
+								},
+							},
+							&actionExpr{
+								run:  (*parser).call_onstmtIf_9,
+								expr: &ruleIRefExpr{index: 17 /* block */},
+							},
+							&actionExpr{
+								run: (*parser).call_onstmtIf_11,
+								expr: &zeroOrOneExpr{
+									expr: &ruleIRefExpr{index: 21 /* stmtElse */},
+								},
+							},
+						},
+					},
+					&seqExpr{
+						exprs: []any{
+							&litMatcher{val: "if", want: "\"if\""},
+							&ruleIRefExpr{index: 166 /* sp1x */},
+							&andCodeExpr{run: (*parser).call_onstmtIf_17},
+						},
+					},
+					&seqExpr{
+						exprs: []any{
+							&andCodeExpr{run: (*parser).call_onstmtIf_19},
+							&litMatcher{val: "如果", want: "\"如果\""},
+							&ruleIRefExpr{index: 166 /* sp1x */},
+							&andCodeExpr{run: (*parser).call_onstmtIf_22},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "stmtMatch",
+			expr: &choiceExpr{
+				alternatives: []any{
+					&seqExpr{
+						exprs: []any{
+							&actionExpr{
+								run: (*parser).call_onstmtMatch_3,
+								expr: &seqExpr{
+									exprs: []any{
+										&andExpr{
+											expr: &seqExpr{
+												exprs: []any{
+													&litMatcher{val: "match", want: "\"match\""},
+													&ruleIRefExpr{index: 166 /* sp1x */},
+													&ruleIRefExpr{index: 49 /* exprRoot */},
+													&ruleIRefExpr{index: 164 /* sp */},
+													&litMatcher{val: "{", want: "\"{\""},
+												},
+											},
+										},
+										&litMatcher{val: "match", want: "\"match\""},
+										&ruleIRefExpr{index: 166 /* sp1x */},
+										&ruleIRefExpr{index: 49 /* exprRoot */},
+										&ruleIRefExpr{index: 164 /* sp */},
+										&litMatcher{val: "{", want: "\"{\""},
+										&ruleIRefExpr{index: 164 /* sp */},
+									},
+								},
+							},
+							&actionExpr{
+								run: (*parser).call_onstmtMatch_18,
+								expr: &seqExpr{
+									exprs: []any{
+										&zeroOrMoreExpr{
+											expr: &seqExpr{
+												exprs: []any{
+													&ruleIRefExpr{index: 24 /* matchArm */},
+													&zeroOrOneExpr{
+														expr: &choiceExpr{
+															alternatives: []any{
+																&seqExpr{
+																	exprs: []any{
+																		&litMatcher{val: ",", want: "\",\""},
+																		&ruleIRefExpr{index: 164 /* sp */},
+																	},
+																},
+																&seqExpr{
+																	exprs: []any{
+																		&litMatcher{val: ";", want: "\";\""},
+																		&ruleIRefExpr{index: 164 /* sp */},
+																	},
+																},
 															},
-															&ruleIRefExpr{index: 123 /* sp */},
 														},
 													},
 												},
-												&codeExpr{
-													run: (*parser).call_onfunc_def_params_28,
-												},
 											},
 										},
+										&litMatcher{val: "}", want: "\"}\""},
+										&ruleIRefExpr{index: 164 /* sp */},
+									},
+								},
+							},
+						},
+					},
+					&seqExpr{
+						exprs: []any{
+							&andExpr{
+								expr: &seqExpr{
+									exprs: []any{
+										&litMatcher{val: "match", want: "\"match\""},
+										&ruleIRefExpr{index: 166 /* sp1x */},
+										&ruleIRefExpr{index: 49 /* exprRoot */},
+										&ruleIRefExpr{index: 164 /* sp */},
+										&litMatcher{val: "{", want: "\"{\""},
 									},
-									&litMatcher{val: ")", want: "\")\""},
-									&ruleIRefExpr{index: 123 /* sp */},
 								},
 							},
+							&andCodeExpr{run: (*parser).call_onstmtMatch_41},
 						},
 					},
 				},
 			},
 		},
 		{
-			name:      "stmtFunc",
-			varExists: true,
+			name: "matchArm",
 			expr: &seqExpr{
 				exprs: []any{
 					&actionExpr{
-						run: (*parser).call_onstmtFunc_2,
+						run: (*parser).call_onmatchArm_2,
+						expr: &seqExpr{
+							exprs: []any{
+								&ruleIRefExpr{index: 25 /* matchPattern */},
+								&ruleIRefExpr{index: 164 /* sp */},
+								&zeroOrOneExpr{
+									expr: &ruleIRefExpr{index: 27 /* matchGuard */},
+								},
+								&litMatcher{val: "=>", want: "\"=>\""},
+								&ruleIRefExpr{index: 164 /* sp */},
+							},
+						},
+					},
+					&actionExpr{
+						run:  (*parser).call_onmatchArm_10,
+						expr: &ruleIRefExpr{index: 28 /* matchBody */},
+					},
+				},
+			},
+		},
+		{
+			name:      "matchPattern",
+			varExists: true,
+			expr: &choiceExpr{
+				alternatives: []any{
+					&seqExpr{
+						exprs: []any{
+							&litMatcher{val: "_", want: "\"_\""},
+							&notExpr{
+								expr: &ruleIRefExpr{index: 138 /* xidContinue */},
+							},
+						},
+					},
+					&actionExpr{
+						run: (*parser).call_onmatchPattern_6,
+						expr: &seqExpr{
+							exprs: []any{
+								&litMatcher{val: "[", want: "\"[\""},
+								&ruleIRefExpr{index: 164 /* sp */},
+								&ruleIRefExpr{index: 26 /* matchArrayItem */},
+								&zeroOrMoreExpr{
+									expr: &seqExpr{
+										exprs: []any{
+											&litMatcher{val: ",", want: "\",\""},
+											&ruleIRefExpr{index: 164 /* sp */},
+											&ruleIRefExpr{index: 26 /* matchArrayItem */},
+										},
+									},
+								},
+								&litMatcher{val: "]", want: "\"]\""},
+							},
+						},
+					},
+					&seqExpr{
+						exprs: []any{
+							&actionExpr{
+								run: (*parser).call_onmatchPattern_18,
+								expr: &andExpr{
+									expr: &seqExpr{
+										exprs: []any{
+											&ruleIRefExpr{index: 63 /* exprAdditive */},
+											&ruleIRefExpr{index: 164 /* sp */},
+											&litMatcher{val: "..", want: "\"..\""},
+										},
+									},
+								},
+							},
+							&actionExpr{
+								run: (*parser).call_onmatchPattern_24,
+								expr: &seqExpr{
+									exprs: []any{
+										&ruleIRefExpr{index: 63 /* exprAdditive */},
+										&ruleIRefExpr{index: 164 /* sp */},
+										&litMatcher{val: "..", want: "\"..\""},
+										&ruleIRefExpr{index: 164 /* sp */},
+									},
+								},
+							},
+							&actionExpr{
+								run:  (*parser).call_onmatchPattern_30,
+								expr: &ruleIRefExpr{index: 63 /* exprAdditive */},
+							},
+						},
+					},
+					&actionExpr{
+						run: (*parser).call_onmatchPattern_32,
 						expr: &seqExpr{
 							exprs: []any{
-								&litMatcher{val: "func", want: "\"func\""},
-								&ruleIRefExpr{index: 125 /* sp1x */},
+								&andExpr{
+									expr: &seqExpr{
+										exprs: []any{
+											&ruleIRefExpr{index: 135 /* identifier */},
+											&ruleIRefExpr{index: 164 /* sp */},
+											&choiceExpr{
+												alternatives: []any{
+													&litMatcher{val: "=>", want: "\"=>\""},
+													&seqExpr{
+														exprs: []any{
+															&ruleIRefExpr{index: 128 /* kwIf */},
+															&ruleIRefExpr{index: 166 /* sp1x */},
+														},
+													},
+												},
+											},
+										},
+									},
+								},
 								&labeledExpr{
 									label: "id",
-									expr:  &ruleIRefExpr{index: 98 /* identifier */},
+									expr:  &ruleIRefExpr{index: 135 /* identifier */},
+								},
+							},
+						},
+					},
+					&seqExpr{
+						exprs: []any{
+							&actionExpr{
+								run: (*parser).call_onmatchPattern_46,
+								expr: &andExpr{
+									expr: &ruleIRefExpr{index: 63 /* exprAdditive */},
 								},
-								&ruleIRefExpr{index: 123 /* sp */},
+							},
+							&actionExpr{
+								run:  (*parser).call_onmatchPattern_49,
+								expr: &ruleIRefExpr{index: 63 /* exprAdditive */},
 							},
 						},
 					},
+				},
+			},
+		},
+		{
+			name: "matchArrayItem",
+			expr: &seqExpr{
+				exprs: []any{
 					&actionExpr{
-						run: (*parser).call_onstmtFunc_9,
+						run: (*parser).call_onmatchArrayItem_2,
+						expr: &andExpr{
+							expr: &ruleIRefExpr{index: 63 /* exprAdditive */},
+						},
+					},
+					&actionExpr{
+						run: (*parser).call_onmatchArrayItem_5,
 						expr: &seqExpr{
 							exprs: []any{
-								&ruleIRefExpr{index: 15 /* func_def_params */},
-								&litMatcher{val: "{", want: "\"{\""},
-								&ruleIRefExpr{index: 123 /* sp */},
+								&ruleIRefExpr{index: 63 /* exprAdditive */},
+								&ruleIRefExpr{index: 164 /* sp */},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "matchGuard",
+			expr: &actionExpr{
+				run: (*parser).call_onmatchGuard_1,
+				expr: &seqExpr{
+					exprs: []any{
+						&ruleIRefExpr{index: 128 /* kwIf */},
+						&ruleIRefExpr{index: 166 /* sp1x */},
+						&ruleIRefExpr{index: 58 /* exprLogicOr */},
+						&ruleIRefExpr{index: 164 /* sp */},
+					},
+				},
+			},
+		},
+		{
+			name: "matchBody",
+			expr: &choiceExpr{
+				alternatives: []any{
+					&seqExpr{
+						exprs: []any{
+							&actionExpr{
+								run: (*parser).call_onmatchBody_3,
+								expr: &andExpr{
+									expr: &ruleIRefExpr{index: 17 /* block */},
+								},
 							},
+							&actionExpr{
+								run:  (*parser).call_onmatchBody_6,
+								expr: &ruleIRefExpr{index: 17 /* block */},
+							},
+						},
+					},
+					&ruleIRefExpr{index: 23 /* stmtMatch */},
+					&ruleIRefExpr{index: 15 /* stmtReturn */},
+					&ruleIRefExpr{index: 8 /* stmtBreak */},
+					&ruleIRefExpr{index: 9 /* stmtContinue */},
+					&seqExpr{
+						exprs: []any{
+							&ruleIRefExpr{index: 49 /* exprRoot */},
+							&ruleIRefExpr{index: 164 /* sp */},
 						},
 					},
+				},
+			},
+		},
+		{
+			name: "func_def_params",
+			expr: &choiceExpr{
+				alternatives: []any{
 					&actionExpr{
-						run: (*parser).call_onstmtFunc_14,
+						run: (*parser).call_onfunc_def_params_2,
 						expr: &seqExpr{
 							exprs: []any{
-								&labeledExpr{
-									label: "exprText",
-									expr: &zeroOrOneExpr{
-										expr: &ruleIRefExpr{index: 2 /* stmtRoot */},
+								&litMatcher{val: "(", want: "\"(\""},
+								&ruleIRefExpr{index: 164 /* sp */},
+								&litMatcher{val: ")", want: "\")\""},
+								&ruleIRefExpr{index: 164 /* sp */},
+							},
+						},
+					},
+					&seqExpr{
+						exprs: []any{
+							&actionExpr{
+								run: (*parser).call_onfunc_def_params_9,
+								expr: &seqExpr{
+									exprs: []any{
+										&litMatcher{val: "(", want: "\"(\""},
+										&ruleIRefExpr{index: 164 /* sp */},
+									},
+								},
+							},
+							&seqExpr{
+								exprs: []any{
+									&ruleIRefExpr{index: 30 /* func_def_param */},
+									&zeroOrMoreExpr{
+										expr: &seqExpr{
+											exprs: []any{
+												&litMatcher{val: ",", want: "\",\""},
+												&ruleIRefExpr{index: 164 /* sp */},
+												&ruleIRefExpr{index: 30 /* func_def_param */},
+											},
+										},
+									},
+									&litMatcher{val: ")", want: "\")\""},
+									&ruleIRefExpr{index: 164 /* sp */},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:      "func_def_param",
+			varExists: true,
+			expr: &choiceExpr{
+				alternatives: []any{
+					&actionExpr{
+						run: (*parser).call_onfunc_def_param_2,
+						expr: &seqExpr{
+							exprs: []any{
+								&litMatcher{val: "...", want: "\"...\""},
+								&ruleIRefExpr{index: 164 /* sp */},
+								&labeledExpr{
+									label: "id",
+									expr:  &ruleIRefExpr{index: 135 /* identifier */},
+								},
+								&ruleIRefExpr{index: 164 /* sp */},
+								&andExpr{
+									expr: &litMatcher{val: ")", want: "\")\""},
+								},
+							},
+						},
+					},
+					&seqExpr{
+						exprs: []any{
+							&litMatcher{val: "...", want: "\"...\""},
+							&andCodeExpr{run: (*parser).call_onfunc_def_param_13},
+						},
+					},
+					&seqExpr{
+						exprs: []any{
+							&actionExpr{
+								run: (*parser).call_onfunc_def_param_15,
+								expr: &seqExpr{
+									exprs: []any{
+										&labeledExpr{
+											label: "id",
+											expr:  &ruleIRefExpr{index: 135 /* identifier */},
+										},
+										&ruleIRefExpr{index: 164 /* sp */},
+										&litMatcher{val: "=", want: "\"=\""},
+										&ruleIRefExpr{index: 164 /* sp */},
+									},
+								},
+							},
+							&actionExpr{
+								run: (*parser).call_onfunc_def_param_22,
+								expr: &seqExpr{
+									exprs: []any{
+										&labeledExpr{
+											label:       "expr",
+											expr:        &ruleIRefExpr{index: 49 /* exprRoot */},
+											textCapture: true,
+										},
+										&ruleIRefExpr{index: 164 /* sp */},
+									},
+								},
+							},
+						},
+					},
+					&actionExpr{
+						run: (*parser).call_onfunc_def_param_27,
+						expr: &seqExpr{
+							exprs: []any{
+								&labeledExpr{
+									label: "id",
+									expr:  &ruleIRefExpr{index: 135 /* identifier */},
+								},
+								&ruleIRefExpr{index: 164 /* sp */},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:      "stmtFunc",
+			varExists: true,
+			expr: &seqExpr{
+				exprs: []any{
+					&actionExpr{
+						run: (*parser).call_onstmtFunc_2,
+						expr: &seqExpr{
+							exprs: []any{
+								&ruleIRefExpr{index: 131 /* kwFunc */},
+								&ruleIRefExpr{index: 166 /* sp1x */},
+								&labeledExpr{
+									label: "id",
+									expr:  &ruleIRefExpr{index: 135 /* identifier */},
+								},
+								&ruleIRefExpr{index: 164 /* sp */},
+							},
+						},
+					},
+					&actionExpr{
+						run: (*parser).call_onstmtFunc_9,
+						expr: &seqExpr{
+							exprs: []any{
+								&ruleIRefExpr{index: 29 /* func_def_params */},
+								&litMatcher{val: "{", want: "\"{\""},
+								&ruleIRefExpr{index: 164 /* sp */},
+							},
+						},
+					},
+					&actionExpr{
+						run: (*parser).call_onstmtFunc_14,
+						expr: &seqExpr{
+							exprs: []any{
+								&labeledExpr{
+									label: "exprText",
+									expr: &zeroOrOneExpr{
+										expr: &ruleIRefExpr{index: 2 /* stmtRoot */},
+									},
+									textCapture: true,
+								},
+								&litMatcher{val: "}", want: "\"}\""},
+								&ruleIRefExpr{index: 164 /* sp */},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:      "stmtAssignType1",
+			varExists: true,
+			expr: &seqExpr{
+				exprs: []any{
+					&actionExpr{
+						run: (*parser).call_onstmtAssignType1_2,
+						expr: &seqExpr{
+							exprs: []any{
+								&labeledExpr{
+									label: "id",
+									expr:  &ruleIRefExpr{index: 135 /* identifier */},
+								},
+								&ruleIRefExpr{index: 164 /* sp */},
+							},
+						},
+					},
+					&actionExpr{
+						run: (*parser).call_onstmtAssignType1_7,
+						expr: &seqExpr{
+							exprs: []any{
+								&litMatcher{val: "=", want: "\"=\""},
+								&ruleIRefExpr{index: 164 /* sp */},
+								&ruleIRefExpr{index: 49 /* exprRoot */},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:      "stmtAssignType2",
+			varExists: true,
+			expr: &seqExpr{
+				exprs: []any{
+					&actionExpr{
+						run: (*parser).call_onstmtAssignType2_2,
+						expr: &seqExpr{
+							exprs: []any{
+								&litMatcher{val: "&", want: "\"&\""},
+								&labeledExpr{
+									label: "id",
+									expr:  &ruleIRefExpr{index: 135 /* identifier */},
+								},
+								&ruleIRefExpr{index: 164 /* sp */},
+							},
+						},
+					},
+					&actionExpr{
+						run: (*parser).call_onstmtAssignType2_8,
+						expr: &seqExpr{
+							exprs: []any{
+								&litMatcher{val: "=", want: "\"=\""},
+								&ruleIRefExpr{index: 164 /* sp */},
+							},
+						},
+					},
+					&actionExpr{
+						run: (*parser).call_onstmtAssignType2_12,
+						expr: &labeledExpr{
+							label:       "expr",
+							expr:        &ruleIRefExpr{index: 49 /* exprRoot */},
+							textCapture: true,
+						},
+					},
+				},
+			},
+		},
+		{
+			name:      "stmtAssignType3",
+			varExists: true,
+			expr: &seqExpr{
+				exprs: []any{
+					&actionExpr{
+						run: (*parser).call_onstmtAssignType3_2,
+						expr: &seqExpr{
+							exprs: []any{
+								&litMatcher{val: "&", want: "\"&\""},
+								&labeledExpr{
+									label: "id",
+									expr:  &ruleIRefExpr{index: 135 /* identifier */},
+								},
+								&ruleIRefExpr{index: 164 /* sp */},
+							},
+						},
+					},
+					&actionExpr{
+						run: (*parser).call_onstmtAssignType3_8,
+						expr: &seqExpr{
+							exprs: []any{
+								&litMatcher{val: ".", want: "\".\""},
+								&labeledExpr{
+									label: "id2",
+									expr:  &ruleIRefExpr{index: 135 /* identifier */},
+								},
+								&ruleIRefExpr{index: 164 /* sp */},
+							},
+						},
+					},
+					&actionExpr{
+						run: (*parser).call_onstmtAssignType3_14,
+						expr: &seqExpr{
+							exprs: []any{
+								&ruleIRefExpr{index: 164 /* sp */},
+								&litMatcher{val: "=", want: "\"=\""},
+								&ruleIRefExpr{index: 164 /* sp */},
+								&ruleIRefExpr{index: 49 /* exprRoot */},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:      "stmtAssignType4",
+			varExists: true,
+			expr: &seqExpr{
+				exprs: []any{
+					&actionExpr{
+						run: (*parser).call_onstmtAssignType4_2,
+						expr: &seqExpr{
+							exprs: []any{
+								&litMatcher{val: "this", want: "\"this\""},
+								&ruleIRefExpr{index: 164 /* sp */},
+								&litMatcher{val: ".", want: "\".\""},
+								&ruleIRefExpr{index: 164 /* sp */},
+								&labeledExpr{
+									label: "id",
+									expr:  &ruleIRefExpr{index: 135 /* identifier */},
+								},
+								&ruleIRefExpr{index: 164 /* sp */},
+							},
+						},
+					},
+					&actionExpr{
+						run: (*parser).call_onstmtAssignType4_11,
+						expr: &seqExpr{
+							exprs: []any{
+								&litMatcher{val: "=", want: "\"=\""},
+								&ruleIRefExpr{index: 164 /* sp */},
+								&ruleIRefExpr{index: 49 /* exprRoot */},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:      "stmtAssignType5",
+			varExists: true,
+			expr: &seqExpr{
+				exprs: []any{
+					&actionExpr{
+						run: (*parser).call_onstmtAssignType5_2,
+						expr: &seqExpr{
+							exprs: []any{
+								&labeledExpr{
+									label: "id",
+									expr:  &ruleIRefExpr{index: 135 /* identifier */},
+								},
+								&ruleIRefExpr{index: 164 /* sp */},
+							},
+						},
+					},
+					&actionExpr{
+						run: (*parser).call_onstmtAssignType5_7,
+						expr: &seqExpr{
+							exprs: []any{
+								&litMatcher{val: ".", want: "\".\""},
+								&ruleIRefExpr{index: 164 /* sp */},
+								&labeledExpr{
+									label: "id2",
+									expr:  &ruleIRefExpr{index: 135 /* identifier */},
+								},
+								&ruleIRefExpr{index: 164 /* sp */},
+							},
+						},
+					},
+					&actionExpr{
+						run: (*parser).call_onstmtAssignType5_14,
+						expr: &seqExpr{
+							exprs: []any{
+								&litMatcher{val: "=", want: "\"=\""},
+								&ruleIRefExpr{index: 164 /* sp */},
+								&ruleIRefExpr{index: 49 /* exprRoot */},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "stmtAssignType6",
+			expr: &actionExpr{
+				run: (*parser).call_onstmtAssignType6_1,
+				expr: &seqExpr{
+					exprs: []any{
+						&ruleIRefExpr{index: 53 /* exprSlice */},
+						&litMatcher{val: "[", want: "\"[\""},
+						&ruleIRefExpr{index: 164 /* sp */},
+						&ruleIRefExpr{index: 49 /* exprRoot */},
+						&litMatcher{val: "]", want: "\"]\""},
+						&ruleIRefExpr{index: 164 /* sp */},
+						&litMatcher{val: "=", want: "\"=\""},
+						&ruleIRefExpr{index: 164 /* sp */},
+						&ruleIRefExpr{index: 49 /* exprRoot */},
+					},
+				},
+			},
+		},
+		{
+			name: "stmtAssignType7",
+			expr: &actionExpr{
+				run: (*parser).call_onstmtAssignType7_1,
+				expr: &seqExpr{
+					exprs: []any{
+						&ruleIRefExpr{index: 53 /* exprSlice */},
+						&ruleIRefExpr{index: 51 /* _sliceSuffix */},
+						&litMatcher{val: "=", want: "\"=\""},
+						&ruleIRefExpr{index: 164 /* sp */},
+						&ruleIRefExpr{index: 49 /* exprRoot */},
+					},
+				},
+			},
+		},
+		{
+			name:      "stmtAssignType8",
+			varExists: true,
+			expr: &actionExpr{
+				run: (*parser).call_onstmtAssignType8_1,
+				expr: &seqExpr{
+					exprs: []any{
+						&labeledExpr{
+							label: "id",
+							expr:  &ruleIRefExpr{index: 135 /* identifier */},
+						},
+						&ruleIRefExpr{index: 164 /* sp */},
+						&labeledExpr{
+							label:       "op",
+							expr:        &ruleIRefExpr{index: 163 /* assignOp */},
+							textCapture: true,
+						},
+						&ruleIRefExpr{index: 164 /* sp */},
+						&ruleIRefExpr{index: 49 /* exprRoot */},
+					},
+				},
+			},
+		},
+		{
+			name:      "stmtAssignType9",
+			varExists: true,
+			expr: &actionExpr{
+				run: (*parser).call_onstmtAssignType9_1,
+				expr: &seqExpr{
+					exprs: []any{
+						&labeledExpr{
+							label: "id",
+							expr:  &ruleIRefExpr{index: 135 /* identifier */},
+						},
+						&ruleIRefExpr{index: 164 /* sp */},
+						&litMatcher{val: ".", want: "\".\""},
+						&ruleIRefExpr{index: 164 /* sp */},
+						&labeledExpr{
+							label: "id2",
+							expr:  &ruleIRefExpr{index: 135 /* identifier */},
+						},
+						&ruleIRefExpr{index: 164 /* sp */},
+						&labeledExpr{
+							label:       "op",
+							expr:        &ruleIRefExpr{index: 163 /* assignOp */},
+							textCapture: true,
+						},
+						&ruleIRefExpr{index: 164 /* sp */},
+						&ruleIRefExpr{index: 49 /* exprRoot */},
+					},
+				},
+			},
+		},
+		{
+			name:      "stmtAssignType10",
+			varExists: true,
+			expr: &actionExpr{
+				run: (*parser).call_onstmtAssignType10_1,
+				expr: &seqExpr{
+					exprs: []any{
+						&ruleIRefExpr{index: 53 /* exprSlice */},
+						&litMatcher{val: "[", want: "\"[\""},
+						&ruleIRefExpr{index: 164 /* sp */},
+						&ruleIRefExpr{index: 49 /* exprRoot */},
+						&litMatcher{val: "]", want: "\"]\""},
+						&ruleIRefExpr{index: 164 /* sp */},
+						&labeledExpr{
+							label:       "op",
+							expr:        &ruleIRefExpr{index: 163 /* assignOp */},
+							textCapture: true,
+						},
+						&ruleIRefExpr{index: 164 /* sp */},
+						&ruleIRefExpr{index: 49 /* exprRoot */},
+					},
+				},
+			},
+		},
+		{
+			name: "stmtAssignType11",
+			expr: &seqExpr{
+				exprs: []any{
+					&actionExpr{
+						run: (*parser).call_onstmtAssignType11_2,
+						expr: &seqExpr{
+							exprs: []any{
+								&litMatcher{val: "[", want: "\"[\""},
+								&ruleIRefExpr{index: 164 /* sp */},
+							},
+						},
+					},
+					&actionExpr{
+						run: (*parser).call_onstmtAssignType11_6,
+						expr: &seqExpr{
+							exprs: []any{
+								&ruleIRefExpr{index: 44 /* destructItem */},
+								&zeroOrMoreExpr{
+									expr: &seqExpr{
+										exprs: []any{
+											&litMatcher{val: ",", want: "\",\""},
+											&ruleIRefExpr{index: 164 /* sp */},
+											&ruleIRefExpr{index: 44 /* destructItem */},
+										},
 									},
-									textCapture: true,
 								},
-								&litMatcher{val: "}", want: "\"}\""},
-								&ruleIRefExpr{index: 123 /* sp */},
+								&litMatcher{val: "]", want: "\"]\""},
+								&ruleIRefExpr{index: 164 /* sp */},
+								&litMatcher{val: "=", want: "\"=\""},
+								&notExpr{
+									expr: &litMatcher{val: "=", want: "\"=\""},
+								},
+								&ruleIRefExpr{index: 164 /* sp */},
+								&ruleIRefExpr{index: 49 /* exprRoot */},
 							},
 						},
 					},
@@ -507,29 +1594,40 @@ var g = &grammar{
 			},
 		},
 		{
-			name:      "stmtAssignType1",
-			varExists: true,
+			name: "stmtAssignType12",
 			expr: &seqExpr{
 				exprs: []any{
 					&actionExpr{
-						run: (*parser).call_onstmtAssignType1_2,
+						run: (*parser).call_onstmtAssignType12_2,
 						expr: &seqExpr{
 							exprs: []any{
-								&labeledExpr{
-									label: "id",
-									expr:  &ruleIRefExpr{index: 98 /* identifier */},
-								},
-								&ruleIRefExpr{index: 123 /* sp */},
+								&litMatcher{val: "{", want: "\"{\""},
+								&ruleIRefExpr{index: 164 /* sp */},
 							},
 						},
 					},
 					&actionExpr{
-						run: (*parser).call_onstmtAssignType1_7,
+						run: (*parser).call_onstmtAssignType12_6,
 						expr: &seqExpr{
 							exprs: []any{
+								&ruleIRefExpr{index: 45 /* destructDictItem */},
+								&zeroOrMoreExpr{
+									expr: &seqExpr{
+										exprs: []any{
+											&litMatcher{val: ",", want: "\",\""},
+											&ruleIRefExpr{index: 164 /* sp */},
+											&ruleIRefExpr{index: 45 /* destructDictItem */},
+										},
+									},
+								},
+								&litMatcher{val: "}", want: "\"}\""},
+								&ruleIRefExpr{index: 164 /* sp */},
 								&litMatcher{val: "=", want: "\"=\""},
-								&ruleIRefExpr{index: 123 /* sp */},
-								&ruleIRefExpr{index: 26 /* exprRoot */},
+								&notExpr{
+									expr: &litMatcher{val: "=", want: "\"=\""},
+								},
+								&ruleIRefExpr{index: 164 /* sp */},
+								&ruleIRefExpr{index: 49 /* exprRoot */},
 							},
 						},
 					},
@@ -537,82 +1635,89 @@ var g = &grammar{
 			},
 		},
 		{
-			name:      "stmtAssignType2",
+			name:      "destructItem",
 			varExists: true,
-			expr: &seqExpr{
-				exprs: []any{
+			expr: &choiceExpr{
+				alternatives: []any{
 					&actionExpr{
-						run: (*parser).call_onstmtAssignType2_2,
+						run: (*parser).call_ondestructItem_2,
 						expr: &seqExpr{
 							exprs: []any{
-								&litMatcher{val: "&", want: "\"&\""},
+								&litMatcher{val: "...", want: "\"...\""},
+								&ruleIRefExpr{index: 164 /* sp */},
 								&labeledExpr{
 									label: "id",
-									expr:  &ruleIRefExpr{index: 98 /* identifier */},
+									expr:  &ruleIRefExpr{index: 135 /* identifier */},
 								},
-								&ruleIRefExpr{index: 123 /* sp */},
+								&ruleIRefExpr{index: 164 /* sp */},
 							},
 						},
 					},
 					&actionExpr{
-						run: (*parser).call_onstmtAssignType2_8,
+						run: (*parser).call_ondestructItem_9,
 						expr: &seqExpr{
 							exprs: []any{
-								&litMatcher{val: "=", want: "\"=\""},
-								&ruleIRefExpr{index: 123 /* sp */},
+								&labeledExpr{
+									label: "id",
+									expr:  &ruleIRefExpr{index: 135 /* identifier */},
+								},
+								&ruleIRefExpr{index: 164 /* sp */},
 							},
 						},
 					},
-					&actionExpr{
-						run: (*parser).call_onstmtAssignType2_12,
-						expr: &labeledExpr{
-							label:       "expr",
-							expr:        &ruleIRefExpr{index: 26 /* exprRoot */},
-							textCapture: true,
-						},
-					},
 				},
 			},
 		},
 		{
-			name:      "stmtAssignType3",
+			name:      "destructDictItem",
 			varExists: true,
-			expr: &seqExpr{
-				exprs: []any{
+			expr: &choiceExpr{
+				alternatives: []any{
 					&actionExpr{
-						run: (*parser).call_onstmtAssignType3_2,
+						run: (*parser).call_ondestructDictItem_2,
 						expr: &seqExpr{
 							exprs: []any{
-								&litMatcher{val: "&", want: "\"&\""},
+								&litMatcher{val: "...", want: "\"...\""},
+								&ruleIRefExpr{index: 164 /* sp */},
 								&labeledExpr{
 									label: "id",
-									expr:  &ruleIRefExpr{index: 98 /* identifier */},
+									expr:  &ruleIRefExpr{index: 135 /* identifier */},
+								},
+								&ruleIRefExpr{index: 164 /* sp */},
+								&andExpr{
+									expr: &litMatcher{val: "}", want: "\"}\""},
 								},
-								&ruleIRefExpr{index: 123 /* sp */},
 							},
 						},
 					},
 					&actionExpr{
-						run: (*parser).call_onstmtAssignType3_8,
+						run: (*parser).call_ondestructDictItem_11,
 						expr: &seqExpr{
 							exprs: []any{
-								&litMatcher{val: ".", want: "\".\""},
 								&labeledExpr{
-									label: "id2",
-									expr:  &ruleIRefExpr{index: 98 /* identifier */},
+									label: "key",
+									expr:  &ruleIRefExpr{index: 136 /* identifierWithoutColon */},
+								},
+								&ruleIRefExpr{index: 164 /* sp */},
+								&litMatcher{val: ":", want: "\":\""},
+								&ruleIRefExpr{index: 164 /* sp */},
+								&labeledExpr{
+									label: "id",
+									expr:  &ruleIRefExpr{index: 135 /* identifier */},
 								},
-								&ruleIRefExpr{index: 123 /* sp */},
+								&ruleIRefExpr{index: 164 /* sp */},
 							},
 						},
 					},
 					&actionExpr{
-						run: (*parser).call_onstmtAssignType3_14,
+						run: (*parser).call_ondestructDictItem_21,
 						expr: &seqExpr{
 							exprs: []any{
-								&ruleIRefExpr{index: 123 /* sp */},
-								&litMatcher{val: "=", want: "\"=\""},
-								&ruleIRefExpr{index: 123 /* sp */},
-								&ruleIRefExpr{index: 26 /* exprRoot */},
+								&labeledExpr{
+									label: "id",
+									expr:  &ruleIRefExpr{index: 135 /* identifier */},
+								},
+								&ruleIRefExpr{index: 164 /* sp */},
 							},
 						},
 					},
@@ -620,117 +1725,97 @@ var g = &grammar{
 			},
 		},
 		{
-			name:      "stmtAssignType4",
+			name:      "stmtAssignTuple",
 			varExists: true,
 			expr: &seqExpr{
 				exprs: []any{
 					&actionExpr{
-						run: (*parser).call_onstmtAssignType4_2,
-						expr: &seqExpr{
-							exprs: []any{
-								&litMatcher{val: "this", want: "\"this\""},
-								&ruleIRefExpr{index: 123 /* sp */},
-								&litMatcher{val: ".", want: "\".\""},
-								&ruleIRefExpr{index: 123 /* sp */},
-								&labeledExpr{
-									label: "id",
-									expr:  &ruleIRefExpr{index: 98 /* identifier */},
+						run: (*parser).call_onstmtAssignTuple_2,
+						expr: &andExpr{
+							expr: &seqExpr{
+								exprs: []any{
+									&ruleIRefExpr{index: 135 /* identifier */},
+									&ruleIRefExpr{index: 164 /* sp */},
+									&oneOrMoreExpr{
+										expr: &seqExpr{
+											exprs: []any{
+												&litMatcher{val: ",", want: "\",\""},
+												&ruleIRefExpr{index: 164 /* sp */},
+												&ruleIRefExpr{index: 135 /* identifier */},
+												&ruleIRefExpr{index: 164 /* sp */},
+											},
+										},
+									},
+									&litMatcher{val: "=", want: "\"=\""},
+									&notExpr{
+										expr: &litMatcher{val: "=", want: "\"=\""},
+									},
 								},
-								&ruleIRefExpr{index: 123 /* sp */},
-							},
-						},
-					},
-					&actionExpr{
-						run: (*parser).call_onstmtAssignType4_11,
-						expr: &seqExpr{
-							exprs: []any{
-								&litMatcher{val: "=", want: "\"=\""},
-								&ruleIRefExpr{index: 123 /* sp */},
-								&ruleIRefExpr{index: 26 /* exprRoot */},
 							},
 						},
 					},
-				},
-			},
-		},
-		{
-			name:      "stmtAssignType5",
-			varExists: true,
-			expr: &seqExpr{
-				exprs: []any{
 					&actionExpr{
-						run: (*parser).call_onstmtAssignType5_2,
+						run: (*parser).call_onstmtAssignTuple_16,
 						expr: &seqExpr{
 							exprs: []any{
 								&labeledExpr{
 									label: "id",
-									expr:  &ruleIRefExpr{index: 98 /* identifier */},
+									expr:  &ruleIRefExpr{index: 135 /* identifier */},
 								},
-								&ruleIRefExpr{index: 123 /* sp */},
+								&ruleIRefExpr{index: 164 /* sp */},
 							},
 						},
 					},
 					&actionExpr{
-						run: (*parser).call_onstmtAssignType5_7,
+						run: (*parser).call_onstmtAssignTuple_21,
 						expr: &seqExpr{
 							exprs: []any{
-								&litMatcher{val: ".", want: "\".\""},
-								&ruleIRefExpr{index: 123 /* sp */},
-								&labeledExpr{
-									label: "id2",
-									expr:  &ruleIRefExpr{index: 98 /* identifier */},
+								&oneOrMoreExpr{
+									expr: &actionExpr{
+										run: (*parser).call_onstmtAssignTuple_24,
+										expr: &seqExpr{
+											exprs: []any{
+												&litMatcher{val: ",", want: "\",\""},
+												&ruleIRefExpr{index: 164 /* sp */},
+												&labeledExpr{
+													label: "id2",
+													expr:  &ruleIRefExpr{index: 135 /* identifier */},
+												},
+												&ruleIRefExpr{index: 164 /* sp */},
+											},
+										},
+									},
 								},
-								&ruleIRefExpr{index: 123 /* sp */},
+								&litMatcher{val: "=", want: "\"=\""},
+								&ruleIRefExpr{index: 164 /* sp */},
 							},
 						},
 					},
 					&actionExpr{
-						run: (*parser).call_onstmtAssignType5_14,
+						run: (*parser).call_onstmtAssignTuple_33,
 						expr: &seqExpr{
 							exprs: []any{
-								&litMatcher{val: "=", want: "\"=\""},
-								&ruleIRefExpr{index: 123 /* sp */},
-								&ruleIRefExpr{index: 26 /* exprRoot */},
+								&ruleIRefExpr{index: 49 /* exprRoot */},
+								&ruleIRefExpr{index: 164 /* sp */},
+								&zeroOrMoreExpr{
+									expr: &actionExpr{
+										run: (*parser).call_onstmtAssignTuple_38,
+										expr: &seqExpr{
+											exprs: []any{
+												&litMatcher{val: ",", want: "\",\""},
+												&ruleIRefExpr{index: 164 /* sp */},
+												&ruleIRefExpr{index: 49 /* exprRoot */},
+												&ruleIRefExpr{index: 164 /* sp */},
+											},
+										},
+									},
+								},
 							},
 						},
 					},
 				},
 			},
 		},
-		{
-			name: "stmtAssignType6",
-			expr: &actionExpr{
-				run: (*parser).call_onstmtAssignType6_1,
-				expr: &seqExpr{
-					exprs: []any{
-						&ruleIRefExpr{index: 30 /* exprSlice */},
-						&litMatcher{val: "[", want: "\"[\""},
-						&ruleIRefExpr{index: 123 /* sp */},
-						&ruleIRefExpr{index: 26 /* exprRoot */},
-						&litMatcher{val: "]", want: "\"]\""},
-						&ruleIRefExpr{index: 123 /* sp */},
-						&litMatcher{val: "=", want: "\"=\""},
-						&ruleIRefExpr{index: 123 /* sp */},
-						&ruleIRefExpr{index: 26 /* exprRoot */},
-					},
-				},
-			},
-		},
-		{
-			name: "stmtAssignType7",
-			expr: &actionExpr{
-				run: (*parser).call_onstmtAssignType7_1,
-				expr: &seqExpr{
-					exprs: []any{
-						&ruleIRefExpr{index: 30 /* exprSlice */},
-						&ruleIRefExpr{index: 28 /* _sliceSuffix */},
-						&litMatcher{val: "=", want: "\"=\""},
-						&ruleIRefExpr{index: 123 /* sp */},
-						&ruleIRefExpr{index: 26 /* exprRoot */},
-					},
-				},
-			},
-		},
 		{
 			name: "stmtAssign",
 			expr: &choiceExpr{
@@ -738,57 +1823,97 @@ var g = &grammar{
 					&seqExpr{
 						exprs: []any{
 							&andExpr{
-								expr: &ruleIRefExpr{index: 17 /* stmtAssignType1 */},
+								expr: &ruleIRefExpr{index: 32 /* stmtAssignType1 */},
+							},
+							&ruleIRefExpr{index: 32 /* stmtAssignType1 */},
+						},
+					},
+					&seqExpr{
+						exprs: []any{
+							&andExpr{
+								expr: &ruleIRefExpr{index: 33 /* stmtAssignType2 */},
+							},
+							&ruleIRefExpr{index: 33 /* stmtAssignType2 */},
+						},
+					},
+					&seqExpr{
+						exprs: []any{
+							&andExpr{
+								expr: &ruleIRefExpr{index: 34 /* stmtAssignType3 */},
 							},
-							&ruleIRefExpr{index: 17 /* stmtAssignType1 */},
+							&ruleIRefExpr{index: 34 /* stmtAssignType3 */},
 						},
 					},
 					&seqExpr{
 						exprs: []any{
 							&andExpr{
-								expr: &ruleIRefExpr{index: 18 /* stmtAssignType2 */},
+								expr: &ruleIRefExpr{index: 35 /* stmtAssignType4 */},
 							},
-							&ruleIRefExpr{index: 18 /* stmtAssignType2 */},
+							&ruleIRefExpr{index: 35 /* stmtAssignType4 */},
 						},
 					},
 					&seqExpr{
 						exprs: []any{
 							&andExpr{
-								expr: &ruleIRefExpr{index: 19 /* stmtAssignType3 */},
+								expr: &ruleIRefExpr{index: 36 /* stmtAssignType5 */},
 							},
-							&ruleIRefExpr{index: 19 /* stmtAssignType3 */},
+							&ruleIRefExpr{index: 36 /* stmtAssignType5 */},
 						},
 					},
 					&seqExpr{
 						exprs: []any{
 							&andExpr{
-								expr: &ruleIRefExpr{index: 20 /* stmtAssignType4 */},
+								expr: &ruleIRefExpr{index: 37 /* stmtAssignType6 */},
 							},
-							&ruleIRefExpr{index: 20 /* stmtAssignType4 */},
+							&ruleIRefExpr{index: 37 /* stmtAssignType6 */},
 						},
 					},
 					&seqExpr{
 						exprs: []any{
 							&andExpr{
-								expr: &ruleIRefExpr{index: 21 /* stmtAssignType5 */},
+								expr: &ruleIRefExpr{index: 38 /* stmtAssignType7 */},
 							},
-							&ruleIRefExpr{index: 21 /* stmtAssignType5 */},
+							&ruleIRefExpr{index: 38 /* stmtAssignType7 */},
 						},
 					},
 					&seqExpr{
 						exprs: []any{
 							&andExpr{
-								expr: &ruleIRefExpr{index: 22 /* stmtAssignType6 */},
+								expr: &ruleIRefExpr{index: 39 /* stmtAssignType8 */},
 							},
-							&ruleIRefExpr{index: 22 /* stmtAssignType6 */},
+							&ruleIRefExpr{index: 39 /* stmtAssignType8 */},
 						},
 					},
 					&seqExpr{
 						exprs: []any{
 							&andExpr{
-								expr: &ruleIRefExpr{index: 23 /* stmtAssignType7 */},
+								expr: &ruleIRefExpr{index: 40 /* stmtAssignType9 */},
 							},
-							&ruleIRefExpr{index: 23 /* stmtAssignType7 */},
+							&ruleIRefExpr{index: 40 /* stmtAssignType9 */},
+						},
+					},
+					&seqExpr{
+						exprs: []any{
+							&andExpr{
+								expr: &ruleIRefExpr{index: 41 /* stmtAssignType10 */},
+							},
+							&ruleIRefExpr{index: 41 /* stmtAssignType10 */},
+						},
+					},
+					&seqExpr{
+						exprs: []any{
+							&andExpr{
+								expr: &ruleIRefExpr{index: 42 /* stmtAssignType11 */},
+							},
+							&ruleIRefExpr{index: 42 /* stmtAssignType11 */},
+						},
+					},
+					&seqExpr{
+						exprs: []any{
+							&andExpr{
+								expr: &ruleIRefExpr{index: 43 /* stmtAssignType12 */},
+							},
+							&ruleIRefExpr{index: 43 /* stmtAssignType12 */},
 						},
 					},
 				},
@@ -803,8 +1928,8 @@ var g = &grammar{
 							&andExpr{
 								expr: &seqExpr{
 									exprs: []any{
-										&ruleIRefExpr{index: 103 /* subX */},
-										&ruleIRefExpr{index: 123 /* sp */},
+										&ruleIRefExpr{index: 140 /* subX */},
+										&ruleIRefExpr{index: 164 /* sp */},
 										&charClassMatcher{
 											val:   "[-+*/%^dDcCaA&|?<>=]",
 											chars: []rune{'-', '+', '*', '/', '%', '^', 'd', 'D', 'c', 'C', 'a', 'A', '&', '|', '?', '<', '>', '='},
@@ -814,8 +1939,8 @@ var g = &grammar{
 							},
 							&choiceExpr{
 								alternatives: []any{
-									&ruleIRefExpr{index: 24 /* stmtAssign */},
-									&ruleIRefExpr{index: 30 /* exprSlice */},
+									&ruleIRefExpr{index: 47 /* stmtAssign */},
+									&ruleIRefExpr{index: 53 /* exprSlice */},
 								},
 							},
 						},
@@ -823,9 +1948,9 @@ var g = &grammar{
 					&seqExpr{
 						exprs: []any{
 							&andExpr{
-								expr: &ruleIRefExpr{index: 103 /* subX */},
+								expr: &ruleIRefExpr{index: 140 /* subX */},
 							},
-							&ruleIRefExpr{index: 103 /* subX */},
+							&ruleIRefExpr{index: 140 /* subX */},
 						},
 					},
 				},
@@ -835,9 +1960,9 @@ var g = &grammar{
 			name: "exprRoot",
 			expr: &choiceExpr{
 				alternatives: []any{
-					&ruleIRefExpr{index: 25 /* nestedBoost */},
-					&ruleIRefExpr{index: 24 /* stmtAssign */},
-					&ruleIRefExpr{index: 30 /* exprSlice */},
+					&ruleIRefExpr{index: 48 /* nestedBoost */},
+					&ruleIRefExpr{index: 47 /* stmtAssign */},
+					&ruleIRefExpr{index: 53 /* exprSlice */},
 				},
 			},
 		},
@@ -848,13 +1973,13 @@ var g = &grammar{
 					&seqExpr{
 						exprs: []any{
 							&litMatcher{val: ":", want: "\":\""},
-							&ruleIRefExpr{index: 123 /* sp */},
+							&ruleIRefExpr{index: 164 /* sp */},
 							&choiceExpr{
 								alternatives: []any{
-									&ruleIRefExpr{index: 26 /* exprRoot */},
+									&ruleIRefExpr{index: 49 /* exprRoot */},
 									&actionExpr{
 										run:  (*parser).call_on_step_7,
-										expr: &ruleIRefExpr{index: 123 /* sp */},
+										expr: &ruleIRefExpr{index: 164 /* sp */},
 									},
 								},
 							},
@@ -862,7 +1987,7 @@ var g = &grammar{
 					},
 					&actionExpr{
 						run:  (*parser).call_on_step_9,
-						expr: &ruleIRefExpr{index: 123 /* sp */},
+						expr: &ruleIRefExpr{index: 164 /* sp */},
 					},
 				},
 			},
@@ -872,31 +1997,31 @@ var g = &grammar{
 			expr: &seqExpr{
 				exprs: []any{
 					&litMatcher{val: "[", want: "\"[\""},
-					&ruleIRefExpr{index: 123 /* sp */},
+					&ruleIRefExpr{index: 164 /* sp */},
 					&choiceExpr{
 						alternatives: []any{
-							&ruleIRefExpr{index: 26 /* exprRoot */},
+							&ruleIRefExpr{index: 49 /* exprRoot */},
 							&actionExpr{
 								run:  (*parser).call_on_sliceSuffix_6,
-								expr: &ruleIRefExpr{index: 123 /* sp */},
+								expr: &ruleIRefExpr{index: 164 /* sp */},
 							},
 						},
 					},
 					&litMatcher{val: ":", want: "\":\""},
-					&ruleIRefExpr{index: 123 /* sp */},
+					&ruleIRefExpr{index: 164 /* sp */},
 					&choiceExpr{
 						alternatives: []any{
-							&ruleIRefExpr{index: 26 /* exprRoot */},
+							&ruleIRefExpr{index: 49 /* exprRoot */},
 							&actionExpr{
 								run:  (*parser).call_on_sliceSuffix_12,
-								expr: &ruleIRefExpr{index: 123 /* sp */},
+								expr: &ruleIRefExpr{index: 164 /* sp */},
 							},
 						},
 					},
-					&ruleIRefExpr{index: 27 /* _step */},
-					&ruleIRefExpr{index: 123 /* sp */},
+					&ruleIRefExpr{index: 50 /* _step */},
+					&ruleIRefExpr{index: 164 /* sp */},
 					&litMatcher{val: "]", want: "\"]\""},
-					&ruleIRefExpr{index: 123 /* sp */},
+					&ruleIRefExpr{index: 164 /* sp */},
 				},
 			},
 		},
@@ -906,8 +2031,8 @@ var g = &grammar{
 				run: (*parser).call_onexprSliceType1_1,
 				expr: &seqExpr{
 					exprs: []any{
-						&ruleIRefExpr{index: 34 /* exprTernary */},
-						&ruleIRefExpr{index: 28 /* _sliceSuffix */},
+						&ruleIRefExpr{index: 57 /* exprTernary */},
+						&ruleIRefExpr{index: 51 /* _sliceSuffix */},
 						&notExpr{
 							expr: &litMatcher{val: "=", want: "\"=\""},
 						},
@@ -922,12 +2047,12 @@ var g = &grammar{
 					&seqExpr{
 						exprs: []any{
 							&andExpr{
-								expr: &ruleIRefExpr{index: 29 /* exprSliceType1 */},
+								expr: &ruleIRefExpr{index: 52 /* exprSliceType1 */},
 							},
-							&ruleIRefExpr{index: 29 /* exprSliceType1 */},
+							&ruleIRefExpr{index: 52 /* exprSliceType1 */},
 						},
 					},
-					&ruleIRefExpr{index: 34 /* exprTernary */},
+					&ruleIRefExpr{index: 57 /* exprTernary */},
 				},
 			},
 		},
@@ -939,10 +2064,10 @@ var g = &grammar{
 						run: (*parser).call_onexprValueIfExists_2,
 						expr: &seqExpr{
 							exprs: []any{
-								&ruleIRefExpr{index: 35 /* exprLogicOr */},
-								&ruleIRefExpr{index: 123 /* sp */},
+								&ruleIRefExpr{index: 58 /* exprLogicOr */},
+								&ruleIRefExpr{index: 164 /* sp */},
 								&litMatcher{val: "?", want: "\"?\""},
-								&ruleIRefExpr{index: 123 /* sp */},
+								&ruleIRefExpr{index: 164 /* sp */},
 							},
 						},
 					},
@@ -950,8 +2075,8 @@ var g = &grammar{
 						run: (*parser).call_onexprValueIfExists_8,
 						expr: &seqExpr{
 							exprs: []any{
-								&ruleIRefExpr{index: 35 /* exprLogicOr */},
-								&ruleIRefExpr{index: 123 /* sp */},
+								&ruleIRefExpr{index: 58 /* exprLogicOr */},
+								&ruleIRefExpr{index: 164 /* sp */},
 							},
 						},
 					},
@@ -966,10 +2091,10 @@ var g = &grammar{
 						run: (*parser).call_onexprTernaryType1_2,
 						expr: &seqExpr{
 							exprs: []any{
-								&ruleIRefExpr{index: 35 /* exprLogicOr */},
-								&ruleIRefExpr{index: 123 /* sp */},
+								&ruleIRefExpr{index: 58 /* exprLogicOr */},
+								&ruleIRefExpr{index: 164 /* sp */},
 								&litMatcher{val: "?", want: "\"?\""},
-								&ruleIRefExpr{index: 123 /* sp */},
+								&ruleIRefExpr{index: 164 /* sp */},
 							},
 						},
 					},
@@ -977,10 +2102,10 @@ var g = &grammar{
 						run: (*parser).call_onexprTernaryType1_8,
 						expr: &seqExpr{
 							exprs: []any{
-								&ruleIRefExpr{index: 35 /* exprLogicOr */},
-								&ruleIRefExpr{index: 123 /* sp */},
+								&ruleIRefExpr{index: 58 /* exprLogicOr */},
+								&ruleIRefExpr{index: 164 /* sp */},
 								&litMatcher{val: ":", want: "\":\""},
-								&ruleIRefExpr{index: 123 /* sp */},
+								&ruleIRefExpr{index: 164 /* sp */},
 							},
 						},
 					},
@@ -988,8 +2113,8 @@ var g = &grammar{
 						run: (*parser).call_onexprTernaryType1_14,
 						expr: &seqExpr{
 							exprs: []any{
-								&ruleIRefExpr{index: 35 /* exprLogicOr */},
-								&ruleIRefExpr{index: 123 /* sp */},
+								&ruleIRefExpr{index: 58 /* exprLogicOr */},
+								&ruleIRefExpr{index: 164 /* sp */},
 							},
 						},
 					},
@@ -1002,7 +2127,7 @@ var g = &grammar{
 				exprs: []any{
 					&actionExpr{
 						run:  (*parser).call_onexprTernaryType2_2,
-						expr: &ruleIRefExpr{index: 31 /* exprValueIfExists */},
+						expr: &ruleIRefExpr{index: 54 /* exprValueIfExists */},
 					},
 					&actionExpr{
 						run: (*parser).call_onexprTernaryType2_4,
@@ -1012,8 +2137,8 @@ var g = &grammar{
 								expr: &seqExpr{
 									exprs: []any{
 										&litMatcher{val: ",", want: "\",\""},
-										&ruleIRefExpr{index: 123 /* sp */},
-										&ruleIRefExpr{index: 31 /* exprValueIfExists */},
+										&ruleIRefExpr{index: 164 /* sp */},
+										&ruleIRefExpr{index: 54 /* exprValueIfExists */},
 									},
 								},
 							},
@@ -1029,20 +2154,20 @@ var g = &grammar{
 					&seqExpr{
 						exprs: []any{
 							&andExpr{
-								expr: &ruleIRefExpr{index: 32 /* exprTernaryType1 */},
+								expr: &ruleIRefExpr{index: 55 /* exprTernaryType1 */},
 							},
-							&ruleIRefExpr{index: 32 /* exprTernaryType1 */},
+							&ruleIRefExpr{index: 55 /* exprTernaryType1 */},
 						},
 					},
 					&seqExpr{
 						exprs: []any{
 							&andExpr{
-								expr: &ruleIRefExpr{index: 33 /* exprTernaryType2 */},
+								expr: &ruleIRefExpr{index: 56 /* exprTernaryType2 */},
 							},
-							&ruleIRefExpr{index: 33 /* exprTernaryType2 */},
+							&ruleIRefExpr{index: 56 /* exprTernaryType2 */},
 						},
 					},
-					&ruleIRefExpr{index: 35 /* exprLogicOr */},
+					&ruleIRefExpr{index: 58 /* exprLogicOr */},
 				},
 			},
 		},
@@ -1050,7 +2175,7 @@ var g = &grammar{
 			name: "exprLogicOr",
 			expr: &seqExpr{
 				exprs: []any{
-					&ruleIRefExpr{index: 36 /* exprLogicAnd */},
+					&ruleIRefExpr{index: 59 /* exprLogicAnd */},
 					&zeroOrMoreExpr{
 						expr: &seqExpr{
 							exprs: []any{
@@ -1058,14 +2183,14 @@ var g = &grammar{
 									run: (*parser).call_onexprLogicOr_5,
 									expr: &seqExpr{
 										exprs: []any{
-											&ruleIRefExpr{index: 123 /* sp */},
-											&ruleIRefExpr{index: 115 /* logicOr */},
+											&ruleIRefExpr{index: 164 /* sp */},
+											&ruleIRefExpr{index: 152 /* logicOr */},
 										},
 									},
 								},
 								&actionExpr{
 									run:  (*parser).call_onexprLogicOr_9,
-									expr: &ruleIRefExpr{index: 36 /* exprLogicAnd */},
+									expr: &ruleIRefExpr{index: 59 /* exprLogicAnd */},
 								},
 								&codeExpr{
 									run: (*parser).call_onexprLogicOr_11,
@@ -1080,15 +2205,15 @@ var g = &grammar{
 			name: "exprLogicAnd",
 			expr: &seqExpr{
 				exprs: []any{
-					&ruleIRefExpr{index: 37 /* exprBitwiseOr */},
+					&ruleIRefExpr{index: 60 /* exprBitwiseOr */},
 					&zeroOrMoreExpr{
 						expr: &actionExpr{
 							run: (*parser).call_onexprLogicAnd_4,
 							expr: &seqExpr{
 								exprs: []any{
-									&ruleIRefExpr{index: 123 /* sp */},
-									&ruleIRefExpr{index: 116 /* logicAnd */},
-									&ruleIRefExpr{index: 37 /* exprBitwiseOr */},
+									&ruleIRefExpr{index: 164 /* sp */},
+									&ruleIRefExpr{index: 153 /* logicAnd */},
+									&ruleIRefExpr{index: 60 /* exprBitwiseOr */},
 								},
 							},
 						},
@@ -1103,20 +2228,26 @@ var g = &grammar{
 					&seqExpr{
 						exprs: []any{
 							&andCodeExpr{run: (*parser).call_onexprBitwiseOr_3},
-							&ruleIRefExpr{index: 39 /* exprCompare */},
+							&ruleIRefExpr{index: 62 /* exprCompare */},
 						},
 					},
 					&seqExpr{
 						exprs: []any{
-							&ruleIRefExpr{index: 38 /* exprBitwiseAnd */},
-							&zeroOrMoreExpr{
-								expr: &actionExpr{
-									run: (*parser).call_onexprBitwiseOr_8,
-									expr: &seqExpr{
-										exprs: []any{
-											&ruleIRefExpr{index: 123 /* sp */},
-											&ruleIRefExpr{index: 113 /* bitwiseOr */},
-											&ruleIRefExpr{index: 38 /* exprBitwiseAnd */},
+							&actionExpr{
+								run:  (*parser).call_onexprBitwiseOr_6,
+								expr: &ruleIRefExpr{index: 61 /* exprBitwiseAnd */},
+							},
+							&actionExpr{
+								run: (*parser).call_onexprBitwiseOr_8,
+								expr: &zeroOrMoreExpr{
+									expr: &actionExpr{
+										run: (*parser).call_onexprBitwiseOr_10,
+										expr: &seqExpr{
+											exprs: []any{
+												&ruleIRefExpr{index: 164 /* sp */},
+												&ruleIRefExpr{index: 150 /* bitwiseOr */},
+												&ruleIRefExpr{index: 61 /* exprBitwiseAnd */},
+											},
 										},
 									},
 								},
@@ -1130,15 +2261,21 @@ var g = &grammar{
 			name: "exprBitwiseAnd",
 			expr: &seqExpr{
 				exprs: []any{
-					&ruleIRefExpr{index: 39 /* exprCompare */},
-					&zeroOrMoreExpr{
-						expr: &actionExpr{
-							run: (*parser).call_onexprBitwiseAnd_4,
-							expr: &seqExpr{
-								exprs: []any{
-									&ruleIRefExpr{index: 123 /* sp */},
-									&ruleIRefExpr{index: 114 /* bitwiseAnd */},
-									&ruleIRefExpr{index: 39 /* exprCompare */},
+					&actionExpr{
+						run:  (*parser).call_onexprBitwiseAnd_2,
+						expr: &ruleIRefExpr{index: 62 /* exprCompare */},
+					},
+					&actionExpr{
+						run: (*parser).call_onexprBitwiseAnd_4,
+						expr: &zeroOrMoreExpr{
+							expr: &actionExpr{
+								run: (*parser).call_onexprBitwiseAnd_6,
+								expr: &seqExpr{
+									exprs: []any{
+										&ruleIRefExpr{index: 164 /* sp */},
+										&ruleIRefExpr{index: 151 /* bitwiseAnd */},
+										&ruleIRefExpr{index: 62 /* exprCompare */},
+									},
 								},
 							},
 						},
@@ -1150,64 +2287,88 @@ var g = &grammar{
 			name: "exprCompare",
 			expr: &seqExpr{
 				exprs: []any{
-					&ruleIRefExpr{index: 40 /* exprAdditive */},
-					&zeroOrMoreExpr{
-						expr: &seqExpr{
-							exprs: []any{
-								&ruleIRefExpr{index: 123 /* sp */},
-								&choiceExpr{
-									alternatives: []any{
-										&actionExpr{
-											run: (*parser).call_onexprCompare_7,
-											expr: &seqExpr{
-												exprs: []any{
-													&ruleIRefExpr{index: 117 /* lt */},
-													&ruleIRefExpr{index: 40 /* exprAdditive */},
+					&actionExpr{
+						run:  (*parser).call_onexprCompare_2,
+						expr: &ruleIRefExpr{index: 63 /* exprAdditive */},
+					},
+					&actionExpr{
+						run: (*parser).call_onexprCompare_4,
+						expr: &zeroOrMoreExpr{
+							expr: &seqExpr{
+								exprs: []any{
+									&ruleIRefExpr{index: 164 /* sp */},
+									&choiceExpr{
+										alternatives: []any{
+											&actionExpr{
+												run: (*parser).call_onexprCompare_9,
+												expr: &seqExpr{
+													exprs: []any{
+														&ruleIRefExpr{index: 155 /* lt */},
+														&ruleIRefExpr{index: 63 /* exprAdditive */},
+													},
 												},
 											},
-										},
-										&actionExpr{
-											run: (*parser).call_onexprCompare_11,
-											expr: &seqExpr{
-												exprs: []any{
-													&ruleIRefExpr{index: 119 /* le */},
-													&ruleIRefExpr{index: 40 /* exprAdditive */},
+											&actionExpr{
+												run: (*parser).call_onexprCompare_13,
+												expr: &seqExpr{
+													exprs: []any{
+														&ruleIRefExpr{index: 157 /* le */},
+														&ruleIRefExpr{index: 63 /* exprAdditive */},
+													},
 												},
 											},
-										},
-										&actionExpr{
-											run: (*parser).call_onexprCompare_15,
-											expr: &seqExpr{
-												exprs: []any{
-													&ruleIRefExpr{index: 121 /* eq */},
-													&ruleIRefExpr{index: 40 /* exprAdditive */},
+											&actionExpr{
+												run: (*parser).call_onexprCompare_17,
+												expr: &seqExpr{
+													exprs: []any{
+														&ruleIRefExpr{index: 159 /* eq */},
+														&ruleIRefExpr{index: 63 /* exprAdditive */},
+													},
 												},
 											},
-										},
-										&actionExpr{
-											run: (*parser).call_onexprCompare_19,
-											expr: &seqExpr{
-												exprs: []any{
-													&ruleIRefExpr{index: 122 /* ne */},
-													&ruleIRefExpr{index: 40 /* exprAdditive */},
+											&actionExpr{
+												run: (*parser).call_onexprCompare_21,
+												expr: &seqExpr{
+													exprs: []any{
+														&ruleIRefExpr{index: 160 /* ne */},
+														&ruleIRefExpr{index: 63 /* exprAdditive */},
+													},
 												},
 											},
-										},
-										&actionExpr{
-											run: (*parser).call_onexprCompare_23,
-											expr: &seqExpr{
-												exprs: []any{
-													&ruleIRefExpr{index: 120 /* ge */},
-													&ruleIRefExpr{index: 40 /* exprAdditive */},
+											&actionExpr{
+												run: (*parser).call_onexprCompare_25,
+												expr: &seqExpr{
+													exprs: []any{
+														&ruleIRefExpr{index: 158 /* ge */},
+														&ruleIRefExpr{index: 63 /* exprAdditive */},
+													},
 												},
 											},
-										},
-										&actionExpr{
-											run: (*parser).call_onexprCompare_27,
-											expr: &seqExpr{
-												exprs: []any{
-													&ruleIRefExpr{index: 118 /* gt */},
-													&ruleIRefExpr{index: 40 /* exprAdditive */},
+											&actionExpr{
+												run: (*parser).call_onexprCompare_29,
+												expr: &seqExpr{
+													exprs: []any{
+														&ruleIRefExpr{index: 156 /* gt */},
+														&ruleIRefExpr{index: 63 /* exprAdditive */},
+													},
+												},
+											},
+											&actionExpr{
+												run: (*parser).call_onexprCompare_33,
+												expr: &seqExpr{
+													exprs: []any{
+														&ruleIRefExpr{index: 162 /* opNotIn */},
+														&ruleIRefExpr{index: 63 /* exprAdditive */},
+													},
+												},
+											},
+											&actionExpr{
+												run: (*parser).call_onexprCompare_37,
+												expr: &seqExpr{
+													exprs: []any{
+														&ruleIRefExpr{index: 161 /* opIn */},
+														&ruleIRefExpr{index: 63 /* exprAdditive */},
+													},
 												},
 											},
 										},
@@ -1223,28 +2384,34 @@ var g = &grammar{
 			name: "exprAdditive",
 			expr: &seqExpr{
 				exprs: []any{
-					&ruleIRefExpr{index: 41 /* exprMultiplicative */},
-					&zeroOrMoreExpr{
-						expr: &seqExpr{
-							exprs: []any{
-								&ruleIRefExpr{index: 123 /* sp */},
-								&choiceExpr{
-									alternatives: []any{
-										&actionExpr{
-											run: (*parser).call_onexprAdditive_7,
-											expr: &seqExpr{
-												exprs: []any{
-													&ruleIRefExpr{index: 106 /* add */},
-													&ruleIRefExpr{index: 41 /* exprMultiplicative */},
+					&actionExpr{
+						run:  (*parser).call_onexprAdditive_2,
+						expr: &ruleIRefExpr{index: 64 /* exprMultiplicative */},
+					},
+					&actionExpr{
+						run: (*parser).call_onexprAdditive_4,
+						expr: &zeroOrMoreExpr{
+							expr: &seqExpr{
+								exprs: []any{
+									&ruleIRefExpr{index: 164 /* sp */},
+									&choiceExpr{
+										alternatives: []any{
+											&actionExpr{
+												run: (*parser).call_onexprAdditive_9,
+												expr: &seqExpr{
+													exprs: []any{
+														&ruleIRefExpr{index: 143 /* add */},
+														&ruleIRefExpr{index: 64 /* exprMultiplicative */},
+													},
 												},
 											},
-										},
-										&actionExpr{
-											run: (*parser).call_onexprAdditive_11,
-											expr: &seqExpr{
-												exprs: []any{
-													&ruleIRefExpr{index: 107 /* minus */},
-													&ruleIRefExpr{index: 41 /* exprMultiplicative */},
+											&actionExpr{
+												run: (*parser).call_onexprAdditive_13,
+												expr: &seqExpr{
+													exprs: []any{
+														&ruleIRefExpr{index: 144 /* minus */},
+														&ruleIRefExpr{index: 64 /* exprMultiplicative */},
+													},
 												},
 											},
 										},
@@ -1260,37 +2427,43 @@ var g = &grammar{
 			name: "exprMultiplicative",
 			expr: &seqExpr{
 				exprs: []any{
-					&ruleIRefExpr{index: 42 /* exprNullCoalescing */},
-					&zeroOrMoreExpr{
-						expr: &seqExpr{
-							exprs: []any{
-								&ruleIRefExpr{index: 123 /* sp */},
-								&choiceExpr{
-									alternatives: []any{
-										&actionExpr{
-											run: (*parser).call_onexprMultiplicative_7,
-											expr: &seqExpr{
-												exprs: []any{
-													&ruleIRefExpr{index: 108 /* multiply */},
-													&ruleIRefExpr{index: 43 /* exprExp */},
+					&actionExpr{
+						run:  (*parser).call_onexprMultiplicative_2,
+						expr: &ruleIRefExpr{index: 65 /* exprNullCoalescing */},
+					},
+					&actionExpr{
+						run: (*parser).call_onexprMultiplicative_4,
+						expr: &zeroOrMoreExpr{
+							expr: &seqExpr{
+								exprs: []any{
+									&ruleIRefExpr{index: 164 /* sp */},
+									&choiceExpr{
+										alternatives: []any{
+											&actionExpr{
+												run: (*parser).call_onexprMultiplicative_9,
+												expr: &seqExpr{
+													exprs: []any{
+														&ruleIRefExpr{index: 145 /* multiply */},
+														&ruleIRefExpr{index: 66 /* exprExp */},
+													},
 												},
 											},
-										},
-										&actionExpr{
-											run: (*parser).call_onexprMultiplicative_11,
-											expr: &seqExpr{
-												exprs: []any{
-													&ruleIRefExpr{index: 109 /* divide */},
-													&ruleIRefExpr{index: 43 /* exprExp */},
+											&actionExpr{
+												run: (*parser).call_onexprMultiplicative_13,
+												expr: &seqExpr{
+													exprs: []any{
+														&ruleIRefExpr{index: 146 /* divide */},
+														&ruleIRefExpr{index: 66 /* exprExp */},
+													},
 												},
 											},
-										},
-										&actionExpr{
-											run: (*parser).call_onexprMultiplicative_15,
-											expr: &seqExpr{
-												exprs: []any{
-													&ruleIRefExpr{index: 110 /* modulus */},
-													&ruleIRefExpr{index: 43 /* exprExp */},
+											&actionExpr{
+												run: (*parser).call_onexprMultiplicative_17,
+												expr: &seqExpr{
+													exprs: []any{
+														&ruleIRefExpr{index: 147 /* modulus */},
+														&ruleIRefExpr{index: 66 /* exprExp */},
+													},
 												},
 											},
 										},
@@ -1306,15 +2479,15 @@ var g = &grammar{
 			name: "exprNullCoalescing",
 			expr: &seqExpr{
 				exprs: []any{
-					&ruleIRefExpr{index: 43 /* exprExp */},
+					&ruleIRefExpr{index: 66 /* exprExp */},
 					&zeroOrMoreExpr{
 						expr: &actionExpr{
 							run: (*parser).call_onexprNullCoalescing_4,
 							expr: &seqExpr{
 								exprs: []any{
-									&ruleIRefExpr{index: 123 /* sp */},
-									&ruleIRefExpr{index: 112 /* nullCoalescing */},
-									&ruleIRefExpr{index: 43 /* exprExp */},
+									&ruleIRefExpr{index: 164 /* sp */},
+									&ruleIRefExpr{index: 149 /* nullCoalescing */},
+									&ruleIRefExpr{index: 66 /* exprExp */},
 								},
 							},
 						},
@@ -1326,15 +2499,26 @@ var g = &grammar{
 			name: "exprExp",
 			expr: &seqExpr{
 				exprs: []any{
-					&ruleIRefExpr{index: 44 /* exprUnaryNeg */},
-					&zeroOrMoreExpr{
-						expr: &actionExpr{
-							run: (*parser).call_onexprExp_4,
-							expr: &seqExpr{
-								exprs: []any{
-									&ruleIRefExpr{index: 123 /* sp */},
-									&ruleIRefExpr{index: 111 /* exponentiation */},
-									&ruleIRefExpr{index: 44 /* exprUnaryNeg */},
+					&actionExpr{
+						run: (*parser).call_onexprExp_2,
+						expr: &seqExpr{
+							exprs: []any{
+								&andCodeExpr{run: (*parser).call_onexprExp_4},
+								&ruleIRefExpr{index: 67 /* exprUnaryNeg */},
+							},
+						},
+					},
+					&actionExpr{
+						run: (*parser).call_onexprExp_6,
+						expr: &zeroOrMoreExpr{
+							expr: &actionExpr{
+								run: (*parser).call_onexprExp_8,
+								expr: &seqExpr{
+									exprs: []any{
+										&ruleIRefExpr{index: 164 /* sp */},
+										&ruleIRefExpr{index: 148 /* exponentiation */},
+										&ruleIRefExpr{index: 67 /* exprUnaryNeg */},
+									},
 								},
 							},
 						},
@@ -1350,12 +2534,21 @@ var g = &grammar{
 						run: (*parser).call_onexprUnaryNeg_2,
 						expr: &seqExpr{
 							exprs: []any{
-								&ruleIRefExpr{index: 107 /* minus */},
-								&ruleIRefExpr{index: 69 /* exprDice */},
+								&ruleIRefExpr{index: 144 /* minus */},
+								&ruleIRefExpr{index: 92 /* exprDice */},
+							},
+						},
+					},
+					&actionExpr{
+						run: (*parser).call_onexprUnaryNeg_6,
+						expr: &seqExpr{
+							exprs: []any{
+								&ruleIRefExpr{index: 154 /* logicNot */},
+								&ruleIRefExpr{index: 67 /* exprUnaryNeg */},
 							},
 						},
 					},
-					&ruleIRefExpr{index: 45 /* exprUnaryPos */},
+					&ruleIRefExpr{index: 68 /* exprUnaryPos */},
 				},
 			},
 		},
@@ -1367,12 +2560,12 @@ var g = &grammar{
 						run: (*parser).call_onexprUnaryPos_2,
 						expr: &seqExpr{
 							exprs: []any{
-								&ruleIRefExpr{index: 106 /* add */},
-								&ruleIRefExpr{index: 69 /* exprDice */},
+								&ruleIRefExpr{index: 143 /* add */},
+								&ruleIRefExpr{index: 92 /* exprDice */},
 							},
 						},
 					},
-					&ruleIRefExpr{index: 69 /* exprDice */},
+					&ruleIRefExpr{index: 92 /* exprDice */},
 				},
 			},
 		},
@@ -1380,8 +2573,8 @@ var g = &grammar{
 			name: "nos",
 			expr: &choiceExpr{
 				alternatives: []any{
-					&ruleIRefExpr{index: 82 /* number */},
-					&ruleIRefExpr{index: 102 /* sub */},
+					&ruleIRefExpr{index: 108 /* number */},
+					&ruleIRefExpr{index: 139 /* sub */},
 				},
 			},
 		},
@@ -1414,7 +2607,7 @@ var g = &grammar{
 										},
 									},
 								},
-								&ruleIRefExpr{index: 46 /* nos */},
+								&ruleIRefExpr{index: 69 /* nos */},
 							},
 						},
 					},
@@ -1443,7 +2636,7 @@ var g = &grammar{
 										},
 									},
 								},
-								&ruleIRefExpr{index: 46 /* nos */},
+								&ruleIRefExpr{index: 69 /* nos */},
 							},
 						},
 					},
@@ -1464,7 +2657,7 @@ var g = &grammar{
 						expr: &seqExpr{
 							exprs: []any{
 								&litMatcher{val: "dh", want: "\"dh\""},
-								&ruleIRefExpr{index: 46 /* nos */},
+								&ruleIRefExpr{index: 69 /* nos */},
 							},
 						},
 					},
@@ -1477,7 +2670,7 @@ var g = &grammar{
 						expr: &seqExpr{
 							exprs: []any{
 								&litMatcher{val: "dl", want: "\"dl\""},
-								&ruleIRefExpr{index: 46 /* nos */},
+								&ruleIRefExpr{index: 69 /* nos */},
 							},
 						},
 					},
@@ -1497,7 +2690,7 @@ var g = &grammar{
 						expr: &seqExpr{
 							exprs: []any{
 								&litMatcher{val: "min", want: "\"min\""},
-								&ruleIRefExpr{index: 46 /* nos */},
+								&ruleIRefExpr{index: 69 /* nos */},
 							},
 						},
 					},
@@ -1506,7 +2699,7 @@ var g = &grammar{
 						expr: &seqExpr{
 							exprs: []any{
 								&litMatcher{val: "max", want: "\"max\""},
-								&ruleIRefExpr{index: 46 /* nos */},
+								&ruleIRefExpr{index: 69 /* nos */},
 							},
 						},
 					},
@@ -1542,12 +2735,12 @@ var g = &grammar{
 			name: "_diceType1",
 			expr: &seqExpr{
 				exprs: []any{
-					&ruleIRefExpr{index: 46 /* nos */},
+					&ruleIRefExpr{index: 69 /* nos */},
 					&charClassMatcher{
 						val:   "[dD]",
 						chars: []rune{'d', 'D'},
 					},
-					&ruleIRefExpr{index: 46 /* nos */},
+					&ruleIRefExpr{index: 69 /* nos */},
 				},
 			},
 		},
@@ -1559,7 +2752,7 @@ var g = &grammar{
 						val:   "[dD]",
 						chars: []rune{'d', 'D'},
 					},
-					&ruleIRefExpr{index: 46 /* nos */},
+					&ruleIRefExpr{index: 69 /* nos */},
 				},
 			},
 		},
@@ -1567,7 +2760,7 @@ var g = &grammar{
 			name: "_diceType3",
 			expr: &seqExpr{
 				exprs: []any{
-					&ruleIRefExpr{index: 46 /* nos */},
+					&ruleIRefExpr{index: 69 /* nos */},
 					&charClassMatcher{
 						val:   "[dD]",
 						chars: []rune{'d', 'D'},
@@ -1590,7 +2783,7 @@ var g = &grammar{
 							&litMatcher{val: "劣势", want: "\"劣势\""},
 							&litMatcher{val: "劣勢", want: "\"劣勢\""},
 							&notExpr{
-								expr: &ruleIRefExpr{index: 100 /* xidStart */},
+								expr: &ruleIRefExpr{index: 137 /* xidStart */},
 							},
 						},
 					},
@@ -1610,12 +2803,12 @@ var g = &grammar{
 					},
 					&seqExpr{
 						exprs: []any{
-							&ruleIRefExpr{index: 46 /* nos */},
+							&ruleIRefExpr{index: 69 /* nos */},
 							&zeroOrOneExpr{
-								expr: &ruleIRefExpr{index: 49 /* _diceMod */},
+								expr: &ruleIRefExpr{index: 72 /* _diceMod */},
 							},
 							&zeroOrOneExpr{
-								expr: &ruleIRefExpr{index: 50 /* _diceModType2 */},
+								expr: &ruleIRefExpr{index: 73 /* _diceModType2 */},
 							},
 						},
 					},
@@ -1635,17 +2828,17 @@ var g = &grammar{
 					},
 					&seqExpr{
 						exprs: []any{
-							&ruleIRefExpr{index: 46 /* nos */},
+							&ruleIRefExpr{index: 69 /* nos */},
 							&zeroOrOneExpr{
 								expr: &choiceExpr{
 									alternatives: []any{
-										&ruleIRefExpr{index: 51 /* _dicePearMod */},
-										&ruleIRefExpr{index: 49 /* _diceMod */},
+										&ruleIRefExpr{index: 74 /* _dicePearMod */},
+										&ruleIRefExpr{index: 72 /* _diceMod */},
 									},
 								},
 							},
 							&zeroOrOneExpr{
-								expr: &ruleIRefExpr{index: 50 /* _diceModType2 */},
+								expr: &ruleIRefExpr{index: 73 /* _diceModType2 */},
 							},
 						},
 					},
@@ -1666,10 +2859,10 @@ var g = &grammar{
 					&seqExpr{
 						exprs: []any{
 							&zeroOrOneExpr{
-								expr: &ruleIRefExpr{index: 49 /* _diceMod */},
+								expr: &ruleIRefExpr{index: 72 /* _diceMod */},
 							},
 							&zeroOrOneExpr{
-								expr: &ruleIRefExpr{index: 50 /* _diceModType2 */},
+								expr: &ruleIRefExpr{index: 73 /* _diceModType2 */},
 							},
 						},
 					},
@@ -1692,13 +2885,13 @@ var g = &grammar{
 							&zeroOrOneExpr{
 								expr: &choiceExpr{
 									alternatives: []any{
-										&ruleIRefExpr{index: 51 /* _dicePearMod */},
-										&ruleIRefExpr{index: 49 /* _diceMod */},
+										&ruleIRefExpr{index: 74 /* _dicePearMod */},
+										&ruleIRefExpr{index: 72 /* _diceMod */},
 									},
 								},
 							},
 							&zeroOrOneExpr{
-								expr: &ruleIRefExpr{index: 50 /* _diceModType2 */},
+								expr: &ruleIRefExpr{index: 73 /* _diceModType2 */},
 							},
 						},
 					},
@@ -1712,11 +2905,11 @@ var g = &grammar{
 				expr: &seqExpr{
 					exprs: []any{
 						&andExpr{
-							expr: &ruleIRefExpr{index: 53 /* _diceType2 */},
+							expr: &ruleIRefExpr{index: 76 /* _diceType2 */},
 						},
-						&ruleIRefExpr{index: 47 /* detailStart */},
-						&ruleIRefExpr{index: 56 /* _diceExpr1 */},
-						&ruleIRefExpr{index: 48 /* detailEnd */},
+						&ruleIRefExpr{index: 70 /* detailStart */},
+						&ruleIRefExpr{index: 79 /* _diceExpr1 */},
+						&ruleIRefExpr{index: 71 /* detailEnd */},
 					},
 				},
 			},
@@ -1729,7 +2922,7 @@ var g = &grammar{
 						val:   "[aA]",
 						chars: []rune{'a', 'A'},
 					},
-					&ruleIRefExpr{index: 46 /* nos */},
+					&ruleIRefExpr{index: 69 /* nos */},
 					&zeroOrMoreExpr{
 						expr: &choiceExpr{
 							alternatives: []any{
@@ -1739,7 +2932,7 @@ var g = &grammar{
 											val:   "[mM]",
 											chars: []rune{'m', 'M'},
 										},
-										&ruleIRefExpr{index: 46 /* nos */},
+										&ruleIRefExpr{index: 69 /* nos */},
 									},
 								},
 								&seqExpr{
@@ -1748,7 +2941,7 @@ var g = &grammar{
 											val:   "[kK]",
 											chars: []rune{'k', 'K'},
 										},
-										&ruleIRefExpr{index: 46 /* nos */},
+										&ruleIRefExpr{index: 69 /* nos */},
 									},
 								},
 								&seqExpr{
@@ -1757,7 +2950,7 @@ var g = &grammar{
 											val:   "[qQ]",
 											chars: []rune{'q', 'Q'},
 										},
-										&ruleIRefExpr{index: 46 /* nos */},
+										&ruleIRefExpr{index: 69 /* nos */},
 									},
 								},
 							},
@@ -1772,15 +2965,15 @@ var g = &grammar{
 				alternatives: []any{
 					&seqExpr{
 						exprs: []any{
-							&ruleIRefExpr{index: 46 /* nos */},
-							&ruleIRefExpr{index: 61 /* _wodTypeMain */},
+							&ruleIRefExpr{index: 69 /* nos */},
+							&ruleIRefExpr{index: 84 /* _wodTypeMain */},
 						},
 					},
 					&seqExpr{
 						exprs: []any{
-							&ruleIRefExpr{index: 61 /* _wodTypeMain */},
+							&ruleIRefExpr{index: 84 /* _wodTypeMain */},
 							&notExpr{
-								expr: &ruleIRefExpr{index: 101 /* xidContinue */},
+								expr: &ruleIRefExpr{index: 138 /* xidContinue */},
 							},
 						},
 					},
@@ -1795,7 +2988,7 @@ var g = &grammar{
 						val:   "[aA]",
 						chars: []rune{'a', 'A'},
 					},
-					&ruleIRefExpr{index: 46 /* nos */},
+					&ruleIRefExpr{index: 69 /* nos */},
 					&zeroOrMoreExpr{
 						expr: &choiceExpr{
 							alternatives: []any{
@@ -1807,7 +3000,7 @@ var g = &grammar{
 												val:   "[mM]",
 												chars: []rune{'m', 'M'},
 											},
-											&ruleIRefExpr{index: 46 /* nos */},
+											&ruleIRefExpr{index: 69 /* nos */},
 										},
 									},
 								},
@@ -1819,7 +3012,7 @@ var g = &grammar{
 												val:   "[kK]",
 												chars: []rune{'k', 'K'},
 											},
-											&ruleIRefExpr{index: 46 /* nos */},
+											&ruleIRefExpr{index: 69 /* nos */},
 										},
 									},
 								},
@@ -1831,7 +3024,7 @@ var g = &grammar{
 												val:   "[qQ]",
 												chars: []rune{'q', 'Q'},
 											},
-											&ruleIRefExpr{index: 46 /* nos */},
+											&ruleIRefExpr{index: 69 /* nos */},
 										},
 									},
 								},
@@ -1853,14 +3046,14 @@ var g = &grammar{
 						alternatives: []any{
 							&seqExpr{
 								exprs: []any{
-									&ruleIRefExpr{index: 46 /* nos */},
+									&ruleIRefExpr{index: 69 /* nos */},
 									&notExpr{
-										expr: &ruleIRefExpr{index: 101 /* xidContinue */},
+										expr: &ruleIRefExpr{index: 138 /* xidContinue */},
 									},
 								},
 							},
 							&notExpr{
-								expr: &ruleIRefExpr{index: 101 /* xidContinue */},
+								expr: &ruleIRefExpr{index: 138 /* xidContinue */},
 							},
 						},
 					},
@@ -1881,21 +3074,21 @@ var g = &grammar{
 							alternatives: []any{
 								&seqExpr{
 									exprs: []any{
-										&ruleIRefExpr{index: 46 /* nos */},
+										&ruleIRefExpr{index: 69 /* nos */},
 										&notExpr{
-											expr: &ruleIRefExpr{index: 101 /* xidContinue */},
+											expr: &ruleIRefExpr{index: 138 /* xidContinue */},
 										},
 									},
 								},
 								&actionExpr{
 									run: (*parser).call_on_diceCocBonus_9,
 									expr: &notExpr{
-										expr: &ruleIRefExpr{index: 101 /* xidContinue */},
+										expr: &ruleIRefExpr{index: 138 /* xidContinue */},
 									},
 								},
 							},
 						},
-						&ruleIRefExpr{index: 48 /* detailEnd */},
+						&ruleIRefExpr{index: 71 /* detailEnd */},
 					},
 				},
 			},
@@ -1914,21 +3107,21 @@ var g = &grammar{
 							alternatives: []any{
 								&seqExpr{
 									exprs: []any{
-										&ruleIRefExpr{index: 46 /* nos */},
+										&ruleIRefExpr{index: 69 /* nos */},
 										&notExpr{
-											expr: &ruleIRefExpr{index: 101 /* xidContinue */},
+											expr: &ruleIRefExpr{index: 138 /* xidContinue */},
 										},
 									},
 								},
 								&actionExpr{
 									run: (*parser).call_on_diceCocPenalty_9,
 									expr: &notExpr{
-										expr: &ruleIRefExpr{index: 101 /* xidContinue */},
+										expr: &ruleIRefExpr{index: 138 /* xidContinue */},
 									},
 								},
 							},
 						},
-						&ruleIRefExpr{index: 48 /* detailEnd */},
+						&ruleIRefExpr{index: 71 /* detailEnd */},
 					},
 				},
 			},
@@ -1937,12 +3130,12 @@ var g = &grammar{
 			name: "_dcDiceType",
 			expr: &seqExpr{
 				exprs: []any{
-					&ruleIRefExpr{index: 46 /* nos */},
+					&ruleIRefExpr{index: 69 /* nos */},
 					&charClassMatcher{
 						val:   "[cC]",
 						chars: []rune{'c', 'C'},
 					},
-					&ruleIRefExpr{index: 46 /* nos */},
+					&ruleIRefExpr{index: 69 /* nos */},
 					&zeroOrMoreExpr{
 						expr: &seqExpr{
 							exprs: []any{
@@ -1950,7 +3143,7 @@ var g = &grammar{
 									val:   "[mM]",
 									chars: []rune{'m', 'M'},
 								},
-								&ruleIRefExpr{index: 46 /* nos */},
+								&ruleIRefExpr{index: 69 /* nos */},
 							},
 						},
 					},
@@ -1966,7 +3159,7 @@ var g = &grammar{
 						chars: []rune{'f', 'F'},
 					},
 					&notExpr{
-						expr: &ruleIRefExpr{index: 101 /* xidContinue */},
+						expr: &ruleIRefExpr{index: 138 /* xidContinue */},
 					},
 				},
 			},
@@ -1982,13 +3175,13 @@ var g = &grammar{
 								expr: &seqExpr{
 									exprs: []any{
 										&andCodeExpr{run: (*parser).call_onexprDice_5},
-										&ruleIRefExpr{index: 47 /* detailStart */},
+										&ruleIRefExpr{index: 70 /* detailStart */},
 									},
 								},
 							},
 							&actionExpr{
 								run:  (*parser).call_onexprDice_7,
-								expr: &ruleIRefExpr{index: 48 /* detailEnd */},
+								expr: &ruleIRefExpr{index: 71 /* detailEnd */},
 							},
 						},
 					},
@@ -1999,17 +3192,17 @@ var g = &grammar{
 								expr: &seqExpr{
 									exprs: []any{
 										&andExpr{
-											expr: &ruleIRefExpr{index: 52 /* _diceType1 */},
+											expr: &ruleIRefExpr{index: 75 /* _diceType1 */},
 										},
-										&ruleIRefExpr{index: 47 /* detailStart */},
-										&ruleIRefExpr{index: 46 /* nos */},
-										&ruleIRefExpr{index: 56 /* _diceExpr1 */},
-										&ruleIRefExpr{index: 48 /* detailEnd */},
+										&ruleIRefExpr{index: 70 /* detailStart */},
+										&ruleIRefExpr{index: 69 /* nos */},
+										&ruleIRefExpr{index: 79 /* _diceExpr1 */},
+										&ruleIRefExpr{index: 71 /* detailEnd */},
 									},
 								},
 							},
 							&zeroOrMoreExpr{
-								expr: &ruleIRefExpr{index: 60 /* _diceExprX */},
+								expr: &ruleIRefExpr{index: 83 /* _diceExprX */},
 							},
 						},
 					},
@@ -2020,16 +3213,16 @@ var g = &grammar{
 								expr: &seqExpr{
 									exprs: []any{
 										&andExpr{
-											expr: &ruleIRefExpr{index: 53 /* _diceType2 */},
+											expr: &ruleIRefExpr{index: 76 /* _diceType2 */},
 										},
-										&ruleIRefExpr{index: 47 /* detailStart */},
-										&ruleIRefExpr{index: 57 /* _diceExpr2 */},
-										&ruleIRefExpr{index: 48 /* detailEnd */},
+										&ruleIRefExpr{index: 70 /* detailStart */},
+										&ruleIRefExpr{index: 80 /* _diceExpr2 */},
+										&ruleIRefExpr{index: 71 /* detailEnd */},
 									},
 								},
 							},
 							&zeroOrMoreExpr{
-								expr: &ruleIRefExpr{index: 60 /* _diceExprX */},
+								expr: &ruleIRefExpr{index: 83 /* _diceExprX */},
 							},
 						},
 					},
@@ -2041,17 +3234,17 @@ var g = &grammar{
 									exprs: []any{
 										&andCodeExpr{run: (*parser).call_onexprDice_33},
 										&andExpr{
-											expr: &ruleIRefExpr{index: 54 /* _diceType3 */},
+											expr: &ruleIRefExpr{index: 77 /* _diceType3 */},
 										},
-										&ruleIRefExpr{index: 47 /* detailStart */},
-										&ruleIRefExpr{index: 46 /* nos */},
-										&ruleIRefExpr{index: 58 /* _diceExpr3 */},
-										&ruleIRefExpr{index: 48 /* detailEnd */},
+										&ruleIRefExpr{index: 70 /* detailStart */},
+										&ruleIRefExpr{index: 69 /* nos */},
+										&ruleIRefExpr{index: 81 /* _diceExpr3 */},
+										&ruleIRefExpr{index: 71 /* detailEnd */},
 									},
 								},
 							},
 							&zeroOrMoreExpr{
-								expr: &ruleIRefExpr{index: 60 /* _diceExprX */},
+								expr: &ruleIRefExpr{index: 83 /* _diceExprX */},
 							},
 						},
 					},
@@ -2063,9 +3256,9 @@ var g = &grammar{
 									exprs: []any{
 										&andCodeExpr{run: (*parser).call_onexprDice_45},
 										&andExpr{
-											expr: &ruleIRefExpr{index: 55 /* _diceType4 */},
+											expr: &ruleIRefExpr{index: 78 /* _diceType4 */},
 										},
-										&ruleIRefExpr{index: 47 /* detailStart */},
+										&ruleIRefExpr{index: 70 /* detailStart */},
 									},
 								},
 							},
@@ -2073,13 +3266,13 @@ var g = &grammar{
 								run: (*parser).call_onexprDice_49,
 								expr: &seqExpr{
 									exprs: []any{
-										&ruleIRefExpr{index: 59 /* _diceExpr4 */},
-										&ruleIRefExpr{index: 48 /* detailEnd */},
+										&ruleIRefExpr{index: 82 /* _diceExpr4 */},
+										&ruleIRefExpr{index: 71 /* detailEnd */},
 									},
 								},
 							},
 							&zeroOrMoreExpr{
-								expr: &ruleIRefExpr{index: 60 /* _diceExprX */},
+								expr: &ruleIRefExpr{index: 83 /* _diceExprX */},
 							},
 						},
 					},
@@ -2087,13 +3280,13 @@ var g = &grammar{
 						exprs: []any{
 							&andCodeExpr{run: (*parser).call_onexprDice_56},
 							&andExpr{
-								expr: &ruleIRefExpr{index: 64 /* _cocDiceType */},
+								expr: &ruleIRefExpr{index: 87 /* _cocDiceType */},
 							},
-							&ruleIRefExpr{index: 47 /* detailStart */},
+							&ruleIRefExpr{index: 70 /* detailStart */},
 							&choiceExpr{
 								alternatives: []any{
-									&ruleIRefExpr{index: 65 /* _diceCocBonus */},
-									&ruleIRefExpr{index: 66 /* _diceCocPenalty */},
+									&ruleIRefExpr{index: 88 /* _diceCocBonus */},
+									&ruleIRefExpr{index: 89 /* _diceCocPenalty */},
 								},
 							},
 						},
@@ -2106,9 +3299,9 @@ var g = &grammar{
 									exprs: []any{
 										&andCodeExpr{run: (*parser).call_onexprDice_66},
 										&andExpr{
-											expr: &ruleIRefExpr{index: 62 /* _wodDiceType */},
+											expr: &ruleIRefExpr{index: 85 /* _wodDiceType */},
 										},
-										&ruleIRefExpr{index: 47 /* detailStart */},
+										&ruleIRefExpr{index: 70 /* detailStart */},
 									},
 								},
 							},
@@ -2122,22 +3315,22 @@ var g = &grammar{
 													exprs: []any{
 														&actionExpr{
 															run:  (*parser).call_onexprDice_74,
-															expr: &ruleIRefExpr{index: 46 /* nos */},
+															expr: &ruleIRefExpr{index: 69 /* nos */},
 														},
-														&ruleIRefExpr{index: 63 /* _wodMain */},
+														&ruleIRefExpr{index: 86 /* _wodMain */},
 													},
 												},
 												&seqExpr{
 													exprs: []any{
-														&ruleIRefExpr{index: 63 /* _wodMain */},
+														&ruleIRefExpr{index: 86 /* _wodMain */},
 														&notExpr{
-															expr: &ruleIRefExpr{index: 101 /* xidContinue */},
+															expr: &ruleIRefExpr{index: 138 /* xidContinue */},
 														},
 													},
 												},
 											},
 										},
-										&ruleIRefExpr{index: 48 /* detailEnd */},
+										&ruleIRefExpr{index: 71 /* detailEnd */},
 									},
 								},
 							},
@@ -2151,15 +3344,15 @@ var g = &grammar{
 									exprs: []any{
 										&andCodeExpr{run: (*parser).call_onexprDice_85},
 										&andExpr{
-											expr: &ruleIRefExpr{index: 67 /* _dcDiceType */},
+											expr: &ruleIRefExpr{index: 90 /* _dcDiceType */},
 										},
-										&ruleIRefExpr{index: 47 /* detailStart */},
+										&ruleIRefExpr{index: 70 /* detailStart */},
 									},
 								},
 							},
 							&actionExpr{
 								run:  (*parser).call_onexprDice_89,
-								expr: &ruleIRefExpr{index: 46 /* nos */},
+								expr: &ruleIRefExpr{index: 69 /* nos */},
 							},
 							&actionExpr{
 								run: (*parser).call_onexprDice_91,
@@ -2169,7 +3362,7 @@ var g = &grammar{
 											val:   "[cC]",
 											chars: []rune{'c', 'C'},
 										},
-										&ruleIRefExpr{index: 46 /* nos */},
+										&ruleIRefExpr{index: 69 /* nos */},
 										&zeroOrMoreExpr{
 											expr: &actionExpr{
 												run: (*parser).call_onexprDice_96,
@@ -2179,12 +3372,12 @@ var g = &grammar{
 															val:   "[mM]",
 															chars: []rune{'m', 'M'},
 														},
-														&ruleIRefExpr{index: 46 /* nos */},
+														&ruleIRefExpr{index: 69 /* nos */},
 													},
 												},
 											},
 										},
-										&ruleIRefExpr{index: 48 /* detailEnd */},
+										&ruleIRefExpr{index: 71 /* detailEnd */},
 									},
 								},
 							},
@@ -2196,21 +3389,21 @@ var g = &grammar{
 							exprs: []any{
 								&andCodeExpr{run: (*parser).call_onexprDice_103},
 								&andExpr{
-									expr: &ruleIRefExpr{index: 68 /* _fateDiceType */},
+									expr: &ruleIRefExpr{index: 91 /* _fateDiceType */},
 								},
-								&ruleIRefExpr{index: 47 /* detailStart */},
+								&ruleIRefExpr{index: 70 /* detailStart */},
 								&charClassMatcher{
 									val:   "[fF]",
 									chars: []rune{'f', 'F'},
 								},
 								&notExpr{
-									expr: &ruleIRefExpr{index: 101 /* xidContinue */},
+									expr: &ruleIRefExpr{index: 138 /* xidContinue */},
 								},
-								&ruleIRefExpr{index: 48 /* detailEnd */},
+								&ruleIRefExpr{index: 71 /* detailEnd */},
 							},
 						},
 					},
-					&ruleIRefExpr{index: 81 /* value */},
+					&ruleIRefExpr{index: 107 /* value */},
 				},
 			},
 		},
@@ -2228,7 +3421,7 @@ var g = &grammar{
 								alternatives: []any{
 									&actionExpr{
 										run:  (*parser).call_onarray_call_6,
-										expr: &ruleIRefExpr{index: 82 /* number */},
+										expr: &ruleIRefExpr{index: 108 /* number */},
 									},
 									&codeExpr{
 										run: (*parser).call_onarray_call_8,
@@ -2247,7 +3440,7 @@ var g = &grammar{
 								alternatives: []any{
 									&actionExpr{
 										run:  (*parser).call_onarray_call_13,
-										expr: &ruleIRefExpr{index: 82 /* number */},
+										expr: &ruleIRefExpr{index: 108 /* number */},
 									},
 									&codeExpr{
 										run: (*parser).call_onarray_call_15,
@@ -2262,11 +3455,11 @@ var g = &grammar{
 							expr: &seqExpr{
 								exprs: []any{
 									&litMatcher{val: "[", want: "\"[\""},
-									&ruleIRefExpr{index: 123 /* sp */},
-									&ruleIRefExpr{index: 26 /* exprRoot */},
-									&ruleIRefExpr{index: 123 /* sp */},
+									&ruleIRefExpr{index: 164 /* sp */},
+									&ruleIRefExpr{index: 49 /* exprRoot */},
+									&ruleIRefExpr{index: 164 /* sp */},
 									&litMatcher{val: "]", want: "\"]\""},
-									&ruleIRefExpr{index: 123 /* sp */},
+									&ruleIRefExpr{index: 164 /* sp */},
 								},
 							},
 						},
@@ -2283,20 +3476,41 @@ var g = &grammar{
 							run: (*parser).call_onitem_getX_3,
 							expr: &seqExpr{
 								exprs: []any{
+									&zeroOrOneExpr{
+										expr: &seqExpr{
+											exprs: []any{
+												&andExpr{
+													expr: &seqExpr{
+														exprs: []any{
+															&litMatcher{val: "?.", want: "\"?.\""},
+															&ruleIRefExpr{index: 164 /* sp */},
+															&litMatcher{val: "[", want: "\"[\""},
+														},
+													},
+												},
+												&ruleIRefExpr{index: 98 /* optChain */},
+											},
+										},
+									},
 									&litMatcher{val: "[", want: "\"[\""},
-									&ruleIRefExpr{index: 123 /* sp */},
-									&ruleIRefExpr{index: 26 /* exprRoot */},
-									&ruleIRefExpr{index: 123 /* sp */},
+									&ruleIRefExpr{index: 164 /* sp */},
+									&ruleIRefExpr{index: 49 /* exprRoot */},
+									&ruleIRefExpr{index: 164 /* sp */},
 									&litMatcher{val: "]", want: "\"]\""},
-									&ruleIRefExpr{index: 123 /* sp */},
+									&ruleIRefExpr{index: 164 /* sp */},
 									&notExpr{
-										expr: &litMatcher{val: "=", want: "\"=\""},
+										expr: &choiceExpr{
+											alternatives: []any{
+												&litMatcher{val: "=", want: "\"=\""},
+												&ruleIRefExpr{index: 163 /* assignOp */},
+											},
+										},
 									},
 								},
 							},
 						},
 						&zeroOrOneExpr{
-							expr: &ruleIRefExpr{index: 76 /* func_invoke */},
+							expr: &ruleIRefExpr{index: 101 /* func_invoke */},
 						},
 					},
 				},
@@ -2308,9 +3522,9 @@ var g = &grammar{
 				expr: &seqExpr{
 					exprs: []any{
 						&andLogicalExpr{
-							expr: &ruleIRefExpr{index: 71 /* item_getX */},
+							expr: &ruleIRefExpr{index: 94 /* item_getX */},
 						},
-						&ruleIRefExpr{index: 71 /* item_getX */},
+						&ruleIRefExpr{index: 94 /* item_getX */},
 					},
 				},
 			},
@@ -2321,22 +3535,40 @@ var g = &grammar{
 			expr: &zeroOrMoreExpr{
 				expr: &seqExpr{
 					exprs: []any{
-						&litMatcher{val: ".", want: "\".\""},
 						&actionExpr{
-							run: (*parser).call_onattr_getX_4,
+							run: (*parser).call_onattr_getX_3,
 							expr: &seqExpr{
 								exprs: []any{
-									&ruleIRefExpr{index: 123 /* sp */},
+									&choiceExpr{
+										alternatives: []any{
+											&litMatcher{val: ".", want: "\".\""},
+											&seqExpr{
+												exprs: []any{
+													&andExpr{
+														expr: &seqExpr{
+															exprs: []any{
+																&litMatcher{val: "?.", want: "\"?.\""},
+																&ruleIRefExpr{index: 164 /* sp */},
+																&ruleIRefExpr{index: 135 /* identifier */},
+															},
+														},
+													},
+													&ruleIRefExpr{index: 98 /* optChain */},
+												},
+											},
+										},
+									},
+									&ruleIRefExpr{index: 164 /* sp */},
 									&labeledExpr{
 										label: "id",
-										expr:  &ruleIRefExpr{index: 98 /* identifier */},
+										expr:  &ruleIRefExpr{index: 135 /* identifier */},
 									},
-									&ruleIRefExpr{index: 123 /* sp */},
+									&ruleIRefExpr{index: 164 /* sp */},
 								},
 							},
 						},
 						&zeroOrOneExpr{
-							expr: &ruleIRefExpr{index: 76 /* func_invoke */},
+							expr: &ruleIRefExpr{index: 101 /* func_invoke */},
 						},
 					},
 				},
@@ -2348,9 +3580,21 @@ var g = &grammar{
 				expr: &seqExpr{
 					exprs: []any{
 						&andLogicalExpr{
-							expr: &ruleIRefExpr{index: 73 /* attr_getX */},
+							expr: &ruleIRefExpr{index: 96 /* attr_getX */},
 						},
-						&ruleIRefExpr{index: 73 /* attr_getX */},
+						&ruleIRefExpr{index: 96 /* attr_getX */},
+					},
+				},
+			},
+		},
+		{
+			name: "optChain",
+			expr: &actionExpr{
+				run: (*parser).call_onoptChain_1,
+				expr: &seqExpr{
+					exprs: []any{
+						&litMatcher{val: "?.", want: "\"?.\""},
+						&ruleIRefExpr{index: 164 /* sp */},
 					},
 				},
 			},
@@ -2364,7 +3608,7 @@ var g = &grammar{
 						expr: &seqExpr{
 							exprs: []any{
 								&litMatcher{val: "(", want: "\"(\""},
-								&ruleIRefExpr{index: 123 /* sp */},
+								&ruleIRefExpr{index: 164 /* sp */},
 							},
 						},
 					},
@@ -2372,21 +3616,21 @@ var g = &grammar{
 						run: (*parser).call_onfunc_invoke2_6,
 						expr: &seqExpr{
 							exprs: []any{
-								&ruleIRefExpr{index: 26 /* exprRoot */},
-								&ruleIRefExpr{index: 123 /* sp */},
+								&ruleIRefExpr{index: 100 /* func_arg */},
+								&ruleIRefExpr{index: 164 /* sp */},
 								&zeroOrMoreExpr{
 									expr: &actionExpr{
 										run: (*parser).call_onfunc_invoke2_11,
 										expr: &seqExpr{
 											exprs: []any{
 												&litMatcher{val: ",", want: "\",\""},
-												&ruleIRefExpr{index: 123 /* sp */},
-												&ruleIRefExpr{index: 26 /* exprRoot */},
+												&ruleIRefExpr{index: 164 /* sp */},
+												&ruleIRefExpr{index: 100 /* func_arg */},
 											},
 										},
 									},
 								},
-								&ruleIRefExpr{index: 123 /* sp */},
+								&ruleIRefExpr{index: 164 /* sp */},
 								&litMatcher{val: ")", want: "\")\""},
 							},
 						},
@@ -2395,25 +3639,92 @@ var g = &grammar{
 			},
 		},
 		{
-			name: "func_invoke",
+			name:      "func_arg",
+			varExists: true,
 			expr: &choiceExpr{
 				alternatives: []any{
 					&actionExpr{
-						run: (*parser).call_onfunc_invoke_2,
+						run: (*parser).call_onfunc_arg_2,
 						expr: &seqExpr{
 							exprs: []any{
-								&litMatcher{val: "(", want: "\"(\""},
-								&ruleIRefExpr{index: 123 /* sp */},
-								&litMatcher{val: ")", want: "\")\""},
+								&andExpr{
+									expr: &seqExpr{
+										exprs: []any{
+											&ruleIRefExpr{index: 136 /* identifierWithoutColon */},
+											&ruleIRefExpr{index: 164 /* sp */},
+											&litMatcher{val: ":", want: "\":\""},
+											&charClassMatcher{
+												val:   "[ \\n\\t\\r]",
+												chars: []rune{' ', '\n', '\t', '\r'},
+											},
+										},
+									},
+								},
+								&labeledExpr{
+									label: "id",
+									expr:  &ruleIRefExpr{index: 136 /* identifierWithoutColon */},
+								},
+								&ruleIRefExpr{index: 164 /* sp */},
+								&litMatcher{val: ":", want: "\":\""},
+								&ruleIRefExpr{index: 164 /* sp */},
+								&ruleIRefExpr{index: 49 /* exprRoot */},
 							},
 						},
 					},
-					&seqExpr{
-						exprs: []any{
-							&andExpr{
-								expr: &ruleIRefExpr{index: 75 /* func_invoke2 */},
+					&actionExpr{
+						run:  (*parser).call_onfunc_arg_16,
+						expr: &ruleIRefExpr{index: 49 /* exprRoot */},
+					},
+				},
+			},
+		},
+		{
+			name: "func_invoke",
+			expr: &seqExpr{
+				exprs: []any{
+					&zeroOrOneExpr{
+						expr: &seqExpr{
+							exprs: []any{
+								&andExpr{
+									expr: &seqExpr{
+										exprs: []any{
+											&litMatcher{val: "?.", want: "\"?.\""},
+											&ruleIRefExpr{index: 164 /* sp */},
+											&litMatcher{val: "(", want: "\"(\""},
+										},
+									},
+								},
+								&ruleIRefExpr{index: 98 /* optChain */},
+							},
+						},
+					},
+					&ruleIRefExpr{index: 102 /* func_call */},
+				},
+			},
+		},
+		{
+			name: "func_call",
+			expr: &actionExpr{
+				run: (*parser).call_onfunc_call_1,
+				expr: &choiceExpr{
+					alternatives: []any{
+						&actionExpr{
+							run: (*parser).call_onfunc_call_3,
+							expr: &seqExpr{
+								exprs: []any{
+									&litMatcher{val: "(", want: "\"(\""},
+									&ruleIRefExpr{index: 164 /* sp */},
+									&litMatcher{val: ")", want: "\")\""},
+								},
+							},
+						},
+						&seqExpr{
+							exprs: []any{
+								&andExpr{
+									expr: &ruleIRefExpr{index: 99 /* func_invoke2 */},
+								},
+								&ruleIRefExpr{index: 99 /* func_invoke2 */},
 							},
-							&ruleIRefExpr{index: 75 /* func_invoke2 */},
 						},
 					},
 				},
@@ -2429,17 +3740,36 @@ var g = &grammar{
 							exprs: []any{
 								&choiceExpr{
 									alternatives: []any{
-										&ruleIRefExpr{index: 78 /* value_id_without_colon */},
-										&ruleIRefExpr{index: 26 /* exprRoot */},
+										&seqExpr{
+											exprs: []any{
+												&notExpr{
+													expr: &seqExpr{
+														exprs: []any{
+															&choiceExpr{
+																alternatives: []any{
+																	&litMatcher{val: "true", want: "\"true\""},
+																	&litMatcher{val: "false", want: "\"false\""},
+																},
+															},
+															&notExpr{
+																expr: &ruleIRefExpr{index: 138 /* xidContinue */},
+															},
+														},
+													},
+												},
+												&ruleIRefExpr{index: 104 /* value_id_without_colon */},
+											},
+										},
+										&ruleIRefExpr{index: 49 /* exprRoot */},
 									},
 								},
-								&ruleIRefExpr{index: 123 /* sp */},
+								&ruleIRefExpr{index: 164 /* sp */},
 								&litMatcher{val: ":", want: "\":\""},
-								&ruleIRefExpr{index: 123 /* sp */},
-								&ruleIRefExpr{index: 26 /* exprRoot */},
+								&ruleIRefExpr{index: 164 /* sp */},
+								&ruleIRefExpr{index: 49 /* exprRoot */},
 							},
 						},
-						&ruleIRefExpr{index: 123 /* sp */},
+						&ruleIRefExpr{index: 164 /* sp */},
 					},
 				},
 			},
@@ -2455,19 +3785,25 @@ var g = &grammar{
 							exprs: []any{
 								&labeledExpr{
 									label: "id",
-									expr:  &ruleIRefExpr{index: 99 /* identifierWithoutColon */},
+									expr:  &ruleIRefExpr{index: 136 /* identifierWithoutColon */},
 								},
-								&ruleIRefExpr{index: 123 /* sp */},
+								&ruleIRefExpr{index: 164 /* sp */},
 							},
 						},
 					},
-					&seqExpr{
-						exprs: []any{
-							&zeroOrOneExpr{
-								expr: &ruleIRefExpr{index: 76 /* func_invoke */},
+					&codeExpr{
+						run: (*parser).call_onvalue_id_without_colon_7,
+					},
+					&actionExpr{
+						run: (*parser).call_onvalue_id_without_colon_8,
+						expr: &seqExpr{
+							exprs: []any{
+								&zeroOrOneExpr{
+									expr: &ruleIRefExpr{index: 101 /* func_invoke */},
+								},
+								&ruleIRefExpr{index: 95 /* item_get */},
+								&ruleIRefExpr{index: 97 /* attr_get */},
 							},
-							&ruleIRefExpr{index: 72 /* item_get */},
-							&ruleIRefExpr{index: 74 /* attr_get */},
 						},
 					},
 				},
@@ -2480,13 +3816,13 @@ var g = &grammar{
 				expr: &seqExpr{
 					exprs: []any{
 						&litMatcher{val: "[", want: "\"[\""},
-						&ruleIRefExpr{index: 123 /* sp */},
-						&ruleIRefExpr{index: 26 /* exprRoot */},
+						&ruleIRefExpr{index: 164 /* sp */},
+						&ruleIRefExpr{index: 49 /* exprRoot */},
 						&litMatcher{val: "..", want: "\"..\""},
-						&ruleIRefExpr{index: 123 /* sp */},
-						&ruleIRefExpr{index: 26 /* exprRoot */},
+						&ruleIRefExpr{index: 164 /* sp */},
+						&ruleIRefExpr{index: 49 /* exprRoot */},
 						&litMatcher{val: "]", want: "\"]\""},
-						&ruleIRefExpr{index: 123 /* sp */},
+						&ruleIRefExpr{index: 164 /* sp */},
 					},
 				},
 			},
@@ -2500,7 +3836,7 @@ var g = &grammar{
 						expr: &seqExpr{
 							exprs: []any{
 								&litMatcher{val: "[", want: "\"[\""},
-								&ruleIRefExpr{index: 123 /* sp */},
+								&ruleIRefExpr{index: 164 /* sp */},
 							},
 						},
 					},
@@ -2508,21 +3844,21 @@ var g = &grammar{
 						run: (*parser).call_onvalue_array_6,
 						expr: &seqExpr{
 							exprs: []any{
-								&ruleIRefExpr{index: 26 /* exprRoot */},
+								&ruleIRefExpr{index: 49 /* exprRoot */},
 								&zeroOrMoreExpr{
 									expr: &actionExpr{
 										run: (*parser).call_onvalue_array_10,
 										expr: &seqExpr{
 											exprs: []any{
 												&litMatcher{val: ",", want: "\",\""},
-												&ruleIRefExpr{index: 123 /* sp */},
-												&ruleIRefExpr{index: 26 /* exprRoot */},
+												&ruleIRefExpr{index: 164 /* sp */},
+												&ruleIRefExpr{index: 49 /* exprRoot */},
 											},
 										},
 									},
 								},
 								&litMatcher{val: "]", want: "\"]\""},
-								&ruleIRefExpr{index: 123 /* sp */},
+								&ruleIRefExpr{index: 164 /* sp */},
 							},
 						},
 					},
@@ -2539,7 +3875,7 @@ var g = &grammar{
 						expr: &seqExpr{
 							exprs: []any{
 								&litMatcher{val: "true", want: "\"true\""},
-								&ruleIRefExpr{index: 123 /* sp */},
+								&ruleIRefExpr{index: 164 /* sp */},
 							},
 						},
 					},
@@ -2548,7 +3884,7 @@ var g = &grammar{
 						expr: &seqExpr{
 							exprs: []any{
 								&litMatcher{val: "false", want: "\"false\""},
-								&ruleIRefExpr{index: 123 /* sp */},
+								&ruleIRefExpr{index: 164 /* sp */},
 							},
 						},
 					},
@@ -2557,7 +3893,7 @@ var g = &grammar{
 						expr: &seqExpr{
 							exprs: []any{
 								&litMatcher{val: "null", want: "\"null\""},
-								&ruleIRefExpr{index: 123 /* sp */},
+								&ruleIRefExpr{index: 164 /* sp */},
 							},
 						},
 					},
@@ -2568,14 +3904,20 @@ var g = &grammar{
 								expr: &seqExpr{
 									exprs: []any{
 										&litMatcher{val: "this", want: "\"this\""},
-										&ruleIRefExpr{index: 123 /* sp */},
+										&ruleIRefExpr{index: 164 /* sp */},
 									},
 								},
 							},
-							&seqExpr{
-								exprs: []any{
-									&ruleIRefExpr{index: 72 /* item_get */},
-									&ruleIRefExpr{index: 74 /* attr_get */},
+							&codeExpr{
+								run: (*parser).call_onvalue_19,
+							},
+							&actionExpr{
+								run: (*parser).call_onvalue_20,
+								expr: &seqExpr{
+									exprs: []any{
+										&ruleIRefExpr{index: 95 /* item_get */},
+										&ruleIRefExpr{index: 97 /* attr_get */},
+									},
 								},
 							},
 						},
@@ -2583,130 +3925,219 @@ var g = &grammar{
 					&seqExpr{
 						exprs: []any{
 							&actionExpr{
-								run: (*parser).call_onvalue_23,
+								run: (*parser).call_onvalue_25,
 								expr: &seqExpr{
 									exprs: []any{
 										&litMatcher{val: "&", want: "\"&\""},
 										&labeledExpr{
 											label: "id",
-											expr:  &ruleIRefExpr{index: 98 /* identifier */},
+											expr:  &ruleIRefExpr{index: 135 /* identifier */},
 										},
-										&ruleIRefExpr{index: 123 /* sp */},
+										&ruleIRefExpr{index: 164 /* sp */},
+									},
+								},
+							},
+							&codeExpr{
+								run: (*parser).call_onvalue_31,
+							},
+							&actionExpr{
+								run:  (*parser).call_onvalue_32,
+								expr: &ruleIRefExpr{index: 97 /* attr_get */},
+							},
+						},
+					},
+					&ruleIRefExpr{index: 109 /* float */},
+					&ruleIRefExpr{index: 108 /* number */},
+					&seqExpr{
+						exprs: []any{
+							&andExpr{
+								expr: &seqExpr{
+									exprs: []any{
+										&litMatcher{val: "match", want: "\"match\""},
+										&ruleIRefExpr{index: 166 /* sp1x */},
+										&ruleIRefExpr{index: 49 /* exprRoot */},
+										&ruleIRefExpr{index: 164 /* sp */},
+										&litMatcher{val: "{", want: "\"{\""},
 									},
 								},
 							},
-							&ruleIRefExpr{index: 74 /* attr_get */},
+							&ruleIRefExpr{index: 23 /* stmtMatch */},
 						},
 					},
-					&ruleIRefExpr{index: 83 /* float */},
-					&ruleIRefExpr{index: 82 /* number */},
 					&seqExpr{
 						exprs: []any{
 							&actionExpr{
-								run: (*parser).call_onvalue_33,
+								run: (*parser).call_onvalue_46,
 								expr: &seqExpr{
 									exprs: []any{
 										&andExpr{
 											expr: &seqExpr{
 												exprs: []any{
-													&ruleIRefExpr{index: 98 /* identifier */},
-													&ruleIRefExpr{index: 126 /* spNoCR */},
+													&ruleIRefExpr{index: 135 /* identifier */},
+													&ruleIRefExpr{index: 167 /* spNoCR */},
 												},
 											},
 										},
-										&ruleIRefExpr{index: 47 /* detailStart */},
+										&ruleIRefExpr{index: 70 /* detailStart */},
 										&labeledExpr{
 											label: "id",
-											expr:  &ruleIRefExpr{index: 98 /* identifier */},
+											expr:  &ruleIRefExpr{index: 135 /* identifier */},
 										},
-										&ruleIRefExpr{index: 48 /* detailEnd */},
-										&ruleIRefExpr{index: 126 /* spNoCR */},
+										&ruleIRefExpr{index: 71 /* detailEnd */},
+										&ruleIRefExpr{index: 167 /* spNoCR */},
 									},
 								},
 							},
-							&seqExpr{
-								exprs: []any{
-									&zeroOrOneExpr{
-										expr: &ruleIRefExpr{index: 76 /* func_invoke */},
+							&codeExpr{
+								run: (*parser).call_onvalue_57,
+							},
+							&actionExpr{
+								run: (*parser).call_onvalue_58,
+								expr: &seqExpr{
+									exprs: []any{
+										&zeroOrOneExpr{
+											expr: &ruleIRefExpr{index: 101 /* func_invoke */},
+										},
+										&ruleIRefExpr{index: 95 /* item_get */},
+										&ruleIRefExpr{index: 97 /* attr_get */},
 									},
-									&ruleIRefExpr{index: 72 /* item_get */},
-									&ruleIRefExpr{index: 74 /* attr_get */},
 								},
 							},
 						},
 					},
-					&ruleIRefExpr{index: 95 /* fstring */},
 					&seqExpr{
 						exprs: []any{
-							&ruleIRefExpr{index: 102 /* sub */},
-							&ruleIRefExpr{index: 72 /* item_get */},
-							&ruleIRefExpr{index: 74 /* attr_get */},
+							&actionExpr{
+								run:  (*parser).call_onvalue_65,
+								expr: &ruleIRefExpr{index: 125 /* fstring */},
+							},
+							&actionExpr{
+								run:  (*parser).call_onvalue_67,
+								expr: &ruleIRefExpr{index: 97 /* attr_get */},
+							},
+						},
+					},
+					&seqExpr{
+						exprs: []any{
+							&actionExpr{
+								run:  (*parser).call_onvalue_70,
+								expr: &ruleIRefExpr{index: 139 /* sub */},
+							},
+							&actionExpr{
+								run: (*parser).call_onvalue_72,
+								expr: &seqExpr{
+									exprs: []any{
+										&ruleIRefExpr{index: 95 /* item_get */},
+										&ruleIRefExpr{index: 97 /* attr_get */},
+									},
+								},
+							},
 						},
 					},
 					&seqExpr{
 						exprs: []any{
 							&actionExpr{
-								run: (*parser).call_onvalue_55,
+								run: (*parser).call_onvalue_77,
 								expr: &seqExpr{
 									exprs: []any{
 										&litMatcher{val: "[", want: "\"[\""},
-										&ruleIRefExpr{index: 123 /* sp */},
+										&ruleIRefExpr{index: 164 /* sp */},
 										&litMatcher{val: "]", want: "\"]\""},
-										&ruleIRefExpr{index: 123 /* sp */},
+										&ruleIRefExpr{index: 164 /* sp */},
 									},
 								},
 							},
-							&seqExpr{
-								exprs: []any{
-									&zeroOrOneExpr{
-										expr: &ruleIRefExpr{index: 70 /* array_call */},
+							&codeExpr{
+								run: (*parser).call_onvalue_83,
+							},
+							&actionExpr{
+								run: (*parser).call_onvalue_84,
+								expr: &seqExpr{
+									exprs: []any{
+										&zeroOrOneExpr{
+											expr: &ruleIRefExpr{index: 93 /* array_call */},
+										},
+										&ruleIRefExpr{index: 97 /* attr_get */},
+									},
+								},
+							},
+						},
+					},
+					&seqExpr{
+						exprs: []any{
+							&actionExpr{
+								run: (*parser).call_onvalue_90,
+								expr: &seqExpr{
+									exprs: []any{
+										&andExpr{
+											expr: &ruleIRefExpr{index: 105 /* value_array_range */},
+										},
+										&ruleIRefExpr{index: 105 /* value_array_range */},
+									},
+								},
+							},
+							&actionExpr{
+								run: (*parser).call_onvalue_95,
+								expr: &seqExpr{
+									exprs: []any{
+										&zeroOrOneExpr{
+											expr: &ruleIRefExpr{index: 93 /* array_call */},
+										},
+										&ruleIRefExpr{index: 97 /* attr_get */},
 									},
-									&ruleIRefExpr{index: 74 /* attr_get */},
 								},
 							},
 						},
 					},
 					&seqExpr{
 						exprs: []any{
-							&andExpr{
-								expr: &ruleIRefExpr{index: 79 /* value_array_range */},
-							},
-							&ruleIRefExpr{index: 79 /* value_array_range */},
-							&zeroOrOneExpr{
-								expr: &ruleIRefExpr{index: 70 /* array_call */},
-							},
-							&ruleIRefExpr{index: 74 /* attr_get */},
-						},
-					},
-					&seqExpr{
-						exprs: []any{
-							&andExpr{
-								expr: &ruleIRefExpr{index: 80 /* value_array */},
+							&actionExpr{
+								run: (*parser).call_onvalue_101,
+								expr: &seqExpr{
+									exprs: []any{
+										&andExpr{
+											expr: &ruleIRefExpr{index: 106 /* value_array */},
+										},
+										&ruleIRefExpr{index: 106 /* value_array */},
+									},
+								},
 							},
-							&ruleIRefExpr{index: 80 /* value_array */},
-							&zeroOrOneExpr{
-								expr: &ruleIRefExpr{index: 70 /* array_call */},
+							&actionExpr{
+								run: (*parser).call_onvalue_106,
+								expr: &seqExpr{
+									exprs: []any{
+										&zeroOrOneExpr{
+											expr: &ruleIRefExpr{index: 93 /* array_call */},
+										},
+										&ruleIRefExpr{index: 97 /* attr_get */},
+									},
+								},
 							},
-							&ruleIRefExpr{index: 74 /* attr_get */},
 						},
 					},
 					&seqExpr{
 						exprs: []any{
 							&actionExpr{
-								run: (*parser).call_onvalue_80,
+								run: (*parser).call_onvalue_112,
 								expr: &seqExpr{
 									exprs: []any{
 										&litMatcher{val: "{", want: "\"{\""},
-										&ruleIRefExpr{index: 123 /* sp */},
+										&ruleIRefExpr{index: 164 /* sp */},
 										&litMatcher{val: "}", want: "\"}\""},
-										&ruleIRefExpr{index: 123 /* sp */},
+										&ruleIRefExpr{index: 164 /* sp */},
 									},
 								},
 							},
-							&seqExpr{
-								exprs: []any{
-									&ruleIRefExpr{index: 72 /* item_get */},
-									&ruleIRefExpr{index: 74 /* attr_get */},
+							&codeExpr{
+								run: (*parser).call_onvalue_118,
+							},
+							&actionExpr{
+								run: (*parser).call_onvalue_119,
+								expr: &seqExpr{
+									exprs: []any{
+										&ruleIRefExpr{index: 95 /* item_get */},
+										&ruleIRefExpr{index: 97 /* attr_get */},
+									},
 								},
 							},
 						},
@@ -2714,25 +4145,25 @@ var g = &grammar{
 					&seqExpr{
 						exprs: []any{
 							&actionExpr{
-								run: (*parser).call_onvalue_90,
+								run: (*parser).call_onvalue_124,
 								expr: &seqExpr{
 									exprs: []any{
 										&litMatcher{val: "{", want: "\"{\""},
-										&ruleIRefExpr{index: 123 /* sp */},
+										&ruleIRefExpr{index: 164 /* sp */},
 									},
 								},
 							},
 							&actionExpr{
-								run: (*parser).call_onvalue_94,
+								run: (*parser).call_onvalue_128,
 								expr: &seqExpr{
 									exprs: []any{
-										&ruleIRefExpr{index: 77 /* dict_item */},
+										&ruleIRefExpr{index: 103 /* dict_item */},
 										&zeroOrMoreExpr{
 											expr: &seqExpr{
 												exprs: []any{
 													&litMatcher{val: ",", want: "\",\""},
-													&ruleIRefExpr{index: 123 /* sp */},
-													&ruleIRefExpr{index: 77 /* dict_item */},
+													&ruleIRefExpr{index: 164 /* sp */},
+													&ruleIRefExpr{index: 103 /* dict_item */},
 												},
 											},
 										},
@@ -2740,14 +4171,20 @@ var g = &grammar{
 											expr: &litMatcher{val: ",", want: "\",\""},
 										},
 										&litMatcher{val: "}", want: "\"}\""},
-										&ruleIRefExpr{index: 123 /* sp */},
+										&ruleIRefExpr{index: 164 /* sp */},
 									},
 								},
 							},
-							&seqExpr{
-								exprs: []any{
-									&ruleIRefExpr{index: 72 /* item_get */},
-									&ruleIRefExpr{index: 74 /* attr_get */},
+							&codeExpr{
+								run: (*parser).call_onvalue_140,
+							},
+							&actionExpr{
+								run: (*parser).call_onvalue_141,
+								expr: &seqExpr{
+									exprs: []any{
+										&ruleIRefExpr{index: 95 /* item_get */},
+										&ruleIRefExpr{index: 97 /* attr_get */},
+									},
 								},
 							},
 						},
@@ -2759,10 +4196,29 @@ var g = &grammar{
 			name: "number",
 			expr: &actionExpr{
 				run: (*parser).call_onnumber_1,
-				expr: &oneOrMoreExpr{
-					expr: &charClassMatcher{
-						val:    "[0-9]",
-						ranges: []rune{'0', '9'},
+				expr: &choiceExpr{
+					alternatives: []any{
+						&seqExpr{
+							exprs: []any{
+								&litMatcher{val: "0", want: "\"0\""},
+								&charClassMatcher{
+									val:   "[xX]",
+									chars: []rune{'x', 'X'},
+								},
+								&ruleIRefExpr{index: 112 /* hexDigits */},
+							},
+						},
+						&seqExpr{
+							exprs: []any{
+								&litMatcher{val: "0", want: "\"0\""},
+								&charClassMatcher{
+									val:   "[bB]",
+									chars: []rune{'b', 'B'},
+								},
+								&ruleIRefExpr{index: 113 /* binDigits */},
+							},
+						},
+						&ruleIRefExpr{index: 111 /* digits */},
 					},
 				},
 			},
@@ -2771,19 +4227,114 @@ var g = &grammar{
 			name: "float",
 			expr: &actionExpr{
 				run: (*parser).call_onfloat_1,
-				expr: &seqExpr{
-					exprs: []any{
-						&zeroOrMoreExpr{
-							expr: &charClassMatcher{
-								val:    "[0-9]",
-								ranges: []rune{'0', '9'},
+				expr: &choiceExpr{
+					alternatives: []any{
+						&seqExpr{
+							exprs: []any{
+								&zeroOrOneExpr{
+									expr: &ruleIRefExpr{index: 111 /* digits */},
+								},
+								&litMatcher{val: ".", want: "\".\""},
+								&ruleIRefExpr{index: 111 /* digits */},
+								&zeroOrOneExpr{
+									expr: &ruleIRefExpr{index: 110 /* floatExp */},
+								},
 							},
 						},
-						&litMatcher{val: ".", want: "\".\""},
-						&oneOrMoreExpr{
-							expr: &charClassMatcher{
-								val:    "[0-9]",
-								ranges: []rune{'0', '9'},
+						&seqExpr{
+							exprs: []any{
+								&ruleIRefExpr{index: 111 /* digits */},
+								&ruleIRefExpr{index: 110 /* floatExp */},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "floatExp",
+			expr: &seqExpr{
+				exprs: []any{
+					&charClassMatcher{
+						val:   "[eE]",
+						chars: []rune{'e', 'E'},
+					},
+					&zeroOrOneExpr{
+						expr: &charClassMatcher{
+							val:   "[+-]",
+							chars: []rune{'+', '-'},
+						},
+					},
+					&ruleIRefExpr{index: 111 /* digits */},
+				},
+			},
+		},
+		{
+			name: "digits",
+			expr: &seqExpr{
+				exprs: []any{
+					&charClassMatcher{
+						val:    "[0-9]",
+						ranges: []rune{'0', '9'},
+					},
+					&zeroOrMoreExpr{
+						expr: &seqExpr{
+							exprs: []any{
+								&zeroOrOneExpr{
+									expr: &litMatcher{val: "_", want: "\"_\""},
+								},
+								&charClassMatcher{
+									val:    "[0-9]",
+									ranges: []rune{'0', '9'},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "hexDigits",
+			expr: &seqExpr{
+				exprs: []any{
+					&charClassMatcher{
+						val:    "[0-9a-fA-F]",
+						ranges: []rune{'0', '9', 'a', 'f', 'A', 'F'},
+					},
+					&zeroOrMoreExpr{
+						expr: &seqExpr{
+							exprs: []any{
+								&zeroOrOneExpr{
+									expr: &litMatcher{val: "_", want: "\"_\""},
+								},
+								&charClassMatcher{
+									val:    "[0-9a-fA-F]",
+									ranges: []rune{'0', '9', 'a', 'f', 'A', 'F'},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "binDigits",
+			expr: &seqExpr{
+				exprs: []any{
+					&charClassMatcher{
+						val:   "[01]",
+						chars: []rune{'0', '1'},
+					},
+					&zeroOrMoreExpr{
+						expr: &seqExpr{
+							exprs: []any{
+								&zeroOrOneExpr{
+									expr: &litMatcher{val: "_", want: "\"_\""},
+								},
+								&charClassMatcher{
+									val:   "[01]",
+									chars: []rune{'0', '1'},
+								},
 							},
 						},
 					},
@@ -2800,8 +4351,8 @@ var g = &grammar{
 					expr: &oneOrMoreExpr{
 						expr: &choiceExpr{
 							alternatives: []any{
-								&ruleIRefExpr{index: 92 /* strEscape */},
-								&ruleIRefExpr{index: 85 /* strPart1Normal */},
+								&ruleIRefExpr{index: 122 /* strEscape */},
+								&ruleIRefExpr{index: 115 /* strPart1Normal */},
 							},
 						},
 					},
@@ -2838,8 +4389,8 @@ var g = &grammar{
 					expr: &oneOrMoreExpr{
 						expr: &choiceExpr{
 							alternatives: []any{
-								&ruleIRefExpr{index: 92 /* strEscape */},
-								&ruleIRefExpr{index: 87 /* strPart2Normal */},
+								&ruleIRefExpr{index: 122 /* strEscape */},
+								&ruleIRefExpr{index: 117 /* strPart2Normal */},
 							},
 						},
 					},
@@ -2876,8 +4427,8 @@ var g = &grammar{
 					expr: &oneOrMoreExpr{
 						expr: &choiceExpr{
 							alternatives: []any{
-								&ruleIRefExpr{index: 92 /* strEscape */},
-								&ruleIRefExpr{index: 89 /* strPart3Normal */},
+								&ruleIRefExpr{index: 122 /* strEscape */},
+								&ruleIRefExpr{index: 119 /* strPart3Normal */},
 							},
 						},
 					},
@@ -2914,8 +4465,8 @@ var g = &grammar{
 					expr: &oneOrMoreExpr{
 						expr: &choiceExpr{
 							alternatives: []any{
-								&ruleIRefExpr{index: 92 /* strEscape */},
-								&ruleIRefExpr{index: 91 /* strPart4Normal */},
+								&ruleIRefExpr{index: 122 /* strEscape */},
+								&ruleIRefExpr{index: 121 /* strPart4Normal */},
 							},
 						},
 					},
@@ -2994,7 +4545,7 @@ var g = &grammar{
 			expr: &seqExpr{
 				exprs: []any{
 					&litMatcher{val: "{%", want: "\"{%\""},
-					&ruleIRefExpr{index: 123 /* sp */},
+					&ruleIRefExpr{index: 164 /* sp */},
 					&choiceExpr{
 						alternatives: []any{
 							&seqExpr{
@@ -3011,7 +4562,7 @@ var g = &grammar{
 							&andCodeExpr{run: (*parser).call_onfstringStmt_9},
 						},
 					},
-					&ruleIRefExpr{index: 123 /* sp */},
+					&ruleIRefExpr{index: 164 /* sp */},
 					&litMatcher{val: "%}", want: "\"%}\""},
 				},
 			},
@@ -3021,7 +4572,7 @@ var g = &grammar{
 			expr: &seqExpr{
 				exprs: []any{
 					&litMatcher{val: "{", want: "\"{\""},
-					&ruleIRefExpr{index: 123 /* sp */},
+					&ruleIRefExpr{index: 164 /* sp */},
 					&choiceExpr{
 						alternatives: []any{
 							&seqExpr{
@@ -3038,7 +4589,7 @@ var g = &grammar{
 							&andCodeExpr{run: (*parser).call_onfstringStmt2_9},
 						},
 					},
-					&ruleIRefExpr{index: 123 /* sp */},
+					&ruleIRefExpr{index: 164 /* sp */},
 					&litMatcher{val: "}", want: "\"}\""},
 				},
 			},
@@ -3096,7 +4647,7 @@ var g = &grammar{
 										expr: &seqExpr{
 											exprs: []any{
 												&zeroOrMoreExpr{
-													expr: &ruleIRefExpr{index: 84 /* strPart1 */},
+													expr: &ruleIRefExpr{index: 114 /* strPart1 */},
 												},
 												&litMatcher{val: "'", want: "\"'\""},
 											},
@@ -3115,7 +4666,7 @@ var g = &grammar{
 										expr: &seqExpr{
 											exprs: []any{
 												&zeroOrMoreExpr{
-													expr: &ruleIRefExpr{index: 86 /* strPart2 */},
+													expr: &ruleIRefExpr{index: 116 /* strPart2 */},
 												},
 												&litMatcher{val: "\"", want: "\"\\\"\""},
 											},
@@ -3136,9 +4687,9 @@ var g = &grammar{
 												&zeroOrMoreExpr{
 													expr: &choiceExpr{
 														alternatives: []any{
-															&ruleIRefExpr{index: 88 /* strPart3 */},
-															&ruleIRefExpr{index: 93 /* fstringStmt */},
-															&ruleIRefExpr{index: 94 /* fstringStmt2 */},
+															&ruleIRefExpr{index: 118 /* strPart3 */},
+															&ruleIRefExpr{index: 123 /* fstringStmt */},
+															&ruleIRefExpr{index: 124 /* fstringStmt2 */},
 														},
 													},
 												},
@@ -3161,9 +4712,9 @@ var g = &grammar{
 												&zeroOrMoreExpr{
 													expr: &choiceExpr{
 														alternatives: []any{
-															&ruleIRefExpr{index: 90 /* strPart4 */},
-															&ruleIRefExpr{index: 93 /* fstringStmt */},
-															&ruleIRefExpr{index: 94 /* fstringStmt2 */},
+															&ruleIRefExpr{index: 120 /* strPart4 */},
+															&ruleIRefExpr{index: 123 /* fstringStmt */},
+															&ruleIRefExpr{index: 124 /* fstringStmt2 */},
 														},
 													},
 												},
@@ -3175,7 +4726,7 @@ var g = &grammar{
 							},
 						},
 					},
-					&ruleIRefExpr{index: 123 /* sp */},
+					&ruleIRefExpr{index: 164 /* sp */},
 				},
 			},
 		},
@@ -3190,6 +4741,29 @@ var g = &grammar{
 					&litMatcher{val: "break", want: "\"break\""},
 					&litMatcher{val: "return", want: "\"return\""},
 					&litMatcher{val: "func", want: "\"func\""},
+					&litMatcher{val: "try", want: "\"try\""},
+					&litMatcher{val: "catch", want: "\"catch\""},
+					&litMatcher{val: "throw", want: "\"throw\""},
+					&litMatcher{val: "let", want: "\"let\""},
+					&litMatcher{val: "const", want: "\"const\""},
+					&litMatcher{val: "global", want: "\"global\""},
+					&litMatcher{val: "import", want: "\"import\""},
+					&seqExpr{
+						exprs: []any{
+							&andCodeExpr{run: (*parser).call_onkeywords_17},
+							&choiceExpr{
+								alternatives: []any{
+									&litMatcher{val: "如果", want: "\"如果\""},
+									&litMatcher{val: "否则", want: "\"否则\""},
+									&litMatcher{val: "当", want: "\"当\""},
+									&litMatcher{val: "函数", want: "\"函数\""},
+									&litMatcher{val: "返回", want: "\"返回\""},
+									&litMatcher{val: "跳出", want: "\"跳出\""},
+									&litMatcher{val: "继续", want: "\"继续\""},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
@@ -3199,27 +4773,125 @@ var g = &grammar{
 			expr: &notExpr{
 				expr: &seqExpr{
 					exprs: []any{
-						&ruleIRefExpr{index: 96 /* keywords */},
+						&ruleIRefExpr{index: 126 /* keywords */},
 						&notExpr{
-							expr: &ruleIRefExpr{index: 101 /* xidContinue */},
+							expr: &ruleIRefExpr{index: 138 /* xidContinue */},
 						},
 						&andCodeExpr{run: (*parser).call_onkeywords_test_6},
 					},
 				},
 			},
 		},
+		{
+			name: "kwIf",
+			expr: &choiceExpr{
+				alternatives: []any{
+					&litMatcher{val: "if", want: "\"if\""},
+					&seqExpr{
+						exprs: []any{
+							&andCodeExpr{run: (*parser).call_onkwIf_4},
+							&litMatcher{val: "如果", want: "\"如果\""},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "kwElse",
+			expr: &choiceExpr{
+				alternatives: []any{
+					&litMatcher{val: "else", want: "\"else\""},
+					&seqExpr{
+						exprs: []any{
+							&andCodeExpr{run: (*parser).call_onkwElse_4},
+							&litMatcher{val: "否则", want: "\"否则\""},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "kwWhile",
+			expr: &choiceExpr{
+				alternatives: []any{
+					&litMatcher{val: "while", want: "\"while\""},
+					&seqExpr{
+						exprs: []any{
+							&andCodeExpr{run: (*parser).call_onkwWhile_4},
+							&litMatcher{val: "当", want: "\"当\""},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "kwFunc",
+			expr: &choiceExpr{
+				alternatives: []any{
+					&litMatcher{val: "func", want: "\"func\""},
+					&seqExpr{
+						exprs: []any{
+							&andCodeExpr{run: (*parser).call_onkwFunc_4},
+							&litMatcher{val: "函数", want: "\"函数\""},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "kwReturn",
+			expr: &choiceExpr{
+				alternatives: []any{
+					&litMatcher{val: "return", want: "\"return\""},
+					&seqExpr{
+						exprs: []any{
+							&andCodeExpr{run: (*parser).call_onkwReturn_4},
+							&litMatcher{val: "返回", want: "\"返回\""},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "kwBreak",
+			expr: &choiceExpr{
+				alternatives: []any{
+					&litMatcher{val: "break", want: "\"break\""},
+					&seqExpr{
+						exprs: []any{
+							&andCodeExpr{run: (*parser).call_onkwBreak_4},
+							&litMatcher{val: "跳出", want: "\"跳出\""},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "kwContinue",
+			expr: &choiceExpr{
+				alternatives: []any{
+					&litMatcher{val: "continue", want: "\"continue\""},
+					&seqExpr{
+						exprs: []any{
+							&andCodeExpr{run: (*parser).call_onkwContinue_4},
+							&litMatcher{val: "继续", want: "\"继续\""},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "identifier",
 			expr: &actionExpr{
 				run: (*parser).call_onidentifier_1,
 				expr: &seqExpr{
 					exprs: []any{
-						&ruleIRefExpr{index: 97 /* keywords_test */},
-						&ruleIRefExpr{index: 100 /* xidStart */},
+						&ruleIRefExpr{index: 127 /* keywords_test */},
+						&ruleIRefExpr{index: 137 /* xidStart */},
 						&zeroOrMoreExpr{
 							expr: &choiceExpr{
 								alternatives: []any{
-									&ruleIRefExpr{index: 101 /* xidContinue */},
+									&ruleIRefExpr{index: 138 /* xidContinue */},
 									&litMatcher{val: ":", want: "\":\""},
 								},
 							},
@@ -3234,10 +4906,10 @@ var g = &grammar{
 				run: (*parser).call_onidentifierWithoutColon_1,
 				expr: &seqExpr{
 					exprs: []any{
-						&ruleIRefExpr{index: 97 /* keywords_test */},
-						&ruleIRefExpr{index: 100 /* xidStart */},
+						&ruleIRefExpr{index: 127 /* keywords_test */},
+						&ruleIRefExpr{index: 137 /* xidStart */},
 						&zeroOrMoreExpr{
-							expr: &ruleIRefExpr{index: 101 /* xidContinue */},
+							expr: &ruleIRefExpr{index: 138 /* xidContinue */},
 						},
 					},
 				},
@@ -3266,15 +4938,15 @@ var g = &grammar{
 					&andExpr{
 						expr: &seqExpr{
 							exprs: []any{
-								&ruleIRefExpr{index: 104 /* parenOpen */},
-								&ruleIRefExpr{index: 26 /* exprRoot */},
-								&ruleIRefExpr{index: 105 /* parenClose */},
+								&ruleIRefExpr{index: 141 /* parenOpen */},
+								&ruleIRefExpr{index: 49 /* exprRoot */},
+								&ruleIRefExpr{index: 142 /* parenClose */},
 							},
 						},
 					},
-					&ruleIRefExpr{index: 104 /* parenOpen */},
-					&ruleIRefExpr{index: 26 /* exprRoot */},
-					&ruleIRefExpr{index: 105 /* parenClose */},
+					&ruleIRefExpr{index: 141 /* parenOpen */},
+					&ruleIRefExpr{index: 49 /* exprRoot */},
+					&ruleIRefExpr{index: 142 /* parenClose */},
 				},
 			},
 		},
@@ -3282,9 +4954,19 @@ var g = &grammar{
 			name: "subX",
 			expr: &seqExpr{
 				exprs: []any{
-					&ruleIRefExpr{index: 102 /* sub */},
-					&ruleIRefExpr{index: 72 /* item_get */},
-					&ruleIRefExpr{index: 74 /* attr_get */},
+					&actionExpr{
+						run:  (*parser).call_onsubX_2,
+						expr: &ruleIRefExpr{index: 139 /* sub */},
+					},
+					&actionExpr{
+						run: (*parser).call_onsubX_4,
+						expr: &seqExpr{
+							exprs: []any{
+								&ruleIRefExpr{index: 95 /* item_get */},
+								&ruleIRefExpr{index: 97 /* attr_get */},
+							},
+						},
+					},
 				},
 			},
 		},
@@ -3293,7 +4975,7 @@ var g = &grammar{
 			expr: &seqExpr{
 				exprs: []any{
 					&litMatcher{val: "(", want: "\"(\""},
-					&ruleIRefExpr{index: 123 /* sp */},
+					&ruleIRefExpr{index: 164 /* sp */},
 				},
 			},
 		},
@@ -3302,7 +4984,7 @@ var g = &grammar{
 			expr: &seqExpr{
 				exprs: []any{
 					&litMatcher{val: ")", want: "\")\""},
-					&ruleIRefExpr{index: 123 /* sp */},
+					&ruleIRefExpr{index: 164 /* sp */},
 				},
 			},
 		},
@@ -3316,7 +4998,7 @@ var g = &grammar{
 							&litMatcher{val: "＋", want: "\"＋\""},
 						},
 					},
-					&ruleIRefExpr{index: 123 /* sp */},
+					&ruleIRefExpr{index: 164 /* sp */},
 				},
 			},
 		},
@@ -3330,7 +5012,7 @@ var g = &grammar{
 							&litMatcher{val: "－", want: "\"－\""},
 						},
 					},
-					&ruleIRefExpr{index: 123 /* sp */},
+					&ruleIRefExpr{index: 164 /* sp */},
 				},
 			},
 		},
@@ -3344,7 +5026,7 @@ var g = &grammar{
 							&litMatcher{val: "＊", want: "\"＊\""},
 						},
 					},
-					&ruleIRefExpr{index: 123 /* sp */},
+					&ruleIRefExpr{index: 164 /* sp */},
 				},
 			},
 		},
@@ -3358,7 +5040,7 @@ var g = &grammar{
 							&litMatcher{val: "／", want: "\"／\""},
 						},
 					},
-					&ruleIRefExpr{index: 123 /* sp */},
+					&ruleIRefExpr{index: 164 /* sp */},
 				},
 			},
 		},
@@ -3367,7 +5049,7 @@ var g = &grammar{
 			expr: &seqExpr{
 				exprs: []any{
 					&litMatcher{val: "%", want: "\"%\""},
-					&ruleIRefExpr{index: 123 /* sp */},
+					&ruleIRefExpr{index: 164 /* sp */},
 				},
 			},
 		},
@@ -3378,13 +5060,13 @@ var g = &grammar{
 					&seqExpr{
 						exprs: []any{
 							&litMatcher{val: "^", want: "\"^\""},
-							&ruleIRefExpr{index: 123 /* sp */},
+							&ruleIRefExpr{index: 164 /* sp */},
 						},
 					},
 					&seqExpr{
 						exprs: []any{
 							&litMatcher{val: "**", want: "\"**\""},
-							&ruleIRefExpr{index: 123 /* sp */},
+							&ruleIRefExpr{index: 164 /* sp */},
 						},
 					},
 				},
@@ -3395,7 +5077,7 @@ var g = &grammar{
 			expr: &seqExpr{
 				exprs: []any{
 					&litMatcher{val: "??", want: "\"??\""},
-					&ruleIRefExpr{index: 123 /* sp */},
+					&ruleIRefExpr{index: 164 /* sp */},
 				},
 			},
 		},
@@ -3404,7 +5086,7 @@ var g = &grammar{
 			expr: &seqExpr{
 				exprs: []any{
 					&litMatcher{val: "|", want: "\"|\""},
-					&ruleIRefExpr{index: 123 /* sp */},
+					&ruleIRefExpr{index: 164 /* sp */},
 				},
 			},
 		},
@@ -3413,7 +5095,7 @@ var g = &grammar{
 			expr: &seqExpr{
 				exprs: []any{
 					&litMatcher{val: "&", want: "\"&\""},
-					&ruleIRefExpr{index: 123 /* sp */},
+					&ruleIRefExpr{index: 164 /* sp */},
 				},
 			},
 		},
@@ -3422,7 +5104,7 @@ var g = &grammar{
 			expr: &seqExpr{
 				exprs: []any{
 					&litMatcher{val: "||", want: "\"||\""},
-					&ruleIRefExpr{index: 123 /* sp */},
+					&ruleIRefExpr{index: 164 /* sp */},
 				},
 			},
 		},
@@ -3431,7 +5113,19 @@ var g = &grammar{
 			expr: &seqExpr{
 				exprs: []any{
 					&litMatcher{val: "&&", want: "\"&&\""},
-					&ruleIRefExpr{index: 123 /* sp */},
+					&ruleIRefExpr{index: 164 /* sp */},
+				},
+			},
+		},
+		{
+			name: "logicNot",
+			expr: &seqExpr{
+				exprs: []any{
+					&litMatcher{val: "!", want: "\"!\""},
+					&notExpr{
+						expr: &litMatcher{val: "=", want: "\"=\""},
+					},
+					&ruleIRefExpr{index: 164 /* sp */},
 				},
 			},
 		},
@@ -3440,7 +5134,7 @@ var g = &grammar{
 			expr: &seqExpr{
 				exprs: []any{
 					&litMatcher{val: "<", want: "\"<\""},
-					&ruleIRefExpr{index: 123 /* sp */},
+					&ruleIRefExpr{index: 164 /* sp */},
 				},
 			},
 		},
@@ -3449,7 +5143,7 @@ var g = &grammar{
 			expr: &seqExpr{
 				exprs: []any{
 					&litMatcher{val: ">", want: "\">\""},
-					&ruleIRefExpr{index: 123 /* sp */},
+					&ruleIRefExpr{index: 164 /* sp */},
 				},
 			},
 		},
@@ -3457,35 +5151,74 @@ var g = &grammar{
 			name: "le",
 			expr: &seqExpr{
 				exprs: []any{
-					&litMatcher{val: "<=", want: "\"<=\""},
-					&ruleIRefExpr{index: 123 /* sp */},
+					&litMatcher{val: "<=", want: "\"<=\""},
+					&ruleIRefExpr{index: 164 /* sp */},
+				},
+			},
+		},
+		{
+			name: "ge",
+			expr: &seqExpr{
+				exprs: []any{
+					&litMatcher{val: ">=", want: "\">=\""},
+					&ruleIRefExpr{index: 164 /* sp */},
+				},
+			},
+		},
+		{
+			name: "eq",
+			expr: &seqExpr{
+				exprs: []any{
+					&litMatcher{val: "==", want: "\"==\""},
+					&ruleIRefExpr{index: 164 /* sp */},
+				},
+			},
+		},
+		{
+			name: "ne",
+			expr: &seqExpr{
+				exprs: []any{
+					&litMatcher{val: "!=", want: "\"!=\""},
+					&ruleIRefExpr{index: 164 /* sp */},
 				},
 			},
 		},
 		{
-			name: "ge",
+			name: "opIn",
 			expr: &seqExpr{
 				exprs: []any{
-					&litMatcher{val: ">=", want: "\">=\""},
-					&ruleIRefExpr{index: 123 /* sp */},
+					&litMatcher{val: "in", want: "\"in\""},
+					&notExpr{
+						expr: &ruleIRefExpr{index: 138 /* xidContinue */},
+					},
+					&ruleIRefExpr{index: 164 /* sp */},
 				},
 			},
 		},
 		{
-			name: "eq",
+			name: "opNotIn",
 			expr: &seqExpr{
 				exprs: []any{
-					&litMatcher{val: "==", want: "\"==\""},
-					&ruleIRefExpr{index: 123 /* sp */},
+					&litMatcher{val: "not", want: "\"not\""},
+					&ruleIRefExpr{index: 166 /* sp1x */},
+					&litMatcher{val: "in", want: "\"in\""},
+					&notExpr{
+						expr: &ruleIRefExpr{index: 138 /* xidContinue */},
+					},
+					&ruleIRefExpr{index: 164 /* sp */},
 				},
 			},
 		},
 		{
-			name: "ne",
-			expr: &seqExpr{
-				exprs: []any{
-					&litMatcher{val: "!=", want: "\"!=\""},
-					&ruleIRefExpr{index: 123 /* sp */},
+			name: "assignOp",
+			expr: &choiceExpr{
+				alternatives: []any{
+					&litMatcher{val: "+=", want: "\"+=\""},
+					&litMatcher{val: "-=", want: "\"-=\""},
+					&litMatcher{val: "*=", want: "\"*=\""},
+					&litMatcher{val: "/=", want: "\"/=\""},
+					&litMatcher{val: "%=", want: "\"%=\""},
+					&litMatcher{val: "??=", want: "\"??=\""},
 				},
 			},
 		},
@@ -3510,7 +5243,7 @@ var g = &grammar{
 								val:   "[ \\n\\t\\r]",
 								chars: []rune{' ', '\n', '\t', '\r'},
 							},
-							&ruleIRefExpr{index: 123 /* sp */},
+							&ruleIRefExpr{index: 164 /* sp */},
 						},
 					},
 					&notExpr{
@@ -3523,8 +5256,8 @@ var g = &grammar{
 			name: "sp1x",
 			expr: &seqExpr{
 				exprs: []any{
-					&ruleIRefExpr{index: 124 /* sp1 */},
-					&ruleIRefExpr{index: 123 /* sp */},
+					&ruleIRefExpr{index: 165 /* sp1 */},
+					&ruleIRefExpr{index: 164 /* sp */},
 				},
 			},
 		},
@@ -3541,9 +5274,9 @@ var g = &grammar{
 			name: "comment",
 			expr: &seqExpr{
 				exprs: []any{
-					&ruleIRefExpr{index: 126 /* spNoCR */},
+					&ruleIRefExpr{index: 167 /* spNoCR */},
 					&litMatcher{val: "//", want: "\"//\""},
-					&ruleIRefExpr{index: 128 /* commentLineRest */},
+					&ruleIRefExpr{index: 169 /* commentLineRest */},
 				},
 			},
 		},
@@ -3606,8 +5339,8 @@ var g = &grammar{
 			name: "st_expr",
 			expr: &choiceExpr{
 				alternatives: []any{
-					&ruleIRefExpr{index: 135 /* st_modify_multi_1 */},
-					&ruleIRefExpr{index: 132 /* st_assign_multi */},
+					&ruleIRefExpr{index: 176 /* st_modify_multi_1 */},
+					&ruleIRefExpr{index: 173 /* st_assign_multi */},
 				},
 			},
 		},
@@ -3620,7 +5353,7 @@ var g = &grammar{
 							&andExpr{
 								expr: &litMatcher{val: "(", want: "\"(\""},
 							},
-							&ruleIRefExpr{index: 26 /* exprRoot */},
+							&ruleIRefExpr{index: 49 /* exprRoot */},
 						},
 					},
 					&seqExpr{
@@ -3628,12 +5361,12 @@ var g = &grammar{
 							&actionExpr{
 								run: (*parser).call_onest_7,
 								expr: &andExpr{
-									expr: &ruleIRefExpr{index: 26 /* exprRoot */},
+									expr: &ruleIRefExpr{index: 49 /* exprRoot */},
 								},
 							},
 							&actionExpr{
 								run:  (*parser).call_onest_10,
-								expr: &ruleIRefExpr{index: 26 /* exprRoot */},
+								expr: &ruleIRefExpr{index: 49 /* exprRoot */},
 							},
 						},
 					},
@@ -3645,12 +5378,12 @@ var g = &grammar{
 			expr: &oneOrMoreExpr{
 				expr: &seqExpr{
 					exprs: []any{
-						&ruleIRefExpr{index: 134 /* st_assign */},
-						&ruleIRefExpr{index: 123 /* sp */},
+						&ruleIRefExpr{index: 175 /* st_assign */},
+						&ruleIRefExpr{index: 164 /* sp */},
 						&zeroOrOneExpr{
 							expr: &litMatcher{val: ",", want: "\",\""},
 						},
-						&ruleIRefExpr{index: 123 /* sp */},
+						&ruleIRefExpr{index: 164 /* sp */},
 					},
 				},
 			},
@@ -3660,12 +5393,12 @@ var g = &grammar{
 			expr: &seqExpr{
 				exprs: []any{
 					&litMatcher{val: "*", want: "\"*\""},
-					&ruleIRefExpr{index: 123 /* sp */},
+					&ruleIRefExpr{index: 164 /* sp */},
 					&choiceExpr{
 						alternatives: []any{
-							&ruleIRefExpr{index: 83 /* float */},
-							&ruleIRefExpr{index: 82 /* number */},
-							&ruleIRefExpr{index: 102 /* sub */},
+							&ruleIRefExpr{index: 109 /* float */},
+							&ruleIRefExpr{index: 108 /* number */},
+							&ruleIRefExpr{index: 139 /* sub */},
 						},
 					},
 				},
@@ -3683,29 +5416,29 @@ var g = &grammar{
 								&andExpr{
 									expr: &seqExpr{
 										exprs: []any{
-											&ruleIRefExpr{index: 142 /* st_name2 */},
-											&ruleIRefExpr{index: 123 /* sp */},
+											&ruleIRefExpr{index: 183 /* st_name2 */},
+											&ruleIRefExpr{index: 164 /* sp */},
 											&choiceExpr{
 												alternatives: []any{
 													&litMatcher{val: ":", want: "\":\""},
 													&litMatcher{val: "=", want: "\"=\""},
 												},
 											},
-											&ruleIRefExpr{index: 123 /* sp */},
-											&ruleIRefExpr{index: 131 /* est */},
+											&ruleIRefExpr{index: 164 /* sp */},
+											&ruleIRefExpr{index: 172 /* est */},
 										},
 									},
 								},
-								&ruleIRefExpr{index: 142 /* st_name2 */},
-								&ruleIRefExpr{index: 123 /* sp */},
+								&ruleIRefExpr{index: 183 /* st_name2 */},
+								&ruleIRefExpr{index: 164 /* sp */},
 								&choiceExpr{
 									alternatives: []any{
 										&litMatcher{val: ":", want: "\":\""},
 										&litMatcher{val: "=", want: "\"=\""},
 									},
 								},
-								&ruleIRefExpr{index: 123 /* sp */},
-								&ruleIRefExpr{index: 131 /* est */},
+								&ruleIRefExpr{index: 164 /* sp */},
+								&ruleIRefExpr{index: 172 /* est */},
 							},
 						},
 					},
@@ -3716,13 +5449,13 @@ var g = &grammar{
 								&andExpr{
 									expr: &seqExpr{
 										exprs: []any{
-											&ruleIRefExpr{index: 140 /* st_name1 */},
-											&ruleIRefExpr{index: 131 /* est */},
+											&ruleIRefExpr{index: 181 /* st_name1 */},
+											&ruleIRefExpr{index: 172 /* est */},
 										},
 									},
 								},
-								&ruleIRefExpr{index: 140 /* st_name1 */},
-								&ruleIRefExpr{index: 131 /* est */},
+								&ruleIRefExpr{index: 181 /* st_name1 */},
+								&ruleIRefExpr{index: 172 /* est */},
 							},
 						},
 					},
@@ -3733,33 +5466,33 @@ var g = &grammar{
 								&andExpr{
 									expr: &seqExpr{
 										exprs: []any{
-											&ruleIRefExpr{index: 143 /* st_name2r */},
-											&ruleIRefExpr{index: 123 /* sp */},
-											&ruleIRefExpr{index: 133 /* st_star */},
-											&ruleIRefExpr{index: 123 /* sp */},
+											&ruleIRefExpr{index: 184 /* st_name2r */},
+											&ruleIRefExpr{index: 164 /* sp */},
+											&ruleIRefExpr{index: 174 /* st_star */},
+											&ruleIRefExpr{index: 164 /* sp */},
 											&choiceExpr{
 												alternatives: []any{
 													&litMatcher{val: ":", want: "\":\""},
 													&litMatcher{val: "=", want: "\"=\""},
 												},
 											},
-											&ruleIRefExpr{index: 123 /* sp */},
-											&ruleIRefExpr{index: 131 /* est */},
+											&ruleIRefExpr{index: 164 /* sp */},
+											&ruleIRefExpr{index: 172 /* est */},
 										},
 									},
 								},
-								&ruleIRefExpr{index: 143 /* st_name2r */},
-								&ruleIRefExpr{index: 123 /* sp */},
-								&ruleIRefExpr{index: 133 /* st_star */},
-								&ruleIRefExpr{index: 123 /* sp */},
+								&ruleIRefExpr{index: 184 /* st_name2r */},
+								&ruleIRefExpr{index: 164 /* sp */},
+								&ruleIRefExpr{index: 174 /* st_star */},
+								&ruleIRefExpr{index: 164 /* sp */},
 								&choiceExpr{
 									alternatives: []any{
 										&litMatcher{val: ":", want: "\":\""},
 										&litMatcher{val: "=", want: "\"=\""},
 									},
 								},
-								&ruleIRefExpr{index: 123 /* sp */},
-								&ruleIRefExpr{index: 131 /* est */},
+								&ruleIRefExpr{index: 164 /* sp */},
+								&ruleIRefExpr{index: 172 /* est */},
 							},
 						},
 					},
@@ -3770,33 +5503,33 @@ var g = &grammar{
 								&andExpr{
 									expr: &seqExpr{
 										exprs: []any{
-											&ruleIRefExpr{index: 143 /* st_name2r */},
-											&ruleIRefExpr{index: 123 /* sp */},
+											&ruleIRefExpr{index: 184 /* st_name2r */},
+											&ruleIRefExpr{index: 164 /* sp */},
 											&litMatcher{val: "*", want: "\"*\""},
-											&ruleIRefExpr{index: 123 /* sp */},
+											&ruleIRefExpr{index: 164 /* sp */},
 											&choiceExpr{
 												alternatives: []any{
 													&litMatcher{val: ":", want: "\":\""},
 													&litMatcher{val: "=", want: "\"=\""},
 												},
 											},
-											&ruleIRefExpr{index: 123 /* sp */},
-											&ruleIRefExpr{index: 131 /* est */},
+											&ruleIRefExpr{index: 164 /* sp */},
+											&ruleIRefExpr{index: 172 /* est */},
 										},
 									},
 								},
-								&ruleIRefExpr{index: 143 /* st_name2r */},
-								&ruleIRefExpr{index: 123 /* sp */},
+								&ruleIRefExpr{index: 184 /* st_name2r */},
+								&ruleIRefExpr{index: 164 /* sp */},
 								&litMatcher{val: "*", want: "\"*\""},
-								&ruleIRefExpr{index: 123 /* sp */},
+								&ruleIRefExpr{index: 164 /* sp */},
 								&choiceExpr{
 									alternatives: []any{
 										&litMatcher{val: ":", want: "\":\""},
 										&litMatcher{val: "=", want: "\"=\""},
 									},
 								},
-								&ruleIRefExpr{index: 123 /* sp */},
-								&ruleIRefExpr{index: 131 /* est */},
+								&ruleIRefExpr{index: 164 /* sp */},
+								&ruleIRefExpr{index: 172 /* est */},
 							},
 						},
 					},
@@ -3807,29 +5540,29 @@ var g = &grammar{
 								&andExpr{
 									expr: &seqExpr{
 										exprs: []any{
-											&ruleIRefExpr{index: 143 /* st_name2r */},
-											&ruleIRefExpr{index: 123 /* sp */},
+											&ruleIRefExpr{index: 184 /* st_name2r */},
+											&ruleIRefExpr{index: 164 /* sp */},
 											&choiceExpr{
 												alternatives: []any{
 													&litMatcher{val: ":", want: "\":\""},
 													&litMatcher{val: "=", want: "\"=\""},
 												},
 											},
-											&ruleIRefExpr{index: 123 /* sp */},
-											&ruleIRefExpr{index: 131 /* est */},
+											&ruleIRefExpr{index: 164 /* sp */},
+											&ruleIRefExpr{index: 172 /* est */},
 										},
 									},
 								},
-								&ruleIRefExpr{index: 143 /* st_name2r */},
-								&ruleIRefExpr{index: 123 /* sp */},
+								&ruleIRefExpr{index: 184 /* st_name2r */},
+								&ruleIRefExpr{index: 164 /* sp */},
 								&choiceExpr{
 									alternatives: []any{
 										&litMatcher{val: ":", want: "\":\""},
 										&litMatcher{val: "=", want: "\"=\""},
 									},
 								},
-								&ruleIRefExpr{index: 123 /* sp */},
-								&ruleIRefExpr{index: 131 /* est */},
+								&ruleIRefExpr{index: 164 /* sp */},
+								&ruleIRefExpr{index: 172 /* est */},
 							},
 						},
 					},
@@ -3840,13 +5573,13 @@ var g = &grammar{
 								&andExpr{
 									expr: &seqExpr{
 										exprs: []any{
-											&ruleIRefExpr{index: 141 /* st_name1r */},
-											&ruleIRefExpr{index: 131 /* est */},
+											&ruleIRefExpr{index: 182 /* st_name1r */},
+											&ruleIRefExpr{index: 172 /* est */},
 										},
 									},
 								},
-								&ruleIRefExpr{index: 141 /* st_name1r */},
-								&ruleIRefExpr{index: 131 /* est */},
+								&ruleIRefExpr{index: 182 /* st_name1r */},
+								&ruleIRefExpr{index: 172 /* est */},
 							},
 						},
 					},
@@ -3860,28 +5593,28 @@ var g = &grammar{
 											expr: &seqExpr{
 												exprs: []any{
 													&litMatcher{val: "&", want: "\"&\""},
-													&ruleIRefExpr{index: 142 /* st_name2 */},
-													&ruleIRefExpr{index: 123 /* sp */},
+													&ruleIRefExpr{index: 183 /* st_name2 */},
+													&ruleIRefExpr{index: 164 /* sp */},
 													&choiceExpr{
 														alternatives: []any{
 															&litMatcher{val: ":", want: "\":\""},
 															&litMatcher{val: "=", want: "\"=\""},
 														},
 													},
-													&ruleIRefExpr{index: 131 /* est */},
+													&ruleIRefExpr{index: 172 /* est */},
 												},
 											},
 										},
 										&litMatcher{val: "&", want: "\"&\""},
-										&ruleIRefExpr{index: 142 /* st_name2 */},
-										&ruleIRefExpr{index: 123 /* sp */},
+										&ruleIRefExpr{index: 183 /* st_name2 */},
+										&ruleIRefExpr{index: 164 /* sp */},
 										&choiceExpr{
 											alternatives: []any{
 												&litMatcher{val: ":", want: "\":\""},
 												&litMatcher{val: "=", want: "\"=\""},
 											},
 										},
-										&ruleIRefExpr{index: 123 /* sp */},
+										&ruleIRefExpr{index: 164 /* sp */},
 									},
 								},
 							},
@@ -3889,7 +5622,7 @@ var g = &grammar{
 								run: (*parser).call_onst_assign_117,
 								expr: &labeledExpr{
 									label:       "text",
-									expr:        &ruleIRefExpr{index: 131 /* est */},
+									expr:        &ruleIRefExpr{index: 172 /* est */},
 									textCapture: true,
 								},
 							},
@@ -3905,28 +5638,28 @@ var g = &grammar{
 											expr: &seqExpr{
 												exprs: []any{
 													&litMatcher{val: "&", want: "\"&\""},
-													&ruleIRefExpr{index: 143 /* st_name2r */},
-													&ruleIRefExpr{index: 123 /* sp */},
+													&ruleIRefExpr{index: 184 /* st_name2r */},
+													&ruleIRefExpr{index: 164 /* sp */},
 													&choiceExpr{
 														alternatives: []any{
 															&litMatcher{val: ":", want: "\":\""},
 															&litMatcher{val: "=", want: "\"=\""},
 														},
 													},
-													&ruleIRefExpr{index: 131 /* est */},
+													&ruleIRefExpr{index: 172 /* est */},
 												},
 											},
 										},
 										&litMatcher{val: "&", want: "\"&\""},
-										&ruleIRefExpr{index: 143 /* st_name2r */},
-										&ruleIRefExpr{index: 123 /* sp */},
+										&ruleIRefExpr{index: 184 /* st_name2r */},
+										&ruleIRefExpr{index: 164 /* sp */},
 										&choiceExpr{
 											alternatives: []any{
 												&litMatcher{val: ":", want: "\":\""},
 												&litMatcher{val: "=", want: "\"=\""},
 											},
 										},
-										&ruleIRefExpr{index: 123 /* sp */},
+										&ruleIRefExpr{index: 164 /* sp */},
 									},
 								},
 							},
@@ -3934,7 +5667,7 @@ var g = &grammar{
 								run: (*parser).call_onst_assign_139,
 								expr: &labeledExpr{
 									label:       "text",
-									expr:        &ruleIRefExpr{index: 131 /* est */},
+									expr:        &ruleIRefExpr{index: 172 /* est */},
 									textCapture: true,
 								},
 							},
@@ -3949,15 +5682,15 @@ var g = &grammar{
 				exprs: []any{
 					&seqExpr{
 						exprs: []any{
-							&ruleIRefExpr{index: 136 /* st_modify_lead */},
-							&ruleIRefExpr{index: 123 /* sp */},
+							&ruleIRefExpr{index: 177 /* st_modify_lead */},
+							&ruleIRefExpr{index: 164 /* sp */},
 							&zeroOrOneExpr{
 								expr: &litMatcher{val: ",", want: "\",\""},
 							},
-							&ruleIRefExpr{index: 123 /* sp */},
+							&ruleIRefExpr{index: 164 /* sp */},
 						},
 					},
-					&ruleIRefExpr{index: 137 /* st_modify_multi_rest */},
+					&ruleIRefExpr{index: 178 /* st_modify_multi_rest */},
 				},
 			},
 		},
@@ -3970,13 +5703,13 @@ var g = &grammar{
 							&andExpr{
 								expr: &seqExpr{
 									exprs: []any{
-										&ruleIRefExpr{index: 142 /* st_name2 */},
-										&ruleIRefExpr{index: 138 /* st_modify_rest1 */},
+										&ruleIRefExpr{index: 183 /* st_name2 */},
+										&ruleIRefExpr{index: 179 /* st_modify_rest1 */},
 									},
 								},
 							},
-							&ruleIRefExpr{index: 142 /* st_name2 */},
-							&ruleIRefExpr{index: 138 /* st_modify_rest1 */},
+							&ruleIRefExpr{index: 183 /* st_name2 */},
+							&ruleIRefExpr{index: 179 /* st_modify_rest1 */},
 						},
 					},
 					&seqExpr{
@@ -3984,13 +5717,13 @@ var g = &grammar{
 							&andExpr{
 								expr: &seqExpr{
 									exprs: []any{
-										&ruleIRefExpr{index: 143 /* st_name2r */},
-										&ruleIRefExpr{index: 138 /* st_modify_rest1 */},
+										&ruleIRefExpr{index: 184 /* st_name2r */},
+										&ruleIRefExpr{index: 179 /* st_modify_rest1 */},
 									},
 								},
 							},
-							&ruleIRefExpr{index: 143 /* st_name2r */},
-							&ruleIRefExpr{index: 138 /* st_modify_rest1 */},
+							&ruleIRefExpr{index: 184 /* st_name2r */},
+							&ruleIRefExpr{index: 179 /* st_modify_rest1 */},
 						},
 					},
 					&seqExpr{
@@ -3998,13 +5731,13 @@ var g = &grammar{
 							&andExpr{
 								expr: &seqExpr{
 									exprs: []any{
-										&ruleIRefExpr{index: 140 /* st_name1 */},
-										&ruleIRefExpr{index: 139 /* st_modify_rest */},
+										&ruleIRefExpr{index: 181 /* st_name1 */},
+										&ruleIRefExpr{index: 180 /* st_modify_rest */},
 									},
 								},
 							},
-							&ruleIRefExpr{index: 140 /* st_name1 */},
-							&ruleIRefExpr{index: 139 /* st_modify_rest */},
+							&ruleIRefExpr{index: 181 /* st_name1 */},
+							&ruleIRefExpr{index: 180 /* st_modify_rest */},
 						},
 					},
 					&seqExpr{
@@ -4012,13 +5745,13 @@ var g = &grammar{
 							&andExpr{
 								expr: &seqExpr{
 									exprs: []any{
-										&ruleIRefExpr{index: 141 /* st_name1r */},
-										&ruleIRefExpr{index: 139 /* st_modify_rest */},
+										&ruleIRefExpr{index: 182 /* st_name1r */},
+										&ruleIRefExpr{index: 180 /* st_modify_rest */},
 									},
 								},
 							},
-							&ruleIRefExpr{index: 141 /* st_name1r */},
-							&ruleIRefExpr{index: 139 /* st_modify_rest */},
+							&ruleIRefExpr{index: 182 /* st_name1r */},
+							&ruleIRefExpr{index: 180 /* st_modify_rest */},
 						},
 					},
 				},
@@ -4029,12 +5762,12 @@ var g = &grammar{
 			expr: &zeroOrMoreExpr{
 				expr: &seqExpr{
 					exprs: []any{
-						&ruleIRefExpr{index: 136 /* st_modify_lead */},
-						&ruleIRefExpr{index: 123 /* sp */},
+						&ruleIRefExpr{index: 177 /* st_modify_lead */},
+						&ruleIRefExpr{index: 164 /* sp */},
 						&zeroOrOneExpr{
 							expr: &litMatcher{val: ",", want: "\",\""},
 						},
-						&ruleIRefExpr{index: 123 /* sp */},
+						&ruleIRefExpr{index: 164 /* sp */},
 					},
 				},
 			},
@@ -4044,7 +5777,7 @@ var g = &grammar{
 			varExists: true,
 			expr: &seqExpr{
 				exprs: []any{
-					&ruleIRefExpr{index: 123 /* sp */},
+					&ruleIRefExpr{index: 164 /* sp */},
 					&choiceExpr{
 						alternatives: []any{
 							&actionExpr{
@@ -4052,10 +5785,10 @@ var g = &grammar{
 								expr: &seqExpr{
 									exprs: []any{
 										&litMatcher{val: "+=", want: "\"+=\""},
-										&ruleIRefExpr{index: 123 /* sp */},
+										&ruleIRefExpr{index: 164 /* sp */},
 										&labeledExpr{
 											label:       "text",
-											expr:        &ruleIRefExpr{index: 26 /* exprRoot */},
+											expr:        &ruleIRefExpr{index: 49 /* exprRoot */},
 											textCapture: true,
 										},
 									},
@@ -4066,10 +5799,10 @@ var g = &grammar{
 								expr: &seqExpr{
 									exprs: []any{
 										&litMatcher{val: "-=", want: "\"-=\""},
-										&ruleIRefExpr{index: 123 /* sp */},
+										&ruleIRefExpr{index: 164 /* sp */},
 										&labeledExpr{
 											label:       "text",
-											expr:        &ruleIRefExpr{index: 26 /* exprRoot */},
+											expr:        &ruleIRefExpr{index: 49 /* exprRoot */},
 											textCapture: true,
 										},
 									},
@@ -4085,7 +5818,7 @@ var g = &grammar{
 			varExists: true,
 			expr: &seqExpr{
 				exprs: []any{
-					&ruleIRefExpr{index: 123 /* sp */},
+					&ruleIRefExpr{index: 164 /* sp */},
 					&choiceExpr{
 						alternatives: []any{
 							&actionExpr{
@@ -4096,10 +5829,10 @@ var g = &grammar{
 										&zeroOrOneExpr{
 											expr: &litMatcher{val: "=", want: "\"=\""},
 										},
-										&ruleIRefExpr{index: 123 /* sp */},
+										&ruleIRefExpr{index: 164 /* sp */},
 										&labeledExpr{
 											label:       "text",
-											expr:        &ruleIRefExpr{index: 26 /* exprRoot */},
+											expr:        &ruleIRefExpr{index: 49 /* exprRoot */},
 											textCapture: true,
 										},
 									},
@@ -4110,10 +5843,10 @@ var g = &grammar{
 								expr: &seqExpr{
 									exprs: []any{
 										&litMatcher{val: "-=", want: "\"-=\""},
-										&ruleIRefExpr{index: 123 /* sp */},
+										&ruleIRefExpr{index: 164 /* sp */},
 										&labeledExpr{
 											label:       "text",
-											expr:        &ruleIRefExpr{index: 26 /* exprRoot */},
+											expr:        &ruleIRefExpr{index: 49 /* exprRoot */},
 											textCapture: true,
 										},
 									},
@@ -4126,10 +5859,10 @@ var g = &grammar{
 										&andExpr{
 											expr: &litMatcher{val: "-", want: "\"-\""},
 										},
-										&ruleIRefExpr{index: 123 /* sp */},
+										&ruleIRefExpr{index: 164 /* sp */},
 										&labeledExpr{
 											label:       "text",
-											expr:        &ruleIRefExpr{index: 26 /* exprRoot */},
+											expr:        &ruleIRefExpr{index: 49 /* exprRoot */},
 											textCapture: true,
 										},
 									},
@@ -4150,11 +5883,11 @@ var g = &grammar{
 					expr: &seqExpr{
 						exprs: []any{
 							&oneOrMoreExpr{
-								expr: &ruleIRefExpr{index: 144 /* id_ch */},
+								expr: &ruleIRefExpr{index: 185 /* id_ch */},
 							},
 							&litMatcher{val: ":", want: "\":\""},
 							&oneOrMoreExpr{
-								expr: &ruleIRefExpr{index: 144 /* id_ch */},
+								expr: &ruleIRefExpr{index: 185 /* id_ch */},
 							},
 						},
 					},
@@ -4172,7 +5905,7 @@ var g = &grammar{
 						expr: &labeledExpr{
 							label: "text",
 							expr: &oneOrMoreExpr{
-								expr: &ruleIRefExpr{index: 144 /* id_ch */},
+								expr: &ruleIRefExpr{index: 185 /* id_ch */},
 							},
 							textCapture: true,
 						},
@@ -4187,7 +5920,7 @@ var g = &grammar{
 									expr: &oneOrMoreExpr{
 										expr: &choiceExpr{
 											alternatives: []any{
-												&ruleIRefExpr{index: 144 /* id_ch */},
+												&ruleIRefExpr{index: 185 /* id_ch */},
 												&charClassMatcher{
 													val:    "[0-9]",
 													ranges: []rune{'0', '9'},
@@ -4208,7 +5941,7 @@ var g = &grammar{
 		},
 		{
 			name: "st_name2",
-			expr: &ruleIRefExpr{index: 140 /* st_name1 */},
+			expr: &ruleIRefExpr{index: 181 /* st_name1 */},
 		},
 		{
 			name:      "st_name2r",
@@ -4220,7 +5953,7 @@ var g = &grammar{
 						expr: &labeledExpr{
 							label: "text",
 							expr: &oneOrMoreExpr{
-								expr: &ruleIRefExpr{index: 144 /* id_ch */},
+								expr: &ruleIRefExpr{index: 185 /* id_ch */},
 							},
 							textCapture: true,
 						},
@@ -4235,7 +5968,7 @@ var g = &grammar{
 									expr: &oneOrMoreExpr{
 										expr: &choiceExpr{
 											alternatives: []any{
-												&ruleIRefExpr{index: 144 /* id_ch */},
+												&ruleIRefExpr{index: 185 /* id_ch */},
 												&charClassMatcher{
 													val:    "[0-9]",
 													ranges: []rune{'0', '9'},
@@ -4256,7 +5989,7 @@ var g = &grammar{
 		},
 		{
 			name: "id_ch",
-			expr: &ruleIRefExpr{index: 100 /* xidStart */},
+			expr: &ruleIRefExpr{index: 137 /* xidStart */},
 		},
 	},
 }
@@ -4295,7 +6028,7 @@ func (p *parser) call_onflagsSwitch_1() any {
 func (p *parser) call_onstmtBreak_1() any {
 	return (func(c *current) any {
 		if c.data.loopLayer == 0 {
-			p.addErr(errors.New("`break` is not allowed outside loop."))
+			p.addErr(fmt.Errorf("`%s` is not allowed outside loop.", strings.TrimSpace(toStr(c.text))))
 			return false
 		} else {
 			c.data.BreakPush()
@@ -4307,7 +6040,7 @@ func (p *parser) call_onstmtBreak_1() any {
 func (p *parser) call_onstmtContinue_1() any {
 	return (func(c *current) any {
 		if c.data.loopLayer == 0 {
-			p.addErr(errors.New("`continue` is not allowed outside loop."))
+			p.addErr(fmt.Errorf("`%s` is not allowed outside loop.", strings.TrimSpace(toStr(c.text))))
 			return false
 		} else {
 			c.data.ContinuePush()
@@ -4316,6 +6049,134 @@ func (p *parser) call_onstmtContinue_1() any {
 	})(&p.cur)
 }
 
+func (p *parser) call_onstmtLet_2() any {
+	stack := p.vstack[len(p.vstack)-1]
+	return (func(c *current, id any) any {
+		c.data.AddStoreLet(id.(string), false)
+		return nil
+	})(&p.cur, stack["id"])
+}
+
+func (p *parser) call_onstmtLet_14() any {
+	stack := p.vstack[len(p.vstack)-1]
+	return (func(c *current, id any) any {
+		c.data.PushNull()
+		c.data.AddStoreLet(id.(string), false)
+		return nil
+	})(&p.cur, stack["id"])
+}
+
+func (p *parser) call_onstmtLet_21() any {
+	stack := p.vstack[len(p.vstack)-1]
+	return (func(c *current, id any) any {
+		c.data.AddStoreLet(id.(string), true)
+		return nil
+	})(&p.cur, stack["id"])
+}
+
+func (p *parser) call_onstmtLet_38() bool {
+	return (func(c *current) bool {
+		p.addErr(errors.New("const 声明必须赋初值"))
+		return false
+	})(&p.cur)
+}
+
+func (p *parser) call_onstmtGlobal_2() any {
+	stack := p.vstack[len(p.vstack)-1]
+	return (func(c *current, id any) any {
+		c.data.AddDeclareGlobal(id.(string))
+		c.data.AddStore(id.(string))
+		return nil
+	})(&p.cur, stack["id"])
+}
+
+func (p *parser) call_onstmtGlobal_15() any {
+	stack := p.vstack[len(p.vstack)-1]
+	return (func(c *current, id any) any {
+		c.data.AddDeclareGlobal(id.(string))
+		return nil
+	})(&p.cur, stack["id"])
+}
+
+func (p *parser) call_onstmtGlobal_24() any {
+	stack := p.vstack[len(p.vstack)-1]
+	return (func(c *current, id2 any) any {
+		c.data.AddDeclareGlobal(id2.(string))
+		return nil
+	})(&p.cur, stack["id2"])
+}
+
+func (p *parser) call_onstmtGlobal_22() any {
+	stack := p.vstack[len(p.vstack)-1]
+	return (func(c *current, id any) any {
+		c.data.PushNull()
+		return nil
+	})(&p.cur, stack["id"])
+}
+
+func (p *parser) call_onstmtImport_3() any {
+	return (func(c *current) any {
+		c.data.OpSpanBegin(c.pos.offset)
+		c.data.CounterPush()
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onstmtImport_9() any {
+	stack := p.vstack[len(p.vstack)-1]
+	return (func(c *current, path any) any {
+		c.data.AddImportFrom(path.(string), c.pos.offset, c.text)
+		return nil
+	})(&p.cur, stack["path"])
+}
+
+func (p *parser) call_onstmtImport_24() any {
+	stack := p.vstack[len(p.vstack)-1]
+	return (func(c *current, path, id any) any {
+		_ = c.data.AddImport(path.(string), id.(string), c.pos.offset, c.text)
+		return nil
+	})(&p.cur, stack["path"], stack["id"])
+}
+
+func (p *parser) call_onstmtImport_36() any {
+	stack := p.vstack[len(p.vstack)-1]
+	return (func(c *current, path any) any {
+		if err := c.data.AddImport(path.(string), "", c.pos.offset, c.text); err != nil {
+			p.addErr(err)
+			return false
+		}
+		return nil
+	})(&p.cur, stack["path"])
+}
+
+func (p *parser) call_onimportItem_2() any {
+	stack := p.vstack[len(p.vstack)-1]
+	return (func(c *current, id, alias any) any {
+		c.data.NamePush(id.(string))
+		c.data.NamePush(alias.(string))
+		c.data.CounterAdd(1)
+		return nil
+	})(&p.cur, stack["id"], stack["alias"])
+}
+
+func (p *parser) call_onimportItem_12() any {
+	stack := p.vstack[len(p.vstack)-1]
+	return (func(c *current, id any) any {
+		c.data.NamePush(id.(string))
+		c.data.NamePush(id.(string))
+		c.data.CounterAdd(1)
+		return nil
+	})(&p.cur, stack["id"])
+}
+
+func (p *parser) call_onimportPath_1() any {
+	return (func(c *current) any {
+		s := toStr(c.text)
+		return s[1 : len(s)-1]
+		return nil
+	})(&p.cur)
+}
+
 func (p *parser) call_onstmtReturn_2() any {
 	return (func(c *current) any {
 		c.data.AddOp(typeReturn)
@@ -4350,6 +6211,7 @@ func (p *parser) call_onstmtWhile_6() any {
 
 func (p *parser) call_onstmtWhile_10() any {
 	return (func(c *current) any {
+		c.data.LoopUnwind()
 		c.data.AddOp(typeJmp)
 		c.data.OffsetPush()
 		c.data.OffsetJmpSetX(0, 2, true)
@@ -4363,7 +6225,59 @@ func (p *parser) call_onstmtWhile_10() any {
 	})(&p.cur)
 }
 
-func (p *parser) call_onstmtIf_6() any {
+func (p *parser) call_onstmtThrow_1() any {
+	stack := p.vstack[len(p.vstack)-1]
+	return (func(c *current, text any) any {
+		// 位置不包括表达式尾部的空白
+		tail := len(text.(string)) - len(strings.TrimRightFunc(text.(string), unicode.IsSpace))
+		c.data.AddThrow(c.data.CounterPop(), IntType(p.pt.offset-tail))
+		return nil
+	})(&p.cur, stack["text"])
+}
+
+func (p *parser) call_onstmtTry_2() any {
+	return (func(c *current) any {
+		c.data.AddOp(typeBlockPush)
+		c.data.AddOp(typeTryBegin)
+		c.data.OffsetPush()
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onstmtTry_8() any {
+	return (func(c *current) any {
+		c.data.AddOp(typeTryEnd)
+		c.data.AddOp(typeJmp)
+		c.data.OffsetPopAndSet()
+		c.data.OffsetPush()
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onstmtTry_10() any {
+	return (func(c *current) any {
+		c.data.OffsetPopAndSet()
+		c.data.AddOp(typeBlockPop)
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onstmtCatch_3() any {
+	stack := p.vstack[len(p.vstack)-1]
+	return (func(c *current, id any) any {
+		c.data.AddStore(id.(string))
+		return nil
+	})(&p.cur, stack["id"])
+}
+
+func (p *parser) call_onstmtCatch_21() bool {
+	return (func(c *current) bool {
+		p.addErr(errors.New("不符合try语法: try {...} catch (e) {...}"))
+		return false
+	})(&p.cur)
+}
+
+func (p *parser) call_onstmtIf_3() any {
 	return (func(c *current) any {
 		c.data.AddOp(typeBlockPush)
 		c.data.AddOp(typeJne)
@@ -4372,7 +6286,7 @@ func (p *parser) call_onstmtIf_6() any {
 	})(&p.cur)
 }
 
-func (p *parser) call_onstmtIf_10() any {
+func (p *parser) call_onstmtIf_9() any {
 	return (func(c *current) any {
 		c.data.AddOp(typeJmp)
 		c.data.OffsetPopAndSet()
@@ -4381,7 +6295,7 @@ func (p *parser) call_onstmtIf_10() any {
 	})(&p.cur)
 }
 
-func (p *parser) call_onstmtIf_12() any {
+func (p *parser) call_onstmtIf_11() any {
 	return (func(c *current) any {
 		c.data.OffsetPopAndSet()
 		c.data.AddOp(typeBlockPop)
@@ -4389,13 +6303,152 @@ func (p *parser) call_onstmtIf_12() any {
 	})(&p.cur)
 }
 
-func (p *parser) call_onstmtIf_15() bool {
+func (p *parser) call_onstmtIf_17() bool {
 	return (func(c *current) bool {
 		p.addErr(errors.New("不符合if语法: if expr {...} [else {...}]"))
 		return false
 	})(&p.cur)
 }
 
+func (p *parser) call_onstmtIf_19() bool {
+	return (func(c *current) bool {
+		return c.data.Config.EnableChineseKeywords
+	})(&p.cur)
+}
+
+func (p *parser) call_onstmtIf_22() bool {
+	return (func(c *current) bool {
+		p.addErr(errors.New("不符合如果语法: 如果 expr {...} [否则 {...}]"))
+		return false
+	})(&p.cur)
+}
+
+func (p *parser) call_onstmtMatch_3() any {
+	return (func(c *current) any {
+		c.data.MatchBegin()
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onstmtMatch_18() any {
+	return (func(c *current) any {
+		c.data.MatchEnd()
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onstmtMatch_41() bool {
+	return (func(c *current) bool {
+		p.addErr(errors.New("不符合match语法: match expr { 模式 => 表达式, ... }"))
+		return false
+	})(&p.cur)
+}
+
+func (p *parser) call_onmatchArm_2() any {
+	return (func(c *current) any {
+		c.data.AddOp(typePop)
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onmatchArm_10() any {
+	return (func(c *current) any {
+		c.data.MatchArmEnd()
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onmatchPattern_6() any {
+	return (func(c *current) any {
+		c.data.MatchAddJump(typeJmp, "fail")
+		c.data.MatchSetBody()
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onmatchPattern_18() any {
+	return (func(c *current) any {
+		c.data.AddOp(typeDup)
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onmatchPattern_24() any {
+	return (func(c *current) any {
+		c.data.AddOp(typeCompGE)
+		c.data.MatchAddJump(typeJne, "fail")
+		c.data.AddOp(typeDup)
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onmatchPattern_30() any {
+	return (func(c *current) any {
+		c.data.AddOp(typeCompLE)
+		c.data.MatchAddJump(typeJne, "fail")
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onmatchPattern_32() any {
+	stack := p.vstack[len(p.vstack)-1]
+	return (func(c *current, id any) any {
+		c.data.AddStore(id.(string))
+		return nil
+	})(&p.cur, stack["id"])
+}
+
+func (p *parser) call_onmatchPattern_46() any {
+	return (func(c *current) any {
+		c.data.AddOp(typeDup)
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onmatchPattern_49() any {
+	return (func(c *current) any {
+		c.data.AddOp(typeCompEQ)
+		c.data.MatchAddJump(typeJne, "fail")
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onmatchArrayItem_2() any {
+	return (func(c *current) any {
+		c.data.AddOp(typeDup)
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onmatchArrayItem_5() any {
+	return (func(c *current) any {
+		c.data.AddOp(typeCompEQ)
+		c.data.MatchAddJump(typeJe, "body")
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onmatchGuard_1() any {
+	return (func(c *current) any {
+		c.data.MatchAddJump(typeJne, "fail")
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onmatchBody_3() any {
+	return (func(c *current) any {
+		c.data.AddOp(typeBlockPush)
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onmatchBody_6() any {
+	return (func(c *current) any {
+		c.data.AddOp(typeBlockPop)
+		return nil
+	})(&p.cur)
+}
+
 func (p *parser) call_onfunc_def_params_2() any {
 	return (func(c *current) any {
 		c.data.CounterPush()
@@ -4406,33 +6459,53 @@ func (p *parser) call_onfunc_def_params_2() any {
 func (p *parser) call_onfunc_def_params_9() any {
 	return (func(c *current) any {
 		c.data.CounterPush()
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onfunc_def_param_2() any {
+	stack := p.vstack[len(p.vstack)-1]
+	return (func(c *current, id any) any {
+		c.data.NamePush("..." + id.(string))
+		c.data.FuncDefaultPush()
 		c.data.CounterAdd(1)
 		return nil
+	})(&p.cur, stack["id"])
+}
+
+func (p *parser) call_onfunc_def_param_13() bool {
+	return (func(c *current) bool {
+		p.addErr(errors.New("剩余参数只能放在参数列表的最后"))
+		return false
 	})(&p.cur)
 }
 
-func (p *parser) call_onfunc_def_params_13() any {
+func (p *parser) call_onfunc_def_param_15() any {
 	stack := p.vstack[len(p.vstack)-1]
 	return (func(c *current, id any) any {
-		c.data.NamePush(id.(string))
+		c.data.CodePush(p.pt.offset)
 		return nil
 	})(&p.cur, stack["id"])
 }
 
-func (p *parser) call_onfunc_def_params_21() any {
+func (p *parser) call_onfunc_def_param_22() any {
 	stack := p.vstack[len(p.vstack)-1]
-	return (func(c *current, id2 any) any {
-		c.data.NamePush(id2.(string))
+	return (func(c *current, id, expr any) any {
+		c.data.NamePush(id.(string))
+		c.data.FuncDefaultPushExpr(expr.(string))
+		c.data.CounterAdd(1)
 		return nil
-	})(&p.cur, stack["id2"])
+	})(&p.cur, stack["id"], stack["expr"])
 }
 
-func (p *parser) call_onfunc_def_params_28() any {
+func (p *parser) call_onfunc_def_param_27() any {
 	stack := p.vstack[len(p.vstack)-1]
-	return (func(c *current, id2 any) any {
+	return (func(c *current, id any) any {
+		c.data.NamePush(id.(string))
+		c.data.FuncDefaultPush()
 		c.data.CounterAdd(1)
 		return nil
-	})(&p.cur, stack["id2"])
+	})(&p.cur, stack["id"])
 }
 
 func (p *parser) call_onstmtFunc_2() any {
@@ -4507,6 +6580,7 @@ func (p *parser) call_onstmtAssignType2_12() any {
 func (p *parser) call_onstmtAssignType3_2() any {
 	stack := p.vstack[len(p.vstack)-1]
 	return (func(c *current, id any) any {
+		c.data.OpSpanBegin(c.pos.offset)
 		c.data.NamePush(id.(string))
 		return nil
 	})(&p.cur, stack["id"])
@@ -4524,7 +6598,7 @@ func (p *parser) call_onstmtAssignType3_14() any {
 	stack := p.vstack[len(p.vstack)-1]
 	return (func(c *current, id, id2 any) any {
 		attr, objName := c.data.NamePop(), c.data.NamePop()
-		c.data.AddAttrSet(objName, attr, true)
+		c.data.AddAttrSet(objName, attr, true, c.pos.offset, c.text)
 		return nil
 	})(&p.cur, stack["id"], stack["id2"])
 }
@@ -4548,6 +6622,7 @@ func (p *parser) call_onstmtAssignType4_11() any {
 func (p *parser) call_onstmtAssignType5_2() any {
 	stack := p.vstack[len(p.vstack)-1]
 	return (func(c *current, id any) any {
+		c.data.OpSpanBegin(c.pos.offset)
 		c.data.NamePush(id.(string))
 		return nil
 	})(&p.cur, stack["id"])
@@ -4565,7 +6640,7 @@ func (p *parser) call_onstmtAssignType5_14() any {
 	stack := p.vstack[len(p.vstack)-1]
 	return (func(c *current, id, id2 any) any {
 		attr, objName := c.data.NamePop(), c.data.NamePop()
-		c.data.AddAttrSet(objName, attr, false)
+		c.data.AddAttrSet(objName, attr, false, c.pos.offset, c.text)
 		return nil
 	})(&p.cur, stack["id"], stack["id2"])
 }
@@ -4584,6 +6659,164 @@ func (p *parser) call_onstmtAssignType7_1() any {
 	})(&p.cur)
 }
 
+func (p *parser) call_onstmtAssignType8_1() any {
+	stack := p.vstack[len(p.vstack)-1]
+	return (func(c *current, id, op any) any {
+		c.data.AddStoreNameOp(id.(string), op.(string))
+		return nil
+	})(&p.cur, stack["id"], stack["op"])
+}
+
+func (p *parser) call_onstmtAssignType9_1() any {
+	stack := p.vstack[len(p.vstack)-1]
+	return (func(c *current, id, id2, op any) any {
+		c.data.AddAttrSetOp(id.(string), id2.(string), op.(string), c.pos.offset, c.text)
+		return nil
+	})(&p.cur, stack["id"], stack["id2"], stack["op"])
+}
+
+func (p *parser) call_onstmtAssignType10_1() any {
+	stack := p.vstack[len(p.vstack)-1]
+	return (func(c *current, op any) any {
+		c.data.AddItemSetOp(op.(string))
+		return nil
+	})(&p.cur, stack["op"])
+}
+
+func (p *parser) call_onstmtAssignType11_2() any {
+	return (func(c *current) any {
+		c.data.OpSpanBegin(c.pos.offset)
+		c.data.CounterPush()
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onstmtAssignType11_6() any {
+	return (func(c *current) any {
+		if err := c.data.AddUnpackArray(c.pos.offset, c.text); err != nil {
+			p.addErr(err)
+			return false
+		}
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onstmtAssignType12_2() any {
+	return (func(c *current) any {
+		c.data.OpSpanBegin(c.pos.offset)
+		c.data.CounterPush()
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onstmtAssignType12_6() any {
+	return (func(c *current) any {
+		c.data.AddUnpackDict(c.pos.offset, c.text)
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_ondestructItem_2() any {
+	stack := p.vstack[len(p.vstack)-1]
+	return (func(c *current, id any) any {
+		c.data.NamePush("..." + id.(string))
+		c.data.CounterAdd(1)
+		return nil
+	})(&p.cur, stack["id"])
+}
+
+func (p *parser) call_ondestructItem_9() any {
+	stack := p.vstack[len(p.vstack)-1]
+	return (func(c *current, id any) any {
+		c.data.NamePush(id.(string))
+		c.data.CounterAdd(1)
+		return nil
+	})(&p.cur, stack["id"])
+}
+
+func (p *parser) call_ondestructDictItem_2() any {
+	stack := p.vstack[len(p.vstack)-1]
+	return (func(c *current, id any) any {
+		c.data.NamePush("...")
+		c.data.NamePush(id.(string))
+		c.data.CounterAdd(1)
+		return nil
+	})(&p.cur, stack["id"])
+}
+
+func (p *parser) call_ondestructDictItem_11() any {
+	stack := p.vstack[len(p.vstack)-1]
+	return (func(c *current, key, id any) any {
+		c.data.NamePush(key.(string))
+		c.data.NamePush(id.(string))
+		c.data.CounterAdd(1)
+		return nil
+	})(&p.cur, stack["key"], stack["id"])
+}
+
+func (p *parser) call_ondestructDictItem_21() any {
+	stack := p.vstack[len(p.vstack)-1]
+	return (func(c *current, id any) any {
+		c.data.NamePush(id.(string))
+		c.data.NamePush(id.(string))
+		c.data.CounterAdd(1)
+		return nil
+	})(&p.cur, stack["id"])
+}
+
+func (p *parser) call_onstmtAssignTuple_2() any {
+	return (func(c *current) any {
+		c.data.OpSpanBegin(c.pos.offset)
+		c.data.CounterPush()
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onstmtAssignTuple_16() any {
+	stack := p.vstack[len(p.vstack)-1]
+	return (func(c *current, id any) any {
+		c.data.NamePush(id.(string))
+		c.data.CounterAdd(1)
+		return nil
+	})(&p.cur, stack["id"])
+}
+
+func (p *parser) call_onstmtAssignTuple_24() any {
+	stack := p.vstack[len(p.vstack)-1]
+	return (func(c *current, id2 any) any {
+		c.data.NamePush(id2.(string))
+		c.data.CounterAdd(1)
+		return nil
+	})(&p.cur, stack["id2"])
+}
+
+func (p *parser) call_onstmtAssignTuple_21() any {
+	stack := p.vstack[len(p.vstack)-1]
+	return (func(c *current, id any) any {
+		c.data.CounterPush()
+		c.data.CounterAdd(1)
+		return nil
+	})(&p.cur, stack["id"])
+}
+
+func (p *parser) call_onstmtAssignTuple_38() any {
+	return (func(c *current) any {
+		c.data.CounterAdd(1)
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onstmtAssignTuple_33() any {
+	stack := p.vstack[len(p.vstack)-1]
+	return (func(c *current, id any) any {
+		if err := c.data.AddUnpackTuple(c.pos.offset, c.text); err != nil {
+			p.addErr(err)
+			return false
+		}
+		return nil
+	})(&p.cur, stack["id"])
+}
+
 func (p *parser) call_on_step_7() any {
 	return (func(c *current) any {
 		c.data.PushNull()
@@ -4591,253 +6824,364 @@ func (p *parser) call_on_step_7() any {
 	})(&p.cur)
 }
 
-func (p *parser) call_on_step_9() any {
-	return (func(c *current) any {
-		c.data.PushNull()
-		return nil
+func (p *parser) call_on_step_9() any {
+	return (func(c *current) any {
+		c.data.PushNull()
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_on_sliceSuffix_6() any {
+	return (func(c *current) any {
+		c.data.PushNull()
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_on_sliceSuffix_12() any {
+	return (func(c *current) any {
+		c.data.PushNull()
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onexprSliceType1_1() any {
+	return (func(c *current) any {
+		c.data.AddOpWithSpan(typeSliceGet, c.pos.offset, c.text)
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onexprValueIfExists_2() any {
+	return (func(c *current) any {
+		c.data.AddOp(typeJne)
+		c.data.OffsetPush()
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onexprValueIfExists_8() any {
+	return (func(c *current) any {
+		c.data.AddOp(typeJmp)
+		c.data.OffsetPopAndSet()
+		c.data.OffsetPush()
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onexprTernaryType1_2() any {
+	return (func(c *current) any {
+		c.data.AddOp(typeJne)
+		c.data.OffsetPush()
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onexprTernaryType1_8() any {
+	return (func(c *current) any {
+		c.data.AddOp(typeJmp)
+		c.data.OffsetPopAndSet()
+		c.data.OffsetPush()
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onexprTernaryType1_14() any {
+	return (func(c *current) any {
+		c.data.OffsetPopAndSet()
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onexprTernaryType2_2() any {
+	return (func(c *current) any {
+		c.data.CounterPush()
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onexprTernaryType2_6() any {
+	return (func(c *current) any {
+		c.data.CounterAdd(1)
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onexprTernaryType2_4() any {
+	return (func(c *current) any {
+		c.data.PushStr("")
+		limit := c.data.CounterPop() + 1
+		for i := IntType(0); i < limit; i++ {
+			c.data.OffsetPopAndSet()
+		}
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onexprLogicOr_5() any {
+	return (func(c *current) any {
+		c.data.AddOp(typeJeDup)
+		c.data.OffsetPush()
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onexprLogicOr_9() any {
+	return (func(c *current) any {
+		c.data.AddOp(typeJeDup)
+		c.data.OffsetPush()
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onexprLogicOr_11() any {
+	return (func(c *current) any {
+		c.data.AddOp(typePushLast)
+		c.data.OffsetPopAndSet()
+		c.data.OffsetPopAndSet()
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onexprLogicAnd_4() any {
+	return (func(c *current) any {
+		c.data.AddOp(typeLogicAnd)
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onexprBitwiseOr_3() bool {
+	return (func(c *current) bool {
+		return c.data.Config.DisableBitwiseOp
 	})(&p.cur)
 }
 
-func (p *parser) call_on_sliceSuffix_6() any {
+func (p *parser) call_onexprBitwiseOr_6() any {
 	return (func(c *current) any {
-		c.data.PushNull()
+		c.data.OpSpanBegin(c.pos.offset)
 		return nil
 	})(&p.cur)
 }
 
-func (p *parser) call_on_sliceSuffix_12() any {
+func (p *parser) call_onexprBitwiseOr_10() any {
 	return (func(c *current) any {
-		c.data.PushNull()
+		c.data.AddBinaryOp(typeBitwiseOr, c.pos.offset, c.text)
 		return nil
 	})(&p.cur)
 }
 
-func (p *parser) call_onexprSliceType1_1() any {
+func (p *parser) call_onexprBitwiseOr_8() any {
 	return (func(c *current) any {
-		c.data.AddOp(typeSliceGet)
+		c.data.OpSpanEnd()
 		return nil
 	})(&p.cur)
 }
 
-func (p *parser) call_onexprValueIfExists_2() any {
+func (p *parser) call_onexprBitwiseAnd_2() any {
 	return (func(c *current) any {
-		c.data.AddOp(typeJne)
-		c.data.OffsetPush()
+		c.data.OpSpanBegin(c.pos.offset)
 		return nil
 	})(&p.cur)
 }
 
-func (p *parser) call_onexprValueIfExists_8() any {
+func (p *parser) call_onexprBitwiseAnd_6() any {
 	return (func(c *current) any {
-		c.data.AddOp(typeJmp)
-		c.data.OffsetPopAndSet()
-		c.data.OffsetPush()
+		c.data.AddBinaryOp(typeBitwiseAnd, c.pos.offset, c.text)
 		return nil
 	})(&p.cur)
 }
 
-func (p *parser) call_onexprTernaryType1_2() any {
+func (p *parser) call_onexprBitwiseAnd_4() any {
 	return (func(c *current) any {
-		c.data.AddOp(typeJne)
-		c.data.OffsetPush()
+		c.data.OpSpanEnd()
 		return nil
 	})(&p.cur)
 }
 
-func (p *parser) call_onexprTernaryType1_8() any {
+func (p *parser) call_onexprCompare_2() any {
 	return (func(c *current) any {
-		c.data.AddOp(typeJmp)
-		c.data.OffsetPopAndSet()
-		c.data.OffsetPush()
+		c.data.OpSpanBegin(c.pos.offset)
 		return nil
 	})(&p.cur)
 }
 
-func (p *parser) call_onexprTernaryType1_14() any {
+func (p *parser) call_onexprCompare_9() any {
 	return (func(c *current) any {
-		c.data.OffsetPopAndSet()
+		c.data.AddBinaryOp(typeCompLT, c.pos.offset, c.text)
 		return nil
 	})(&p.cur)
 }
 
-func (p *parser) call_onexprTernaryType2_2() any {
+func (p *parser) call_onexprCompare_13() any {
 	return (func(c *current) any {
-		c.data.CounterPush()
+		c.data.AddBinaryOp(typeCompLE, c.pos.offset, c.text)
 		return nil
 	})(&p.cur)
 }
 
-func (p *parser) call_onexprTernaryType2_6() any {
+func (p *parser) call_onexprCompare_17() any {
 	return (func(c *current) any {
-		c.data.CounterAdd(1)
+		c.data.AddBinaryOp(typeCompEQ, c.pos.offset, c.text)
 		return nil
 	})(&p.cur)
 }
 
-func (p *parser) call_onexprTernaryType2_4() any {
+func (p *parser) call_onexprCompare_21() any {
 	return (func(c *current) any {
-		c.data.PushStr("")
-		limit := c.data.CounterPop() + 1
-		for i := IntType(0); i < limit; i++ {
-			c.data.OffsetPopAndSet()
-		}
+		c.data.AddBinaryOp(typeCompNE, c.pos.offset, c.text)
 		return nil
 	})(&p.cur)
 }
 
-func (p *parser) call_onexprLogicOr_5() any {
+func (p *parser) call_onexprCompare_25() any {
 	return (func(c *current) any {
-		c.data.AddOp(typeJeDup)
-		c.data.OffsetPush()
+		c.data.AddBinaryOp(typeCompGE, c.pos.offset, c.text)
 		return nil
 	})(&p.cur)
 }
 
-func (p *parser) call_onexprLogicOr_9() any {
+func (p *parser) call_onexprCompare_29() any {
 	return (func(c *current) any {
-		c.data.AddOp(typeJeDup)
-		c.data.OffsetPush()
+		c.data.AddBinaryOp(typeCompGT, c.pos.offset, c.text)
 		return nil
 	})(&p.cur)
 }
 
-func (p *parser) call_onexprLogicOr_11() any {
+func (p *parser) call_onexprCompare_33() any {
 	return (func(c *current) any {
-		c.data.AddOp(typePushLast)
-		c.data.OffsetPopAndSet()
-		c.data.OffsetPopAndSet()
+		c.data.AddBinaryOp(typeNotIn, c.pos.offset, c.text)
 		return nil
 	})(&p.cur)
 }
 
-func (p *parser) call_onexprLogicAnd_4() any {
+func (p *parser) call_onexprCompare_37() any {
 	return (func(c *current) any {
-		c.data.AddOp(typeLogicAnd)
+		c.data.AddBinaryOp(typeIn, c.pos.offset, c.text)
 		return nil
 	})(&p.cur)
 }
 
-func (p *parser) call_onexprBitwiseOr_3() bool {
-	return (func(c *current) bool {
-		return c.data.Config.DisableBitwiseOp
+func (p *parser) call_onexprCompare_4() any {
+	return (func(c *current) any {
+		c.data.OpSpanEnd()
+		return nil
 	})(&p.cur)
 }
 
-func (p *parser) call_onexprBitwiseOr_8() any {
+func (p *parser) call_onexprAdditive_2() any {
 	return (func(c *current) any {
-		c.data.AddOp(typeBitwiseOr)
+		c.data.OpSpanBegin(c.pos.offset)
 		return nil
 	})(&p.cur)
 }
 
-func (p *parser) call_onexprBitwiseAnd_4() any {
+func (p *parser) call_onexprAdditive_9() any {
 	return (func(c *current) any {
-		c.data.AddOp(typeBitwiseAnd)
+		c.data.AddBinaryOp(typeAdd, c.pos.offset, c.text)
 		return nil
 	})(&p.cur)
 }
 
-func (p *parser) call_onexprCompare_7() any {
+func (p *parser) call_onexprAdditive_13() any {
 	return (func(c *current) any {
-		c.data.AddOp(typeCompLT)
+		c.data.AddBinaryOp(typeSubtract, c.pos.offset, c.text)
 		return nil
 	})(&p.cur)
 }
 
-func (p *parser) call_onexprCompare_11() any {
+func (p *parser) call_onexprAdditive_4() any {
 	return (func(c *current) any {
-		c.data.AddOp(typeCompLE)
+		c.data.OpSpanEnd()
 		return nil
 	})(&p.cur)
 }
 
-func (p *parser) call_onexprCompare_15() any {
+func (p *parser) call_onexprMultiplicative_2() any {
 	return (func(c *current) any {
-		c.data.AddOp(typeCompEQ)
+		c.data.OpSpanBegin(c.pos.offset)
 		return nil
 	})(&p.cur)
 }
 
-func (p *parser) call_onexprCompare_19() any {
+func (p *parser) call_onexprMultiplicative_9() any {
 	return (func(c *current) any {
-		c.data.AddOp(typeCompNE)
+		c.data.AddBinaryOp(typeMultiply, c.pos.offset, c.text)
 		return nil
 	})(&p.cur)
 }
 
-func (p *parser) call_onexprCompare_23() any {
+func (p *parser) call_onexprMultiplicative_13() any {
 	return (func(c *current) any {
-		c.data.AddOp(typeCompGE)
+		c.data.AddBinaryOp(typeDivide, c.pos.offset, c.text)
 		return nil
 	})(&p.cur)
 }
 
-func (p *parser) call_onexprCompare_27() any {
+func (p *parser) call_onexprMultiplicative_17() any {
 	return (func(c *current) any {
-		c.data.AddOp(typeCompGT)
+		c.data.AddBinaryOp(typeModulus, c.pos.offset, c.text)
 		return nil
 	})(&p.cur)
 }
 
-func (p *parser) call_onexprAdditive_7() any {
+func (p *parser) call_onexprMultiplicative_4() any {
 	return (func(c *current) any {
-		c.data.AddOp(typeAdd)
+		c.data.OpSpanEnd()
 		return nil
 	})(&p.cur)
 }
 
-func (p *parser) call_onexprAdditive_11() any {
+func (p *parser) call_onexprNullCoalescing_4() any {
 	return (func(c *current) any {
-		c.data.AddOp(typeSubtract)
+		c.data.AddOp(typeNullCoalescing)
 		return nil
 	})(&p.cur)
 }
 
-func (p *parser) call_onexprMultiplicative_7() any {
-	return (func(c *current) any {
-		c.data.AddOp(typeMultiply)
-		return nil
+func (p *parser) call_onexprExp_4() bool {
+	return (func(c *current) bool {
+		return c.data.checkCanceled(p.ExprCnt)
 	})(&p.cur)
 }
 
-func (p *parser) call_onexprMultiplicative_11() any {
+func (p *parser) call_onexprExp_2() any {
 	return (func(c *current) any {
-		c.data.AddOp(typeDivide)
+		c.data.OpSpanBegin(c.pos.offset)
 		return nil
 	})(&p.cur)
 }
 
-func (p *parser) call_onexprMultiplicative_15() any {
+func (p *parser) call_onexprExp_8() any {
 	return (func(c *current) any {
-		c.data.AddOp(typeModulus)
+		c.data.AddBinaryOp(typeExponentiation, c.pos.offset, c.text)
 		return nil
 	})(&p.cur)
 }
 
-func (p *parser) call_onexprNullCoalescing_4() any {
+func (p *parser) call_onexprExp_6() any {
 	return (func(c *current) any {
-		c.data.AddOp(typeNullCoalescing)
+		c.data.OpSpanEnd()
 		return nil
 	})(&p.cur)
 }
 
-func (p *parser) call_onexprExp_4() any {
+func (p *parser) call_onexprUnaryNeg_2() any {
 	return (func(c *current) any {
-		c.data.AddOp(typeExponentiation)
+		c.data.AddOpWithSpan(typeNegation, c.pos.offset, c.text)
 		return nil
 	})(&p.cur)
 }
 
-func (p *parser) call_onexprUnaryNeg_2() any {
+func (p *parser) call_onexprUnaryNeg_6() any {
 	return (func(c *current) any {
-		c.data.AddOp(typeNegation)
+		c.data.AddOp(typeLogicNot)
 		return nil
 	})(&p.cur)
 }
 
 func (p *parser) call_onexprUnaryPos_2() any {
 	return (func(c *current) any {
-		c.data.AddOp(typePositive)
+		c.data.AddOpWithSpan(typePositive, c.pos.offset, c.text)
 		return nil
 	})(&p.cur)
 }
@@ -5189,7 +7533,7 @@ func (p *parser) call_onexprDice_101() any {
 
 func (p *parser) call_onarray_call_3() any {
 	return (func(c *current) any {
-		c.data.WriteCode(typeAttrGet, string("kh"))
+		c.data.AddAttrGet("kh", c.pos.offset, c.text)
 		return nil
 	})(&p.cur)
 }
@@ -5210,7 +7554,7 @@ func (p *parser) call_onarray_call_8() any {
 
 func (p *parser) call_onarray_call_10() any {
 	return (func(c *current) any {
-		c.data.WriteCode(typeAttrGet, string("kl"))
+		c.data.AddAttrGet("kl", c.pos.offset, c.text)
 		return nil
 	})(&p.cur)
 }
@@ -5231,30 +7575,38 @@ func (p *parser) call_onarray_call_15() any {
 
 func (p *parser) call_onarray_call_17() any {
 	return (func(c *current) any {
-		c.data.AddOp(typeItemGet)
+		c.data.AddOpWithSpan(typeItemGet, c.pos.offset, c.text)
 		return nil
 	})(&p.cur)
 }
 
 func (p *parser) call_onitem_getX_3() any {
 	return (func(c *current) any {
-		c.data.AddOp(typeItemGet)
+		c.data.AddOpWithSpan(typeItemGet, c.pos.offset, c.text)
 		return nil
 	})(&p.cur)
 }
 
-func (p *parser) call_onattr_getX_4() any {
+func (p *parser) call_onattr_getX_3() any {
 	stack := p.vstack[len(p.vstack)-1]
 	return (func(c *current, id any) any {
-		c.data.WriteCode(typeAttrGet, id.(string))
+		c.data.AddAttrGet(id.(string), c.pos.offset, c.text)
 		return nil
 	})(&p.cur, stack["id"])
 }
 
+func (p *parser) call_onoptChain_1() any {
+	return (func(c *current) any {
+		c.data.OptChainJump()
+		return nil
+	})(&p.cur)
+}
+
 func (p *parser) call_onfunc_invoke2_2() any {
 	return (func(c *current) any {
 		c.data.CounterPush()
 		c.data.CounterAdd(1)
+		c.data.ArgNamesBegin()
 		return nil
 	})(&p.cur)
 }
@@ -5268,18 +7620,43 @@ func (p *parser) call_onfunc_invoke2_11() any {
 
 func (p *parser) call_onfunc_invoke2_6() any {
 	return (func(c *current) any {
-		c.data.AddInvoke(c.data.CounterPop())
+		if err := c.data.AddInvokeWithNames(c.data.CounterPop()); err != nil {
+			p.addErr(err)
+			return false
+		}
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onfunc_arg_2() any {
+	stack := p.vstack[len(p.vstack)-1]
+	return (func(c *current, id any) any {
+		c.data.ArgNamePush(id.(string))
+		return nil
+	})(&p.cur, stack["id"])
+}
+
+func (p *parser) call_onfunc_arg_16() any {
+	return (func(c *current) any {
+		c.data.ArgPositional()
 		return nil
 	})(&p.cur)
 }
 
-func (p *parser) call_onfunc_invoke_2() any {
+func (p *parser) call_onfunc_call_3() any {
 	return (func(c *current) any {
 		c.data.AddInvoke(0)
 		return nil
 	})(&p.cur)
 }
 
+func (p *parser) call_onfunc_call_1() any {
+	return (func(c *current) any {
+		c.data.SetInvokeSpan(IntType(c.pos.offset), IntType(c.pos.offset+len(c.text)))
+		return nil
+	})(&p.cur)
+}
+
 func (p *parser) call_ondict_item_1() any {
 	return (func(c *current) any {
 		c.data.CounterAdd(1)
@@ -5295,9 +7672,25 @@ func (p *parser) call_onvalue_id_without_colon_2() any {
 	})(&p.cur, stack["id"])
 }
 
+func (p *parser) call_onvalue_id_without_colon_7() any {
+	stack := p.vstack[len(p.vstack)-1]
+	return (func(c *current, id any) any {
+		c.data.OptChainBegin()
+		return nil
+	})(&p.cur, stack["id"])
+}
+
+func (p *parser) call_onvalue_id_without_colon_8() any {
+	stack := p.vstack[len(p.vstack)-1]
+	return (func(c *current, id any) any {
+		c.data.OptChainEnd()
+		return nil
+	})(&p.cur, stack["id"])
+}
+
 func (p *parser) call_onvalue_array_range_1() any {
 	return (func(c *current) any {
-		c.data.AddOp(typePushRange)
+		c.data.AddOpWithSpan(typePushRange, c.pos.offset, c.text)
 		return nil
 	})(&p.cur)
 }
@@ -5326,14 +7719,14 @@ func (p *parser) call_onvalue_array_6() any {
 
 func (p *parser) call_onvalue_2() any {
 	return (func(c *current) any {
-		c.data.PushIntNumber("1")
+		c.data.PushBool(true)
 		return nil
 	})(&p.cur)
 }
 
 func (p *parser) call_onvalue_6() any {
 	return (func(c *current) any {
-		c.data.PushIntNumber("0")
+		c.data.PushBool(false)
 		return nil
 	})(&p.cur)
 }
@@ -5352,7 +7745,21 @@ func (p *parser) call_onvalue_15() any {
 	})(&p.cur)
 }
 
-func (p *parser) call_onvalue_23() any {
+func (p *parser) call_onvalue_19() any {
+	return (func(c *current) any {
+		c.data.OptChainBegin()
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onvalue_20() any {
+	return (func(c *current) any {
+		c.data.OptChainEnd()
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onvalue_25() any {
 	stack := p.vstack[len(p.vstack)-1]
 	return (func(c *current, id any) any {
 		c.data.WriteCode(typeLoadNameRaw, id.(string))
@@ -5360,7 +7767,23 @@ func (p *parser) call_onvalue_23() any {
 	})(&p.cur, stack["id"])
 }
 
-func (p *parser) call_onvalue_33() any {
+func (p *parser) call_onvalue_31() any {
+	stack := p.vstack[len(p.vstack)-1]
+	return (func(c *current, id any) any {
+		c.data.OptChainBegin()
+		return nil
+	})(&p.cur, stack["id"])
+}
+
+func (p *parser) call_onvalue_32() any {
+	stack := p.vstack[len(p.vstack)-1]
+	return (func(c *current, id any) any {
+		c.data.OptChainEnd()
+		return nil
+	})(&p.cur, stack["id"])
+}
+
+func (p *parser) call_onvalue_46() any {
 	stack := p.vstack[len(p.vstack)-1]
 	return (func(c *current, id any) any {
 		c.data.WriteCode(typeLoadNameWithDetail, id.(string))
@@ -5368,34 +7791,148 @@ func (p *parser) call_onvalue_33() any {
 	})(&p.cur, stack["id"])
 }
 
-func (p *parser) call_onvalue_55() any {
+func (p *parser) call_onvalue_57() any {
+	stack := p.vstack[len(p.vstack)-1]
+	return (func(c *current, id any) any {
+		c.data.OptChainBegin()
+		return nil
+	})(&p.cur, stack["id"])
+}
+
+func (p *parser) call_onvalue_58() any {
+	stack := p.vstack[len(p.vstack)-1]
+	return (func(c *current, id any) any {
+		c.data.OptChainEnd()
+		return nil
+	})(&p.cur, stack["id"])
+}
+
+func (p *parser) call_onvalue_65() any {
+	return (func(c *current) any {
+		c.data.OptChainBegin()
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onvalue_67() any {
+	return (func(c *current) any {
+		c.data.OptChainEnd()
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onvalue_70() any {
+	return (func(c *current) any {
+		c.data.OptChainBegin()
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onvalue_72() any {
+	return (func(c *current) any {
+		c.data.OptChainEnd()
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onvalue_77() any {
 	return (func(c *current) any {
 		c.data.PushArray(0)
 		return nil
 	})(&p.cur)
 }
 
-func (p *parser) call_onvalue_80() any {
+func (p *parser) call_onvalue_83() any {
 	return (func(c *current) any {
-		c.data.PushDict(0)
+		c.data.OptChainBegin()
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onvalue_84() any {
+	return (func(c *current) any {
+		c.data.OptChainEnd()
 		return nil
 	})(&p.cur)
 }
 
 func (p *parser) call_onvalue_90() any {
+	return (func(c *current) any {
+		c.data.OptChainBegin()
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onvalue_95() any {
+	return (func(c *current) any {
+		c.data.OptChainEnd()
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onvalue_101() any {
+	return (func(c *current) any {
+		c.data.OptChainBegin()
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onvalue_106() any {
+	return (func(c *current) any {
+		c.data.OptChainEnd()
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onvalue_112() any {
+	return (func(c *current) any {
+		c.data.PushDict(0)
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onvalue_118() any {
+	return (func(c *current) any {
+		c.data.OptChainBegin()
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onvalue_119() any {
+	return (func(c *current) any {
+		c.data.OptChainEnd()
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onvalue_124() any {
 	return (func(c *current) any {
 		c.data.CounterPush()
 		return nil
 	})(&p.cur)
 }
 
-func (p *parser) call_onvalue_94() any {
+func (p *parser) call_onvalue_128() any {
 	return (func(c *current) any {
 		c.data.PushDict(c.data.CounterPop())
 		return nil
 	})(&p.cur)
 }
 
+func (p *parser) call_onvalue_140() any {
+	return (func(c *current) any {
+		c.data.OptChainBegin()
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onvalue_141() any {
+	return (func(c *current) any {
+		c.data.OptChainEnd()
+		return nil
+	})(&p.cur)
+}
+
 func (p *parser) call_onnumber_1() any {
 	return (func(c *current) any {
 		c.data.PushIntNumber(toStr(c.text))
@@ -5561,7 +8098,7 @@ func (p *parser) call_onfstringStmt_7() any {
 
 func (p *parser) call_onfstringStmt_9() bool {
 	return (func(c *current) bool {
-		p.addErr(errors.New("{%} 内必须是语句块或表达式"))
+		p.addErr(errors.New("{% %} 内必须是语句块或表达式"))
 		return false
 	})(&p.cur)
 }
@@ -5672,6 +8209,12 @@ func (p *parser) call_onfstring_49() any {
 	})(&p.cur)
 }
 
+func (p *parser) call_onkeywords_17() bool {
+	return (func(c *current) bool {
+		return c.data.Config.EnableChineseKeywords
+	})(&p.cur)
+}
+
 func (p *parser) call_onkeywords_test_6() bool {
 	return (func(c *current) bool {
 		p.addErr(errors.New("使用关键字作为变量名"))
@@ -5679,6 +8222,48 @@ func (p *parser) call_onkeywords_test_6() bool {
 	})(&p.cur)
 }
 
+func (p *parser) call_onkwIf_4() bool {
+	return (func(c *current) bool {
+		return c.data.Config.EnableChineseKeywords
+	})(&p.cur)
+}
+
+func (p *parser) call_onkwElse_4() bool {
+	return (func(c *current) bool {
+		return c.data.Config.EnableChineseKeywords
+	})(&p.cur)
+}
+
+func (p *parser) call_onkwWhile_4() bool {
+	return (func(c *current) bool {
+		return c.data.Config.EnableChineseKeywords
+	})(&p.cur)
+}
+
+func (p *parser) call_onkwFunc_4() bool {
+	return (func(c *current) bool {
+		return c.data.Config.EnableChineseKeywords
+	})(&p.cur)
+}
+
+func (p *parser) call_onkwReturn_4() bool {
+	return (func(c *current) bool {
+		return c.data.Config.EnableChineseKeywords
+	})(&p.cur)
+}
+
+func (p *parser) call_onkwBreak_4() bool {
+	return (func(c *current) bool {
+		return c.data.Config.EnableChineseKeywords
+	})(&p.cur)
+}
+
+func (p *parser) call_onkwContinue_4() bool {
+	return (func(c *current) bool {
+		return c.data.Config.EnableChineseKeywords
+	})(&p.cur)
+}
+
 func (p *parser) call_onidentifier_1() any {
 	return (func(c *current) any {
 		return toStr(c.text)
@@ -5693,6 +8278,20 @@ func (p *parser) call_onidentifierWithoutColon_1() any {
 	})(&p.cur)
 }
 
+func (p *parser) call_onsubX_2() any {
+	return (func(c *current) any {
+		c.data.OptChainBegin()
+		return nil
+	})(&p.cur)
+}
+
+func (p *parser) call_onsubX_4() any {
+	return (func(c *current) any {
+		c.data.OptChainEnd()
+		return nil
+	})(&p.cur)
+}
+
 func (p *parser) call_onest_7() any {
 	return (func(c *current) any {
 		c.data.FlagsPush()
@@ -7,6 +7,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"golang.org/x/exp/rand"
@@ -20,6 +21,9 @@ func getSource() *rand.PCGSource {
 
 var randSource = getSource()
 
+// randSourceMu 未设置种子的上下文共用 randSource，并发执行时需要加锁
+var randSourceMu sync.Mutex
+
 func _roll32(src *rand.PCGSource, dicePoints int) int {
 	// 注: int的长度至少为32位，也可以高于此数，此处只是当作32位处理
 	if dicePoints > math.MaxInt32-1 {
@@ -72,6 +76,8 @@ func Roll(src *rand.PCGSource, dicePoints IntType, mod int) IntType {
 		return dicePoints
 	}
 	if src == nil {
+		randSourceMu.Lock()
+		defer randSourceMu.Unlock()
 		src = randSource
 	}
 
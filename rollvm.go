@@ -18,8 +18,10 @@ package dicescript
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"math/big"
 	"regexp"
 	"sort"
 	"strconv"
@@ -28,6 +30,34 @@ import (
 	"unicode"
 )
 
+var (
+	errOpCountLimit  = errors.New("允许算力上限")
+	errStackOverflow = errors.New("执行栈到达溢出线")
+)
+
+const (
+	vmStackInitSize = 32   // 执行栈的初始大小，不够时倍增
+	vmStackLimit    = 1000 // 执行栈的上限，超出时报错
+)
+
+// ThrowError 脚本中 throw 语句抛出的错误，未被 catch 时会作为 ctx.Error 返回
+type ThrowError struct {
+	Value *VMValue   // 被抛出的值
+	Span  BufferSpan // throw 语句在源码中的位置
+
+	ctx *Context // 抛出时所在的虚拟机，Span 只对它有效
+}
+
+func (e *ThrowError) Error() string {
+	return e.Value.ToString()
+}
+
+// isCatchableError 算力上限、内存上限、栈溢出、调用层数上限和取消不能被 try 捕获，避免脚本借此绕过限制
+func isCatchableError(err error) bool {
+	return !errors.Is(err, errOpCountLimit) && !errors.Is(err, errMemoryLimit) &&
+		!errors.Is(err, errStackOverflow) && !errors.Is(err, errCallDepthLimit) && !errors.Is(err, ErrCanceled)
+}
+
 func NewVM() *Context {
 	// 创建parser
 	p := &Context{}
@@ -61,7 +91,7 @@ func (ctx *Context) GetErrorText() string {
 }
 
 func (ctx *Context) GetParsedOffset() int {
-	return ctx.parser.pt.offset
+	return ctx.sourceOffset(ctx.parser.pt.offset)
 }
 
 func (ctx *Context) Parse(value string) error {
@@ -70,6 +100,12 @@ func (ctx *Context) Parse(value string) error {
 		return errors.New("正在执行中，无法执行新的语句")
 	}
 
+	ctx.source, ctx.sourceOffsets = nil, nil
+	if ctx.Config.InputNormalizer != nil {
+		ctx.source = []byte(value)
+		value, ctx.sourceOffsets = normalizeInput(value, ctx.Config.InputNormalizer)
+	}
+
 	p := newParser("", []byte(value), memoized(true))
 	ctx.parser = p
 	d := p.cur.data
@@ -83,22 +119,27 @@ func (ctx *Context) Parse(value string) error {
 	d.pendingCustomDice = nil
 	ctx.Error = nil
 	ctx.NumOpCount = 0
+	ctx.MemoryUsed = 0
 	ctx.detailCache = ""
 
 	// 开始解析，编译字节码
 	if ctx.Config.ParseExprLimit != 0 {
 		p.maxExprCnt = ctx.Config.ParseExprLimit
 	}
-	// 设置错误消息语言
-	SetParseErrorLanguage(ctx.Config.ParseErrorLanguage)
-	_, err := p.parse(nil)
+	err := parseGuarded(p)
 	if err != nil {
+		// 设置错误消息语言
+		localizeParseError(err, ctx.Config.ParseErrorLanguage)
 		ctx.Error = err
 		return err
 	}
 
 	ctx.code = p.cur.data.code
 	ctx.codeIndex = p.cur.data.codeIndex
+	if ctx.Config.EnableOptimizer {
+		ctx.code = optimizeCode(ctx.code[:ctx.codeIndex])
+		ctx.codeIndex = len(ctx.code)
+	}
 
 	return nil
 }
@@ -134,21 +175,19 @@ func (ctx *Context) RunAfterParsed() error {
 	}
 
 	// 给出VM解析完句子后的剩余文本
-	offset := ctx.parser.pt.offset
-	matched := strings.TrimRightFunc(string(ctx.parser.data[:offset]), func(r rune) bool {
+	data := ctx.sourceData()
+	offset := ctx.sourceOffset(ctx.parser.pt.offset)
+	matched := strings.TrimRightFunc(string(data[:offset]), func(r rune) bool {
 		return unicode.IsSpace(r)
 	})
 	ctx.Matched = matched
-	ctx.RestInput = string(ctx.parser.data[len(matched):])
+	ctx.RestInput = string(data[len(matched):])
 	return nil
 }
 
 // Run 执行给定语句
 func (ctx *Context) Run(value string) error {
-	if err := ctx.Parse(value); err != nil {
-		return err
-	}
-	return ctx.RunAfterParsed()
+	return ctx.RunContext(context.Background(), value)
 }
 
 type spanByBegin []BufferSpan
@@ -197,11 +236,12 @@ d10=10[d10=10=10]=10 首先进行一次省略，即文本标注如果等于值
 同理还有 [2d1,2].kl() 这个与上面等价，只是写法不同
 */
 func (ctx *Context) makeDetailStr(details []BufferSpan) string {
-	offset := ctx.parser.pt.offset
+	data := ctx.sourceData()
+	offset := ctx.sourceOffset(ctx.parser.pt.offset)
 	if ctx.Config.CustomMakeDetailFunc != nil {
-		return ctx.Config.CustomMakeDetailFunc(ctx, details, ctx.parser.data, offset)
+		return ctx.Config.CustomMakeDetailFunc(ctx, details, data, offset)
 	}
-	detailResult := ctx.parser.data[:offset]
+	detailResult := data[:offset]
 
 	curPoint := IntType(-1) // nolint
 	lastEnd := IntType(-1)  // nolint
@@ -255,7 +295,7 @@ func (ctx *Context) makeDetailStr(details []BufferSpan) string {
 				span := item.spans[j]
 				subDetail := string(detailResult[span.Begin:span.End]) + "=" + span.Ret.ToString()
 				if ctx.Config.CustomDetailSpanRewriteFunc != nil {
-					subDetail = ctx.Config.CustomDetailSpanRewriteFunc(ctx, subDetail, span, false, ctx.parser.data, offset)
+					subDetail = ctx.Config.CustomDetailSpanRewriteFunc(ctx, subDetail, span, false, data, offset)
 				}
 				if subDetail != "" {
 					subDetailParts = append(subDetailParts, subDetail)
@@ -320,10 +360,10 @@ func (ctx *Context) makeDetailStr(details []BufferSpan) string {
 		}
 
 		if ctx.Config.CustomDetailSpanRewriteFunc != nil {
-			detail = ctx.Config.CustomDetailSpanRewriteFunc(ctx, detail, last, true, ctx.parser.data, offset)
+			detail = ctx.Config.CustomDetailSpanRewriteFunc(ctx, detail, last, true, data, offset)
 		}
 		if ctx.Config.CustomDetailRewriteFunc != nil {
-			detail = ctx.Config.CustomDetailRewriteFunc(ctx, detail, last, ctx.parser.data, offset)
+			detail = ctx.Config.CustomDetailRewriteFunc(ctx, detail, last, data, offset)
 		}
 
 		writeBufStr(partRet + detail)
@@ -340,11 +380,14 @@ func (ctx *Context) makeDetailStr(details []BufferSpan) string {
 
 func (ctx *Context) evaluate() {
 	ctx.top = 0
-	ctx.stack = make([]VMValue, 1000)
+	ctx.stack = make([]VMValue, vmStackInitSize)
 	ctx.IsRunning = true
+	ctx.blockScopes = nil
 	stack := ctx.stack
+	root := ctx
 	defer func() {
-		ctx.IsRunning = false // 如果程序崩掉，不过halt
+		root.IsRunning = false // 如果程序崩掉，不过halt
+		root.blockScopes = nil
 	}()
 
 	e := ctx
@@ -353,7 +396,7 @@ func (ctx *Context) evaluate() {
 	numOpCountAdd := func(count IntType) bool {
 		e.NumOpCount += count
 		if ctx.Config.OpCountLimit > 0 && e.NumOpCount > ctx.Config.OpCountLimit {
-			ctx.Error = errors.New("允许算力上限")
+			ctx.Error = errOpCountLimit
 			return true
 		}
 		return false
@@ -418,7 +461,18 @@ func (ctx *Context) evaluate() {
 		if !ctx.forceSolveDetail && ctx.subThreadDepth != 0 {
 			return
 		}
-		sort.Sort(spanByBegin(details))
+		if ctx.sourceOffsets != nil {
+			for i := range details {
+				details[i] = ctx.sourceSpan(details[i])
+			}
+		}
+		// 多数情况下已经有序，省去排序的开销
+		for i := 1; i < len(details); i++ {
+			if details[i].Begin < details[i-1].Begin {
+				sort.Sort(spanByBegin(details))
+				break
+			}
+		}
 		ctx.DetailSpans = details
 	}
 
@@ -455,6 +509,33 @@ func (ctx *Context) evaluate() {
 		e.top += 1
 	}
 
+	// 当前调用帧在栈上的起点，脚本函数与调用方共用数据栈
+	frameBase := 0
+
+	// stackReserve 保证栈上至少还有 n 个空位，不够时扩容，超出上限时返回 false。
+	// 上限按调用帧计算，每次函数调用都可以使用 vmStackLimit 个位置
+	stackReserve := func(n int) bool {
+		need := e.top + n
+		if need <= len(e.stack) {
+			return true
+		}
+		if need-frameBase > vmStackLimit {
+			return false
+		}
+		size := len(e.stack) * 2
+		for size < need {
+			size *= 2
+		}
+		if size > frameBase+vmStackLimit {
+			size = frameBase + vmStackLimit
+		}
+		newStack := make([]VMValue, size)
+		copy(newStack, e.stack[:e.top])
+		e.stack = newStack
+		stack = newStack
+		return true
+	}
+
 	getRollMode := func() int {
 		if ctx.Config.DiceMinMode {
 			return -1
@@ -465,27 +546,297 @@ func (ctx *Context) evaluate() {
 		return 0
 	}
 
-	var fstrBlockStack [20]int
-	var fstrBlockIndex int
+	// 语句块与字符串模板块，记录进入时的栈顶。各调用帧共用，blockBase 为当前帧的起点，
+	// fstrDepth 为当前帧中字符串模板块的层数
+	type blockEntry struct {
+		top  int
+		fstr bool
+	}
+	var blocks []blockEntry
+	blockBase, fstrDepth := 0, 0
+
+	enterBlock := func(fstr bool) bool {
+		if limit := ctx.Config.MaxNestingDepth; limit > 0 && len(blocks)-blockBase >= limit {
+			if fstr {
+				ctx.Error = newRuntimeError(ErrCodeNestingDepth, "", errors.New("字符串模板嵌套层数过多"))
+			} else {
+				ctx.Error = newRuntimeError(ErrCodeNestingDepth, "", errors.New("语句块嵌套层数过多"))
+			}
+			return false
+		}
+		blocks = append(blocks, blockEntry{top: e.top, fstr: fstr})
+		if fstr {
+			fstrDepth += 1
+		} else {
+			ctx.blockScopes = append(ctx.blockScopes, nil)
+		}
+		return true
+	}
+
+	// truncateBlocks 离开当前帧中第 n 层以内的语句块
+	truncateBlocks := func(n int) {
+		for len(blocks) > n {
+			if blocks[len(blocks)-1].fstr {
+				fstrDepth -= 1
+			} else if k := len(ctx.blockScopes); k > 0 {
+				ctx.blockScopes = ctx.blockScopes[:k-1]
+			}
+			blocks = blocks[:len(blocks)-1]
+		}
+	}
+
+	// 复合赋值的运算部分，与二元运算符共用实现
+	inplaceOp := func(op CodeType, cur *VMValue, rhs *VMValue) *VMValue {
+		ret := binOperator[op-typeAdd](cur, ctx, rhs)
+		if ctx.Error == nil && ret == nil {
+			opCode := ByteCode{T: op}
+			ctx.Error = newRuntimeError(ErrCodeTypeMismatch, opSymbol(op),
+				fmt.Errorf("这两种类型无法使用 %s 算符连接: %s, %s", opCode.CodeString(), cur.GetTypeName(), rhs.GetTypeName()), cur, rhs)
+		}
+		return ret
+	}
+
+	// try 语句的异常处理器，出错时跳转到最近的 catch 处
+	type tryHandler struct {
+		begin          int // try.begin 指令位置
+		catchIndex     int // catch 代码起点
+		top            int
+		blocks         int
+		diceStateIndex int
+		detailsLen     int
+	}
+	var tryStack []tryHandler
+
+	// 尝试捕获当前错误，成功时返回 catch 代码起点
+	// located 为 true 时出错位置已记录在 ctx.Error 中
+	catchError := func(failedIndex int, located bool) (int, bool) {
+		if !isCatchableError(ctx.Error) {
+			return 0, false
+		}
+		for len(tryStack) > 0 {
+			h := tryStack[len(tryStack)-1]
+			tryStack = tryStack[:len(tryStack)-1]
+			// 通过 break/continue 离开的 try 块不会执行 try.end，在这里丢弃
+			if failedIndex <= h.begin || failedIndex >= h.catchIndex {
+				continue
+			}
+
+			var pos *VMValue
+			var te *ThrowError
+			var re *RuntimeError
+			isThrow := errors.As(ctx.Error, &te)
+			if isThrow && te.ctx == ctx {
+				pos = NewArrayVal(NewIntVal(te.Span.Begin), NewIntVal(te.Span.End))
+			} else if located && errors.As(ctx.Error, &re) {
+				// 出错的算式、下标或调用处
+				pos = NewArrayVal(NewIntVal(re.Span.Begin), NewIntVal(re.Span.End))
+			} else if len(details) > h.detailsLen {
+				last := ctx.sourceSpan(details[len(details)-1])
+				pos = NewArrayVal(NewIntVal(last.Begin), NewIntVal(last.End))
+			} else {
+				pos = NewNullVal()
+			}
+			errVal := NewNullVal()
+			if isThrow {
+				errVal = te.Value
+			}
+			errDict := NewDictValWithArrayMust(
+				NewStrVal("msg"), NewStrVal(ctx.Error.Error()),
+				NewStrVal("value"), errVal,
+				NewStrVal("pos"), pos,
+			)
+
+			// 丢弃出错时尚未完成的计算过程
+			kept := details[:h.detailsLen]
+			for _, i := range details[h.detailsLen:] {
+				if i.Ret != nil {
+					kept = append(kept, i)
+				}
+			}
+			details = kept
+
+			e.top = h.top
+			truncateBlocks(h.blocks)
+			diceStateIndex = h.diceStateIndex
+			ctx.Error = nil
+			stackPush(errDict.V())
+			return h.catchIndex, true
+		}
+		return 0, false
+	}
+
+	// errorSpan 出错的位置: 出错指令记录的调用处或算符位置、本层 throw 语句，或是本层最近的计算过程标记
+	errorSpan := func(failedIndex int, detailsLen int) (BufferSpan, bool) {
+		var span BufferSpan
+		known := false
+		if failedIndex >= 0 && failedIndex < e.codeIndex {
+			switch code := &e.code[failedIndex]; code.T {
+			case typeInvoke, typeInvokeNamed, typeItemGet, typeAttrGet:
+				if span, known = code.span(); known {
+					span = ctx.callSpan(span)
+				}
+			case typeDetailMark:
+			default:
+				if span, known = code.span(); known {
+					span = ctx.sourceSpan(span)
+				}
+			}
+		}
+		var te *ThrowError
+		if !known && errors.As(ctx.Error, &te) && te.ctx == ctx {
+			span, known = te.Span, true
+		} else if !known && len(details) > detailsLen {
+			span, known = ctx.sourceSpan(details[len(details)-1]), true
+		}
+		return span, known
+	}
+
+	// traceError 错误离开当前函数时，在调用栈中记录出错或发起调用的位置
+	traceError := func(failedIndex int, detailsLen int) {
+		ctx.addStackFrame(errorSpan(failedIndex, detailsLen))
+	}
+
+	// 脚本函数的调用帧，保存调用方的执行状态。函数体在同一个循环中执行，不再为每次调用创建虚拟机
+	type callFrame struct {
+		ctx            *Context
+		opIndex        int // 调用指令位置
+		base           int
+		blockBase      int
+		fstrDepth      int
+		tryStack       []tryHandler
+		diceStateIndex int
+		detailsLen     int
+	}
+	var frames []callFrame
+
+	// enterFrame 进入函数，函数对象和参数已经出栈，函数体的栈从函数对象的位置开始
+	enterFrame := func(callee *Context, opIndex int) {
+		frames = append(frames, callFrame{
+			ctx:            ctx,
+			opIndex:        opIndex,
+			base:           frameBase,
+			blockBase:      blockBase,
+			fstrDepth:      fstrDepth,
+			tryStack:       tryStack,
+			diceStateIndex: diceStateIndex,
+			detailsLen:     len(details),
+		})
+		callee.stack = e.stack
+		callee.top = e.top
+		callee.IsRunning = true
+		frameBase = e.top
+		blockBase, fstrDepth, tryStack = len(blocks), 0, nil
+		ctx, e = callee, callee
+	}
+
+	// leaveFrame 回到调用方，返回调用指令的位置。函数体的计算过程不计入调用方
+	leaveFrame := func() int {
+		f := frames[len(frames)-1]
+		frames = frames[:len(frames)-1]
+		callee := ctx
+		callee.IsRunning = false
+		callee.blockScopes = nil
+
+		ctx, e = f.ctx, f.ctx
+		e.stack = callee.stack
+		e.top = frameBase
+		e.NumOpCount = callee.NumOpCount
+		e.MemoryUsed = callee.MemoryUsed
+		frameBase = f.base
+		blocks = blocks[:blockBase]
+		blockBase, fstrDepth = f.blockBase, f.fstrDepth
+		tryStack = f.tryStack
+		diceStateIndex = f.diceStateIndex
+		details = details[:f.detailsLen]
+		return f.opIndex
+	}
+
+	// returnFrame 函数正常返回，返回值为函数体栈顶的值，没有值时为 null
+	returnFrame := func() int {
+		var ret VMValue
+		if e.top > frameBase {
+			ret = e.stack[e.top-1]
+		} else {
+			ret = VMValue{TypeId: VMTypeNull}
+		}
+		opIndex := leaveFrame()
+		ctx.IsComputedLoaded = true
+		stackPush(&ret)
+		return opIndex
+	}
+
+	// invokeFunction 调用函数对象，脚本函数进入新的调用帧并返回 true，其余情况就地调用
+	invokeFunction := func(funcObj *VMValue, arr []*VMValue, names []string, opIndex int) bool {
+		if funcObj.TypeId == VMTypeFunction {
+			callee := funcObj.newCallContext(ctx, arr, names, false)
+			if callee == nil {
+				return false
+			}
+			enterFrame(callee, opIndex)
+			return true
+		}
 
-	var blockStack [20]int // TODO: 如果在while循环中return会使得 blockIndex+1，用完之后就不能用了
-	var blockIndex int
+		ret := funcObj.FuncInvokeWithNames(ctx, arr, names)
+		if ctx.Error == nil {
+			stackPush(ret)
+		}
+		return false
+	}
 
 	startTime := time.Now().UnixMilli()
-	for opIndex := 0; opIndex < e.codeIndex; opIndex += 1 {
+	cancelCountdown := 1 // 第一条指令前先检查一次，之后每隔 cancelCheckOps 条检查
+	for opIndex := 0; ; opIndex += 1 {
+		if opIndex >= e.codeIndex {
+			if len(frames) == 0 {
+				break
+			}
+			if ctx.Error == nil {
+				opIndex = returnFrame()
+				continue
+			}
+		}
+
 		numOpCountAdd(1)
 
-		if ctx.Error == nil && e.top == len(stack) {
-			ctx.Error = errors.New("执行栈到达溢出线")
+		if ctx.Error == nil && !stackReserve(1) {
+			ctx.Error = errStackOverflow
+		}
+
+		if ctx.Error == nil && ctx.runCtx != nil {
+			cancelCountdown--
+			if cancelCountdown == 0 {
+				cancelCountdown = cancelCheckOps
+				ctx.Error = ctx.checkCanceled()
+			}
 		}
 
 		if ctx.Error != nil {
-			return
+			failedIndex := opIndex - 1
+			var located bool
+			if len(frames) > 0 {
+				located = ctx.locateError(errorSpan(failedIndex, frames[len(frames)-1].detailsLen))
+			} else {
+				located = ctx.locateError(errorSpan(failedIndex, 0))
+			}
+			catchIndex, ok := catchError(failedIndex, located)
+			// 函数内未捕获的错误交给调用方处理
+			for !ok && len(frames) > 0 {
+				traceError(failedIndex, frames[len(frames)-1].detailsLen)
+				err := ctx.Error
+				failedIndex = leaveFrame()
+				ctx.Error = err
+				catchIndex, ok = catchError(failedIndex, false)
+			}
+			if !ok {
+				traceError(failedIndex, 0)
+				return
+			}
+			opIndex = catchIndex
 		}
 
-		code := e.code[opIndex]
-		cIndex := fmt.Sprintf("%d/%d", opIndex+1, e.codeIndex)
+		code := &e.code[opIndex]
 		if ctx.Config.PrintBytecode {
+			cIndex := fmt.Sprintf("%d/%d", opIndex+1, e.codeIndex)
 			var subThread string
 			if ctx.subThreadDepth != 0 {
 				subThread = fmt.Sprintf("  S%d", ctx.subThreadDepth)
@@ -508,22 +859,41 @@ func (ctx *Context) evaluate() {
 			stack[e.top].Value = s
 			e.top++
 		case typePushArray:
-			num := code.Value.(IntType)
+			num := code.Arg
+			if !ctx.allocArray(num) {
+				continue
+			}
 			stackPush(NewArrayVal(stackPopN(num)...))
 		case typePushDict:
-			num := code.Value.(IntType)
+			num := code.Arg
+			if !ctx.allocDict(num) {
+				continue
+			}
 			items := stackPopN(num * 2)
 			dict, err := NewDictValWithArray(items...)
 			if err != nil {
 				e.Error = err
-				return
+				continue
 			}
 			stackPush(dict.V())
-		case typePushComputed, typePushFunction:
+		case typePushComputed:
+			// 计算类型会在 Attrs 中缓存自己的变量，每次求值都使用新的副本，以免字节码被多个上下文共享时互相影响
+			cd, _ := code.Value.(*VMValue).ReadComputed()
+			stackPush(NewComputedValRaw(&ComputedData{Expr: cd.Expr, code: cd.code, codeIndex: cd.codeIndex}))
+		case typePushFunction:
 			val := code.Value.(*VMValue)
 			stackPush(val)
 		case typePushNull:
 			stackPush(NewNullVal())
+		case typePushBool:
+			stackPush(boolToVMValue(ctx, code.Value.(bool)))
+		case typePushBigInt:
+			i := code.Value.(*big.Int)
+			if !ctx.Config.EnableBigInt {
+				ctx.Error = fmt.Errorf("整数溢出: 数字 %s 超出整数范围", i.String())
+				continue
+			}
+			stackPush(NewBigIntVal(i))
 		case typePushThis:
 			stackPush(vmValueNewLocal())
 		// case typePushGlobal:
@@ -534,8 +904,8 @@ func (ctx *Context) evaluate() {
 			_a, ok1 := a.ReadInt()
 			_b, ok2 := b.ReadInt()
 			if !(ok1 && ok2) {
-				ctx.Error = errors.New("左右两个区间必须都是数字类型")
-				return
+				ctx.Error = newRuntimeError(ErrCodeTypeMismatch, "..", errors.New("左右两个区间必须都是数字类型"), a, b)
+				continue
 			}
 
 			step := IntType(1)
@@ -547,8 +917,11 @@ func (ctx *Context) evaluate() {
 			length += 1
 
 			if length > 512 {
-				ctx.Error = errors.New("不能一次性创建过长的数组")
-				return
+				ctx.Error = newRuntimeError(ErrCodeRangeLimit, "..", errors.New("不能一次性创建过长的数组"))
+				continue
+			}
+			if !ctx.allocArray(length) {
+				continue
 			}
 
 			arr := make([]*VMValue, length)
@@ -564,7 +937,7 @@ func (ctx *Context) evaluate() {
 		case typePushLast:
 			if lastPop == nil {
 				ctx.Error = errors.New("非法调用指令 push.last")
-				return
+				continue
 			}
 			stackPush(lastPop)
 		case typePushDefaultExpr:
@@ -595,7 +968,7 @@ func (ctx *Context) evaluate() {
 
 				v := val.FuncInvoke(ctx, nil)
 				if ctx.Error != nil {
-					return
+					continue
 				}
 				stackPush(v)
 			} else {
@@ -603,7 +976,11 @@ func (ctx *Context) evaluate() {
 			}
 
 			d := &details[len(details)-1]
-			dText := string(ctx.parser.data[d.Begin:d.End])
+			var dText string
+			// 函数体的上下文没有对应的源码
+			if ctx.parser != nil && int(d.End) <= len(ctx.parser.data) {
+				dText = string(ctx.parser.data[d.Begin:d.End])
+			}
 
 			if !regexp.MustCompile("[dD][优優劣][势勢]").MatchString(dText) {
 				s := &diceStates[diceStateIndex]
@@ -640,24 +1017,21 @@ func (ctx *Context) evaluate() {
 			}
 
 		case typeInvoke:
-			paramsNum := code.Value.(IntType)
+			paramsNum := code.Arg
 			arr := stackPopN(paramsNum)
 			funcObj := stackPop()
 
-			if funcObj.TypeId == VMTypeFunction {
-				ret := funcObj.FuncInvoke(ctx, arr)
-				if ctx.Error != nil {
-					return
-				}
-				stackPush(ret)
-			} else if funcObj.TypeId == VMTypeNativeFunction {
-				ret := funcObj.FuncInvokeNative(ctx, arr)
-				if ctx.Error != nil {
-					return
-				}
-				stackPush(ret)
-			} else {
-				ctx.Error = fmt.Errorf("类型错误: [%s]无法被调用，必须是一个函数", funcObj.ToString())
+			if invokeFunction(funcObj, arr, nil, opIndex) {
+				opIndex = -1
+			}
+
+		case typeInvokeNamed:
+			info := code.Value.(InvokeNamedInfo)
+			arr := stackPopN(info.Num)
+			funcObj := stackPop()
+
+			if invokeFunction(funcObj, arr, info.Names, opIndex) {
+				opIndex = -1
 			}
 
 		case typeItemGet:
@@ -665,7 +1039,7 @@ func (ctx *Context) evaluate() {
 			obj := stackPop()
 			ret := obj.ItemGet(ctx, itemIndex)
 			if ctx.Error != nil {
-				return
+				continue
 			}
 			if ret == nil {
 				ret = NewNullVal()
@@ -677,43 +1051,43 @@ func (ctx *Context) evaluate() {
 			obj := stackPop()       // 数组 / 对象
 			obj.ItemSet(ctx, itemIndex, val.Clone())
 			if ctx.Error != nil {
-				return
+				continue
 			}
 		case typeAttrSet:
 			attrVal, obj := stackPop2()
-			attrName := code.Value.(string)
+			attrName := code.Value.(AttrInfo).Name
 
 			ret := obj.AttrSet(ctx, attrName, attrVal.Clone())
 			if ctx.Error == nil && ret == nil {
-				ctx.Error = errors.New("不支持的类型：当前变量无法用.来设置属性")
+				ctx.Error = newRuntimeError(ErrCodeTypeMismatch, ".", errors.New("不支持的类型：当前变量无法用.来设置属性"), obj)
 			}
 			if ctx.Error != nil {
-				return
+				continue
 			}
 		case typeAttrGet:
 			obj := stackPop()
-			attrName := code.Value.(string)
+			attrName := code.Value.(AttrInfo).Name
 			ret := obj.AttrGet(ctx, attrName)
 			if ctx.Error != nil {
-				return
+				continue
 			}
 			if ret == nil {
-				ctx.Error = errors.New("不支持的类型：当前变量无法用.来取属性")
-				return
+				ctx.Error = newRuntimeError(ErrCodeTypeMismatch, ".", errors.New("不支持的类型：当前变量无法用.来取属性"), obj)
+				continue
 			}
 			stackPush(ret)
 		case typeSliceGet:
 			step := stackPop() // step
 			if step.TypeId != VMTypeNull {
 				ctx.Error = errors.New("尚不支持分片步长")
-				return
+				continue
 			}
 
 			a, b := stackPop2()
 			obj := stackPop()
 			ret := obj.GetSliceEx(ctx, a, b)
 			if ctx.Error != nil {
-				return
+				continue
 			}
 			stackPush(ret)
 		case typeSliceSet:
@@ -721,38 +1095,175 @@ func (ctx *Context) evaluate() {
 			step := stackPop() // step
 			if step.TypeId != VMTypeNull {
 				ctx.Error = errors.New("尚不支持分片步长")
-				return
+				continue
 			}
 
 			a, b := stackPop2()
 			obj := stackPop()
 			obj.SetSliceEx(ctx, a, b, val)
 			if ctx.Error != nil {
-				return
+				continue
 			}
 
-		case typeReturn:
-			solveDetail()
-			ctx.IsRunning = false
-			return
-		case typeHalt:
+		case typeStoreNameOp:
+			info := code.Value.(InplaceInfo)
+			rhs := stackPop()
+			cur := ctx.LoadName(info.Name, false, true)
+			if ctx.Error != nil {
+				continue
+			}
+			ret := inplaceOp(info.Op, cur, rhs)
+			if ctx.Error != nil {
+				continue
+			}
+			ctx.StoreName(info.Name, ret.Clone(), true)
+			if ctx.Error != nil {
+				continue
+			}
+			stackPush(ret)
+		case typeAttrSetOp:
+			info := code.Value.(InplaceInfo)
+			rhs, obj := stackPop2()
+			cur := obj.AttrGet(ctx, info.Name)
+			if ctx.Error == nil && cur == nil {
+				ctx.Error = newRuntimeError(ErrCodeTypeMismatch, ".", errors.New("不支持的类型：当前变量无法用.来取属性"), obj)
+			}
+			if ctx.Error != nil {
+				continue
+			}
+			ret := inplaceOp(info.Op, cur, rhs)
+			if ctx.Error != nil {
+				continue
+			}
+			if obj.AttrSet(ctx, info.Name, ret.Clone()) == nil && ctx.Error == nil {
+				ctx.Error = newRuntimeError(ErrCodeTypeMismatch, ".", errors.New("不支持的类型：当前变量无法用.来设置属性"), obj)
+			}
+		case typeItemSetOp:
+			info := code.Value.(InplaceInfo)
+			rhs := stackPop()
+			itemIndex := stackPop()
+			obj := stackPop()
+			cur := obj.ItemGet(ctx, itemIndex)
+			if ctx.Error != nil {
+				continue
+			}
+			if cur == nil {
+				cur = NewNullVal()
+			}
+			ret := inplaceOp(info.Op, cur, rhs)
+			if ctx.Error != nil {
+				continue
+			}
+			obj.ItemSet(ctx, itemIndex, ret.Clone())
+
+		case typeUnpackArray:
+			info := code.Value.(UnpackInfo)
+			src := &stack[e.top-1] // 被解构的值留在栈上，作为语句的结果
+			arr, ok := src.ReadArray()
+			if !ok {
+				ctx.Error = newRuntimeError(ErrCodeDestructure, "unpack", fmt.Errorf("解构失败: 需要数组，不能为 %s", src.GetTypeName()), src)
+				continue
+			}
+			length := IntType(len(arr.List))
+			if info.RestIndex == -1 && length != info.Count {
+				ctx.Error = newRuntimeError(ErrCodeDestructure, "unpack", fmt.Errorf("解构失败: 需要%d个元素，实际为%d个", info.Count, length), src)
+				continue
+			}
+			if info.RestIndex != -1 && length < info.Count-1 {
+				ctx.Error = newRuntimeError(ErrCodeDestructure, "unpack", fmt.Errorf("解构失败: 至少需要%d个元素，实际为%d个", info.Count-1, length), src)
+				continue
+			}
+			if !stackReserve(int(info.Count) + 1) {
+				ctx.Error = errStackOverflow
+				continue
+			}
+			restLen := length - info.Count + 1
+			if info.RestIndex != -1 && !ctx.allocArray(restLen) {
+				continue
+			}
+			for i := IntType(0); i < info.Count; i++ {
+				switch {
+				case i == info.RestIndex:
+					stackPush(NewArrayVal(arr.List[i : i+restLen]...))
+				case info.RestIndex != -1 && i > info.RestIndex:
+					stackPush(arr.List[i+restLen-1])
+				default:
+					stackPush(arr.List[i])
+				}
+			}
+		case typeUnpackDict:
+			info := code.Value.(UnpackInfo)
+			src := &stack[e.top-1]
+			if src.TypeId != VMTypeDict {
+				ctx.Error = newRuntimeError(ErrCodeDestructure, "unpack", fmt.Errorf("解构失败: 需要字典，不能为 %s", src.GetTypeName()), src)
+				continue
+			}
+			if !stackReserve(int(info.Count) + 1) {
+				ctx.Error = errStackOverflow
+				continue
+			}
+			d := (*VMDictValue)(src)
+			picked := func(k string) bool {
+				for _, j := range info.Keys {
+					if j == k {
+						return true
+					}
+				}
+				return false
+			}
+			for i, key := range info.Keys {
+				if IntType(i) == info.RestIndex {
+					// 剩余项: 未被取出的键组成新字典
+					var restLen IntType
+					d.Range(func(k string, v *VMValue) bool {
+						if !picked(k) {
+							restLen++
+						}
+						return true
+					})
+					if !ctx.allocDict(restLen) {
+						break
+					}
+					rest := NewDictVal(nil)
+					d.Range(func(k string, v *VMValue) bool {
+						if !picked(k) {
+							rest.Store(k, v)
+						}
+						return true
+					})
+					stackPush(rest.V())
+					continue
+				}
+				val, exists := d.Load(key)
+				if !exists {
+					ctx.Error = newRuntimeError(ErrCodeDestructure, "unpack", fmt.Errorf("解构失败: 字典中没有键 %s", key), src)
+					break
+				}
+				stackPush(val)
+			}
+
+		case typeReturn, typeHalt:
+			if len(frames) > 0 {
+				opIndex = returnFrame()
+				continue
+			}
 			solveDetail()
 			ctx.IsRunning = false
 			return
 
 		case typeLoadFormatString:
-			num := int(code.Value.(IntType))
+			num := int(code.Arg)
 
+			if e.top-num < frameBase {
+				e.Error = errors.New("E3:无效的表达式")
+				continue
+			}
 			outStr := ""
 			for index := 0; index < num; index++ {
-				var val VMValue
-				if e.top-num+index < 0 {
-					e.Error = errors.New("E3:无效的表达式")
-					return
-				} else {
-					val = stack[e.top-num+index]
-				}
-				outStr += val.ToString()
+				outStr += stack[e.top-num+index].ToString()
+			}
+			if !ctx.allocString(len(outStr)) {
+				continue
 			}
 
 			e.top -= num
@@ -775,7 +1286,7 @@ func (ctx *Context) evaluate() {
 				val = ctx.LoadName(name, isRaw, true)
 			}
 			if ctx.Error != nil {
-				return
+				continue
 			}
 
 			stackPush(val)
@@ -786,13 +1297,35 @@ func (ctx *Context) evaluate() {
 
 			ctx.StoreName(name, v, true)
 			if ctx.Error != nil {
-				return
+				continue
+			}
+
+		case typeStoreNameLet, typeStoreNameConst:
+			v := e.stack[e.top-1].Clone()
+			ctx.DeclareName(code.Value.(string), v, code.T == typeStoreNameConst)
+		case typeDeclareGlobal:
+			ctx.DeclareGlobal(code.Value.(string))
+		case typeImport:
+			info := code.Value.(ImportInfo)
+			ns := ctx.ImportModule(info.Module)
+			if ctx.Error != nil {
+				continue
+			}
+			for _, key := range info.Keys {
+				if _, ok := (*VMDictValue)(ns).Load(key); !ok {
+					ctx.Error = newRuntimeError(ErrCodeImport, "import", fmt.Errorf("导入失败: 模块 %s 中没有 %s", info.Module, key))
+					break
+				}
 			}
+			if ctx.Error != nil {
+				continue
+			}
+			stackPush(ns)
 
 		case typeJe, typeJeDup:
 			v := stackPop()
 			if v.AsBool() {
-				opIndex += int(code.Value.(IntType))
+				opIndex += int(code.Arg)
 				if code.T == typeJeDup {
 					stackPush(v)
 				}
@@ -800,14 +1333,20 @@ func (ctx *Context) evaluate() {
 		case typeJne:
 			t := stackPop()
 			if !t.AsBool() {
-				opIndex += int(code.Value.(IntType))
+				opIndex += int(code.Arg)
+			}
+		case typeJeNull:
+			if stack[e.top-1].TypeId == VMTypeNull {
+				opIndex += int(code.Arg)
 			}
 		case typeJmp:
-			opIndex += int(code.Value.(IntType))
+			opIndex += int(code.Arg)
 		case typePop:
 			stackPop()
 		case typePopN:
-			stackPopN(code.Value.(IntType))
+			stackPopN(code.Arg)
+		case typeDup:
+			stackPush(&stack[e.top-1])
 
 		case typeAdd, typeSubtract, typeMultiply, typeDivide, typeModulus, typeExponentiation, typeNullCoalescing,
 			typeCompLT, typeCompLE, typeCompEQ, typeCompNE, typeCompGE, typeCompGT,
@@ -817,12 +1356,30 @@ func (ctx *Context) evaluate() {
 			opFunc := binOperator[code.T-typeAdd]
 			ret := opFunc(v1, ctx, v2)
 			if ctx.Error == nil && ret == nil {
-				// TODO: 整理所有错误类型
-				opErr := fmt.Sprintf("这两种类型无法使用 %s 算符连接: %s, %s", code.CodeString(), v1.GetTypeName(), v2.GetTypeName())
-				ctx.Error = errors.New(opErr)
+				opErr := fmt.Errorf("这两种类型无法使用 %s 算符连接: %s, %s", code.CodeString(), v1.GetTypeName(), v2.GetTypeName())
+				ctx.Error = newRuntimeError(ErrCodeTypeMismatch, opSymbol(code.T), opErr, v1, v2)
 			}
 			if ctx.Error != nil {
-				return
+				continue
+			}
+			stackPush(ret)
+
+		case typeLogicNot:
+			v := stackPop()
+			stackPush(boolToVMValue(ctx, !v.AsBool()))
+
+		case typeIn, typeNotIn:
+			v1, v2 := stackPop2()
+			ret := v1.OpIn(ctx, v2)
+			if ctx.Error == nil && ret == nil {
+				opErr := fmt.Errorf("这两种类型无法使用 %s 算符连接: %s, %s", code.CodeString(), v1.GetTypeName(), v2.GetTypeName())
+				ctx.Error = newRuntimeError(ErrCodeTypeMismatch, opSymbol(code.T), opErr, v1, v2)
+			}
+			if ctx.Error != nil {
+				continue
+			}
+			if code.T == typeNotIn {
+				ret = boolToVMValue(ctx, !ret.AsBool())
 			}
 			stackPush(ret)
 
@@ -831,16 +1388,21 @@ func (ctx *Context) evaluate() {
 			var ret *VMValue
 			if code.T == typePositive {
 				ret = v.OpPositive()
+			} else if i, ok := v.ReadInt(); ok && i == minIntType {
+				// 负数的范围比正数大1，取反会溢出
+				ret = NewIntVal(0).OpSub(ctx, v)
+				if ctx.Error != nil {
+					continue
+				}
 			} else {
 				ret = v.OpNegation()
 			}
 			if ret == nil {
-				// TODO: 整理所有错误类型
-				opErr := fmt.Sprintf("此类型无法使用一元算符 %s: %s", code.CodeString(), v.GetTypeName())
-				ctx.Error = errors.New(opErr)
+				opErr := fmt.Errorf("此类型无法使用一元算符 %s: %s", code.CodeString(), v.GetTypeName())
+				ctx.Error = newRuntimeError(ErrCodeTypeMismatch, opSymbol(code.T), opErr, v)
 			}
 			if ctx.Error != nil {
-				return
+				continue
 			}
 			stackPush(ret)
 
@@ -850,8 +1412,8 @@ func (ctx *Context) evaluate() {
 			v := stackPop()
 			times, ok := v.ReadInt()
 			if !ok || times <= 0 {
-				ctx.Error = errors.New("骰点次数不为正整数")
-				return
+				ctx.Error = newRuntimeError(ErrCodeDiceTimes, "d", errors.New("骰点次数不为正整数"), v)
+				continue
 			}
 			diceStates[diceStateIndex].times = times
 		case typeDiceSetKeepLowNum:
@@ -887,21 +1449,25 @@ func (ctx *Context) evaluate() {
 			val := stackPop()
 			bInt, ok := val.ReadInt()
 			if !ok || bInt <= 0 {
-				ctx.Error = errors.New("骰子面数不为正整数")
-				return
+				ctx.Error = newRuntimeError(ErrCodeDiceSides, "d", errors.New("骰子面数不为正整数"), val)
+				continue
 			}
 			if ok && (diceState.isKeepLH == 1 || diceState.isKeepLH == 3) && diceState.lowNum <= 0 {
-				ctx.Error = errors.New("骰子取低个数不为正整数")
-				return
+				ctx.Error = newRuntimeError(ErrCodeDiceKeep, "d", errors.New("骰子取低个数不为正整数"))
+				continue
 			}
 			if ok && (diceState.isKeepLH == 2 || diceState.isKeepLH == 4) && diceState.highNum <= 0 {
-				ctx.Error = errors.New("骰子取高个数不为正整数")
-				return
+				ctx.Error = newRuntimeError(ErrCodeDiceKeep, "d", errors.New("骰子取高个数不为正整数"))
+				continue
 			}
 
 			numOpCountAdd(diceState.times)
 			if ctx.Error != nil {
-				return
+				continue
+			}
+			if diceState.times > 0 && bInt > maxIntType/diceState.times {
+				ctx.Error = newRuntimeError(ErrCodeIntOverflow, "d", fmt.Errorf("整数溢出: %dd%d 的结果可能超出整数范围", diceState.times, bInt), val)
+				continue
 			}
 
 			num, detail := RollCommon(ctx.RandSrc, diceState.times, bInt, diceState.min, diceState.max, diceState.isKeepLH, diceState.lowNum, diceState.highNum, getRollMode())
@@ -919,11 +1485,11 @@ func (ctx *Context) evaluate() {
 			result, detailText, err := compiled.item.fn(ctx, groups, compiled.payload)
 			if err != nil {
 				ctx.Error = err
-				return
+				continue
 			}
 			if result == nil {
 				ctx.Error = errors.New("自定义骰子回调返回 nil")
-				return
+				continue
 			}
 
 			ret := result.Clone()
@@ -952,7 +1518,7 @@ func (ctx *Context) evaluate() {
 			diceNum := t.MustReadInt()
 
 			if numOpCountAdd(diceNum) {
-				return
+				continue
 			}
 
 			isBonus := code.T == typeDiceCocBonus
@@ -992,7 +1558,7 @@ func (ctx *Context) evaluate() {
 
 			// 变量检查
 			if !wodCheck(ctx, v.MustReadInt(), wodState.pool, wodState.points, wodState.threshold) {
-				return
+				continue
 			}
 
 			num, _, _, detailText := RollWoD(ctx.RandSrc, v.MustReadInt(), wodState.pool, wodState.points, wodState.threshold, wodState.isGE, getRollMode())
@@ -1014,7 +1580,7 @@ func (ctx *Context) evaluate() {
 		case typeDiceDC:
 			v := stackPop() // 暴击值 / 也可以理解为加骰线
 			if !doubleCrossCheck(ctx, v.MustReadInt(), dcState.pool, dcState.points) {
-				return
+				continue
 			}
 			success, _, _, detailText := RollDoubleCross(nil, v.MustReadInt(), dcState.pool, dcState.points, getRollMode())
 			ret := NewIntVal(success)
@@ -1024,44 +1590,74 @@ func (ctx *Context) evaluate() {
 			stackPush(ret)
 
 		case typeBlockPush:
-			if blockIndex > 20 {
-				ctx.Error = errors.New("语句块嵌套层数过多")
-				return
+			if !enterBlock(false) {
+				continue
 			}
-			blockStack[blockIndex] = e.top
-			blockIndex += 1
 		case typeBlockPop:
-			newTop := blockStack[blockIndex-1]
-			e.top = newTop
-			blockIndex -= 1
-			if fstrBlockIndex > 0 {
+			e.top = blocks[len(blocks)-1].top
+			truncateBlocks(len(blocks) - 1)
+			if fstrDepth > 0 {
 				stackPush(NewStrVal("")) // 在fstring中返回空字符串
 			} else {
 				stackPush(NewNullVal())
 			}
 
+		case typeBlockUnwind:
+			// break/continue 跳出内层的语句块，栈顶回到所在循环的语句块起点
+			n := len(blocks) - int(code.Arg)
+			if n < blockBase {
+				n = blockBase
+			}
+			truncateBlocks(n)
+			if n > blockBase {
+				e.top = blocks[n-1].top
+			}
+
 		case typeFStringBlockPush:
-			if fstrBlockIndex > 20 {
-				ctx.Error = errors.New("字符串模板嵌套层数过多")
-				return
+			if !enterBlock(true) {
+				continue
 			}
-			fstrBlockStack[fstrBlockIndex] = e.top
-			fstrBlockIndex += 1
 		case typeFStringBlockPop:
 			// 不管栈里多少东西，一律清空
-			newTop := fstrBlockStack[fstrBlockIndex-1]
+			newTop := blocks[len(blocks)-1].top
 			var v *VMValue
 			if newTop != e.top {
 				v = stackPop()
 			}
 			e.top = newTop
-			fstrBlockIndex -= 1
+			truncateBlocks(len(blocks) - 1)
 			if v != nil {
 				stackPush(v)
 			} else {
 				stackPush(NewStrVal(""))
 			}
 
+		case typeTryBegin:
+			// 丢弃先前经由 break/continue 离开、已经失效的处理器
+			for len(tryStack) > 0 {
+				h := tryStack[len(tryStack)-1]
+				if opIndex > h.begin && opIndex < h.catchIndex {
+					break
+				}
+				tryStack = tryStack[:len(tryStack)-1]
+			}
+			tryStack = append(tryStack, tryHandler{
+				begin:          opIndex,
+				catchIndex:     opIndex + int(code.Arg) + 1,
+				top:            e.top,
+				blocks:         len(blocks),
+				diceStateIndex: diceStateIndex,
+				detailsLen:     len(details),
+			})
+		case typeTryEnd:
+			if len(tryStack) > 0 {
+				tryStack = tryStack[:len(tryStack)-1]
+			}
+		case typeThrow:
+			v := stackPop().Clone()
+			ctx.Error = &ThrowError{Value: v, Span: ctx.sourceSpan(code.Value.(BufferSpan)), ctx: ctx}
+			continue
+
 		case typeStSetName:
 			stName, stVal := stackPop2()
 			if e.Config.CallbackSt != nil {
@@ -1097,6 +1693,9 @@ func (ctx *Context) evaluate() {
 		}
 	}
 
+	if ctx.Error != nil {
+		return
+	}
 	solveDetail()
 }
 
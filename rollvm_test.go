@@ -33,6 +33,47 @@ func TestValueDefineBool(t *testing.T) {
 	simpleExecute(t, "false", ni(0))
 }
 
+func TestBoolType(t *testing.T) {
+	nb := NewBoolVal
+	tests := []struct {
+		expr string
+		ret  *VMValue
+	}{
+		{"true", nb(true)},
+		{"repr(false)", ns("false")},
+		{"1 < 2", nb(true)},
+		{"!1", nb(false)},
+		{"toBool(3)", nb(true)},
+		{"1 in [1]", nb(true)},
+		{"'abc'.startsWith('a')", nb(true)},
+		{"true & false", nb(false)},
+		{"true == 1", nb(true)},
+		{"true == 'true'", nb(false)},
+		{"true + 1", ni(2)},
+		{"[1 > 0, 2 > 0, 0 > 1].sum()", ni(2)},
+		{"toInt(true)", ni(1)},
+		{"`{1 > 0}`", ns("true")},
+		{"m = {(1 > 0): 'a'}; [m[1], m[true], m[2 > 1]]", na(ns("a"), ns("a"), ns("a"))},
+		{"m = {1: 'a', 0: 'b'}; m[0 > 1]", ns("b")},
+		{"m = {true: 'a', false:'b'}; [m[1], m[false]]", na(ns("a"), ns("b"))},
+		{"trueX = 2; m = {trueX: 'a'}; m.keys()", na(ns("2"))},
+	}
+	for _, i := range tests {
+		vm := NewVM()
+		vm.Config.EnableBoolType = true
+		err := vm.Run(i.expr)
+		if assert.NoError(t, err, i.expr) {
+			assert.True(t, valueEqual(vm.Ret, i.ret), i.expr)
+		}
+	}
+
+	// 未开启时保持旧行为
+	simpleExecute(t, "1 < 2", ni(1))
+	simpleExecute(t, "toBool(3)", ni(1))
+	simpleExecute(t, "repr(true)", ns("1"))
+	simpleExecute(t, "m = {true: 'a'}; m[1]", ns("a"))
+}
+
 func TestValueDefineNumber(t *testing.T) {
 	simpleExecute(t, "123", ni(123))
 	simpleExecute(t, "1.2", nf(1.2))
@@ -599,6 +640,258 @@ func TestWhileContinueBreak(t *testing.T) {
 	}
 }
 
+func TestWhileBlockUnwind(t *testing.T) {
+	// break/continue 跳出多层语句块后栈与作用域保持平衡，循环次数超过栈的上限也不会溢出
+	simpleExecute(t, "i = 0; while i < 3000 { i += 1; if i > 0 { if 1 { continue } } }; i", ni(3000))
+	simpleExecute(t, "i = 0; while 1 { i += 1; match i { 30 => { if 1 { break } }, _ => 0 } }; i", ni(30))
+	simpleExecute(t, "i = 0; s = ''; while i < 3 { i += 1; s += `{if i == 2 { continue }; i}` }; s", ns("13"))
+	simpleExecute(t, "i = 0; while 1 { i += 1; try { if i > 2 { break } } catch { 0 } }; i", ni(3))
+	simpleExecute(t, "i = 0; while i < 3 { i += 1; if 1 { let x = i; continue } }; x", NewNullVal())
+	simpleExecute(t, "s = 0; i = 0; while i < 3 { i += 1; j = 0; while 1 { j += 1; if j > i { break } }; s += j }; s", ni(9))
+	simpleExecute(t, "func f(n) { while 1 { if 1 { if n > 0 { return n } } } }; i = 0; s = 0; while i < 100 { i += 1; s += f(i) }; s", ni(5050))
+
+	// 不再有固定的嵌套层数限制
+	expr := "a = 0; " + strings.Repeat("if 1 { ", 50) + "a = 5" + strings.Repeat(" }", 50) + "; a"
+	simpleExecute(t, expr, ni(5))
+	fstr := "1"
+	for i := 0; i < 30; i++ {
+		fstr = "`{" + fstr + "}`"
+	}
+	simpleExecute(t, fstr, ns("1"))
+
+	vm := NewVM()
+	vm.Config.MaxNestingDepth = 10
+	err := vm.Run(expr)
+	if assert.Error(t, err) {
+		assert.Equal(t, "语句块嵌套层数过多", err.Error())
+	}
+	err = vm.Run(fstr)
+	if assert.Error(t, err) {
+		assert.Equal(t, "字符串模板嵌套层数过多", err.Error())
+	}
+	// 按每层函数调用分别计算
+	err = vm.Run("func f(n) { if 1 { if 1 { n > 0 ? f(n - 1) : 0 } } }; f(20)")
+	assert.NoError(t, err)
+}
+
+func TestTryCatch(t *testing.T) {
+	vm := NewVM()
+	err := vm.Run("a = 1; try { a = toInt('abc') } catch (e) { a = 10 }; a")
+	if assert.NoError(t, err) {
+		assert.True(t, valueEqual(vm.Ret, ni(10)))
+	}
+
+	vm = NewVM()
+	err = vm.Run("try { 1 / 0 } catch (e) { m = e.msg; p = e.pos }; m")
+	if assert.NoError(t, err) {
+		assert.True(t, valueEqual(vm.Ret, ns("被除数为0")))
+		assert.True(t, vmValueEqual(vm, "p", na(ni(6), ni(11))))
+	}
+
+	vm = NewVM()
+	err = vm.Run("x = [1]; try { x[3] } catch (e) { p = e.pos }; p")
+	if assert.NoError(t, err) {
+		assert.True(t, valueEqual(vm.Ret, na(ni(15), ni(19))))
+	}
+
+	vm = NewVM()
+	err = vm.Run("try { 1 + toInt('abc') } catch (e) { p = e.pos }; p")
+	if assert.NoError(t, err) {
+		assert.True(t, valueEqual(vm.Ret, na(ni(10), ni(22))))
+	}
+
+	vm = NewVM()
+	err = vm.Run("a = 1; try { a = 2 } catch { a = 3 }; a")
+	if assert.NoError(t, err) {
+		assert.True(t, valueEqual(vm.Ret, ni(2)))
+	}
+
+	// catch 中的错误交由外层处理
+	vm = NewVM()
+	err = vm.Run("try { try { 1 / 0 } catch (e) { throw 'inner' } } catch (e) { a = e.msg }; a")
+	if assert.NoError(t, err) {
+		assert.True(t, valueEqual(vm.Ret, ns("inner")))
+	}
+
+	vm = NewVM()
+	err = vm.Run("try { 1 } catch e { 2 }")
+	assert.Error(t, err)
+}
+
+func TestTryCatchThrow(t *testing.T) {
+	vm := NewVM()
+	err := vm.Run("try { throw [1, 2] } catch (e) { a = e.value; p = e.pos }; a")
+	if assert.NoError(t, err) {
+		assert.True(t, valueEqual(vm.Ret, na(ni(1), ni(2))))
+		assert.True(t, vmValueEqual(vm, "p", na(ni(6), ni(18))))
+	}
+
+	// 函数中抛出的错误可以在调用处捕获
+	vm = NewVM()
+	err = vm.Run("func f(n) { if n > 1 { throw n }; n }; try { f(5) } catch (e) { a = e.value * 2 }; a")
+	if assert.NoError(t, err) {
+		assert.True(t, valueEqual(vm.Ret, ni(10)))
+	}
+
+	vm = NewVM()
+	err = vm.Run("throw 'abc'")
+	var te *ThrowError
+	if assert.ErrorAs(t, err, &te) {
+		assert.Equal(t, "abc", err.Error())
+		assert.True(t, valueEqual(te.Value, ns("abc")))
+	}
+
+	// 通过 continue 离开 try 块之后，其 catch 不再生效
+	vm = NewVM()
+	err = vm.Run("a = 0; b = 0; while a < 3 { a = a + 1; try { if a < 3 { continue }; throw a } catch (e) { b = e.value } }; b")
+	if assert.NoError(t, err) {
+		assert.True(t, valueEqual(vm.Ret, ni(3)))
+	}
+
+	vm = NewVM()
+	err = vm.Run("while 1 { try { break } catch (e) { 0 } }; 1 / 0")
+	assert.Error(t, err)
+}
+
+func TestTryCatchUncatchable(t *testing.T) {
+	vm := NewVM()
+	vm.Config.OpCountLimit = 30000
+	err := vm.Run("try { while 1 {} } catch (e) { 1 }")
+	if assert.Error(t, err) {
+		assert.Equal(t, "允许算力上限", err.Error())
+	}
+
+	vm = NewVM()
+	vm.Config.OpCountLimit = 30000
+	err = vm.Run("func f() { f() }; try { f() } catch (e) { 1 }")
+	if assert.Error(t, err) {
+		assert.Equal(t, "允许算力上限", err.Error())
+	}
+}
+
+func TestMatch(t *testing.T) {
+	expr := "match x { 1..5 => '大成功', 6 => '极难成功', [96, 97, 98, 99, 100] => '大失败', _ => '普通' }"
+	for _, i := range []struct {
+		x   IntType
+		ret string
+	}{{1, "大成功"}, {5, "大成功"}, {6, "极难成功"}, {98, "大失败"}, {50, "普通"}} {
+		vm := NewVM()
+		vm.Attrs.Store("x", ni(i.x))
+		err := vm.Run(expr)
+		if assert.NoError(t, err) {
+			assert.True(t, valueEqual(vm.Ret, ns(i.ret)), i.x)
+		}
+	}
+
+	simpleExecute(t, "match 12 { n if n > 10 => n * 2, _ => 0 }", ni(24))
+	simpleExecute(t, "match 3 { 1..5 if 0 => 'a', [3] if 1 => 'b' }", ns("b"))
+	simpleExecute(t, "match 'abc' { 'abc' => { a = 1 } }; a", ni(1))
+	simpleExecute(t, "match 3 {\n 1..2 => 'a'\n 3 => 'b'\n}", ns("b"))
+	simpleExecute(t, "match 3 { 3 => match 5 { 5 => 'n' } }", ns("n"))
+	simpleExecute(t, "match 10 { [7, 8, 9] => 'x' }", NewNullVal())
+	simpleExecute(t, "match = 3; match", ni(3))
+
+	// 作为表达式使用
+	simpleExecute(t, "x = match 2 { 1 => 'a', 2 => 'b' }; x", ns("b"))
+	simpleExecute(t, "[match 1 { 1 => 'a' }, 1 + match 5 { _ => 10 }]", na(ns("a"), ni(11)))
+
+	// 分支体为 return/break/continue
+	simpleExecute(t, "func f(v) { match v { 1 => return 'one', _ => 'other' }; 'after' }; [f(1), f(2)]", na(ns("one"), ns("after")))
+	simpleExecute(t, "i = 0; n = 0; while i < 5 { i += 1; match i { 2 => continue, 4 => break }; n += 1 }; [i, n]", na(ni(4), ni(2)))
+	simpleExecute(t, "i = 0; while 1 { i += 1; x = match i { 3 => break, _ => i } }; i", ni(3))
+
+	vm := NewVM()
+	err := vm.Run("match 3 { 1 = 2 }")
+	assert.Error(t, err)
+
+	vm = NewVM()
+	err = vm.Run("match 1 { 1 => break }")
+	assert.Error(t, err)
+}
+
+func TestLogicNot(t *testing.T) {
+	simpleExecute(t, "!0", ni(1))
+	simpleExecute(t, "!'abc'", ni(0))
+	simpleExecute(t, "!!3", ni(1))
+	simpleExecute(t, "!1 + 1", ni(1))
+	simpleExecute(t, "a = 0; !(a > 1)", ni(1))
+	simpleExecute(t, "1 != 2", ni(1))
+}
+
+func TestOpIn(t *testing.T) {
+	simpleExecute(t, "2 in [1, 2, 3]", ni(1))
+	simpleExecute(t, "2.0 in [1, 2, 3]", ni(1))
+	simpleExecute(t, "4 in [1, 2, 3]", ni(0))
+	simpleExecute(t, "4 not in [1, 2, 3]", ni(1))
+	simpleExecute(t, "'a' in {'a': 1}", ni(1))
+	simpleExecute(t, "'b' not in {'a': 1}", ni(1))
+	simpleExecute(t, "'bc' in 'abc'", ni(1))
+	simpleExecute(t, "index = 1; index", ni(1))
+
+	vm := NewVM()
+	err := vm.Run("1 in 'abc'")
+	assert.Error(t, err)
+
+	vm = NewVM()
+	err = vm.Run("1 in 2")
+	if assert.Error(t, err) {
+		assert.Equal(t, "这两种类型无法使用 in 算符连接: int, int", err.Error())
+	}
+}
+
+func TestOptionalChaining(t *testing.T) {
+	simpleExecute(t, "a = null; a?.b", NewNullVal())
+	simpleExecute(t, "a = null; a?.b.c.d", NewNullVal())
+	simpleExecute(t, "a = {'b': {'c': 3}}; a?.b?.c", ni(3))
+	simpleExecute(t, "a = null; a?.[0]", NewNullVal())
+	simpleExecute(t, "a = [1, 2]; a?.[1]", ni(2))
+	simpleExecute(t, "f = null; f?.(1, 2)", NewNullVal())
+	simpleExecute(t, "o = {'f': null}; o.f?.()", NewNullVal())
+	simpleExecute(t, "a = null; (a)?.b", NewNullVal())
+	simpleExecute(t, "a = null; a?.b ?? 5", ni(5))
+	simpleExecute(t, "a = {'b': 2}; a?.b ?? 5", ni(2))
+	// 短路时不会对下标和参数求值
+	simpleExecute(t, "n = 0; a = null; a?.[n += 1]; n", ni(0))
+	simpleExecute(t, "n = 0; f = null; f?.(n += 1); n", ni(0))
+	// 与三目运算符不冲突
+	simpleExecute(t, "x = 1; x ?.5 : 2", nf(0.5))
+
+	vm := NewVM()
+	err := vm.Run("a = null; a.b")
+	assert.Error(t, err)
+}
+
+func TestAssignOperator(t *testing.T) {
+	simpleExecute(t, "a = 1; a += 2; a", ni(3))
+	simpleExecute(t, "a = 10; a -= 4", ni(6))
+	simpleExecute(t, "a = 3; a *= 1.5; a", nf(4.5))
+	simpleExecute(t, "a = 7; a /= 2; a", ni(3))
+	simpleExecute(t, "a = 7; a %= 4; a", ni(3))
+	simpleExecute(t, "a ??= 5; a", ni(5))
+	simpleExecute(t, "a = 3; a ??= 5; a", ni(3))
+	simpleExecute(t, "s = 'a'; s += 'b'; s", ns("ab"))
+	simpleExecute(t, "o = {'hp': 10}; o.hp -= 3; o.hp", ni(7))
+	simpleExecute(t, "arr = [1, 2]; arr[1] *= 10; arr", na(ni(1), ni(20)))
+	simpleExecute(t, "o = {'hp': 10}; o['hp'] += 1; o.hp", ni(11))
+
+	vm := NewVM()
+	err := vm.Run("a = 5; a /= 0")
+	assert.Error(t, err)
+
+	vm = NewVM()
+	err = vm.Run("a *= 2")
+	if assert.Error(t, err) {
+		assert.Equal(t, "这两种类型无法使用 mul 算符连接: null, int", err.Error())
+	}
+
+	vm = NewVM()
+	err = vm.Run("hp = 10; hp -= 2d1")
+	if assert.NoError(t, err) {
+		assert.True(t, valueEqual(vm.Ret, ni(8)))
+		assert.Equal(t, "hp = 10; hp -= 2[2d1=1+1]", vm.GetDetailText())
+	}
+}
+
 func TestLineBreak(t *testing.T) {
 	vm := NewVM()
 	err := vm.Run("if 1 {} 2")
@@ -997,47 +1290,182 @@ fib(11)
 	}
 }
 
+func TestFunctionDefaultParams(t *testing.T) {
+	simpleExecute(t, "func f(a, b = 10) { a + b }; f(1)", ni(11))
+	simpleExecute(t, "func f(a, b = 10) { a + b }; f(1, 2)", ni(3))
+	simpleExecute(t, "func f(a, b = a * 2) { a + b }; f(3)", ni(9))
+	simpleExecute(t, "func f(a, b = 2d1) { b }; f(1)", ni(2))
+	simpleExecute(t, "func f(a, b = 1) { a + b }; g = f; g(1)", ni(2))
+
+	vm := NewVM()
+	err := vm.Run("func f(a, b = 1) { a + b }; f()")
+	if assert.Error(t, err) {
+		assert.Equal(t, "缺少参数: f() 需要参数 a", err.Error())
+	}
+}
+
+func TestFunctionRestParams(t *testing.T) {
+	simpleExecute(t, "func f(a, ...rest) { rest }; f(1, 2, 3)", na(ni(2), ni(3)))
+	simpleExecute(t, "func f(a, ...rest) { rest }; f(1)", na())
+	simpleExecute(t, "func f(...rest) { rest.len() }; f(1, 2)", ni(2))
+
+	vm := NewVM()
+	err := vm.Run("func f(...r, a) { a }")
+	assert.Error(t, err)
+
+	vm = NewVM()
+	err = vm.Run("func f(a) { a }; f(1, 2)")
+	if assert.Error(t, err) {
+		assert.Equal(t, "参数过多: f() 最多接受1个参数，传入2个", err.Error())
+	}
+}
+
+func TestFunctionNamedArgs(t *testing.T) {
+	simpleExecute(t, "func f(a, b = 2, c = 3) { [a, b, c] }; f(1, c: 9)", na(ni(1), ni(2), ni(9)))
+	simpleExecute(t, "func f(a, b) { [a, b] }; f(b: 1, a: 2)", na(ni(2), ni(1)))
+	simpleExecute(t, "func f(a, b = 10) { a + b }; f(f(1), b: f(2, b: 0))", ni(13))
+	// 冒号后没有空白时是带冒号的变量名
+	simpleExecute(t, "a:b = 3; func f(a, b = 1) { [a, b] }; f(a:b)", na(ni(3), ni(1)))
+	simpleExecute(t, "a:b = 3; func f(a, b = 1) { [a, b] }; f(b:\n 2, a: a:b)", na(ni(3), ni(2)))
+
+	vm := NewVM()
+	err := vm.Run("func f(a) { a }; f(x: 1)")
+	if assert.Error(t, err) {
+		assert.Equal(t, "未知的参数名: f() 没有名为 x 的参数", err.Error())
+	}
+
+	vm = NewVM()
+	err = vm.Run("func f(a) { a }; f(1, a: 2)")
+	if assert.Error(t, err) {
+		assert.Equal(t, "参数重复: f() 的参数 a 被多次赋值", err.Error())
+	}
+
+	vm = NewVM()
+	err = vm.Run("func f(a, b) { a }; f(a: 1, 2)")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "位置参数不能放在命名参数之后")
+	}
+}
+
+func TestNativeFunctionNamedArgs(t *testing.T) {
+	simpleExecute(t, "'a,b'.split(sep: ',')", na(ns("a"), ns("b")))
+	simpleExecute(t, "'ab'.padStart(fill: '-', length: 4)", ns("--ab"))
+
+	vm := NewVM()
+	err := vm.Run("abs()")
+	if assert.Error(t, err) {
+		assert.Equal(t, "缺少参数: abs() 需要参数 value", err.Error())
+	}
+
+	// 原生函数同样支持剩余参数
+	vm = NewVM()
+	vm.Attrs.Store("sum", NewNativeFunctionVal(&NativeFunctionData{
+		Name:   "sum",
+		Params: []string{"first", "...rest"},
+		NativeFunc: func(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
+			ret := params[0].MustReadInt()
+			arr, _ := params[1].ReadArray()
+			for _, i := range arr.List {
+				ret += i.MustReadInt()
+			}
+			return ni(ret)
+		},
+	}))
+	err = vm.Run("sum(1, 2, 3)")
+	if assert.NoError(t, err) {
+		assert.True(t, valueEqual(vm.Ret, ni(6)))
+	}
+}
+
+func TestFunctionCallFrame(t *testing.T) {
+	simpleExecute(t, "func sum(n) { if n == 0 { return 0 }; return n + sum(n - 1) }; sum(300)", ni(45150))
+	simpleExecute(t, "func f() {}; f()", NewNullVal())
+	simpleExecute(t, "func f(a) { while 1 { a += 1; if a > 3 { return a } } }; [f(1), f(5)]", na(ni(4), ni(6)))
+	simpleExecute(t, "func f(a) { a * 2 }; i = 0; s = 0; while i < 3 { i += 1; s += f(i) }; s", ni(12))
+	simpleExecute(t, "func f(a) { a * 2 }; `{f(1)}x{f(2)}`", ns("2x4"))
+	simpleExecute(t, "func f(a, b = a + 1) { [a, b] }; f(f(1)[1])", na(ni(2), ni(3)))
+	simpleExecute(t, "func f() { d1 + 1 }; f() + 1d1", ni(3))
+	simpleExecute(t, "func f() { d }; f() >= 1", ni(1))
+
+	// 函数内的变量按调用链向上查找
+	simpleExecute(t, "func g() { x }; func f() { x = 5; g() }; f()", ni(5))
+	simpleExecute(t, "x = 1; func f() { x }; f()", ni(1))
+	simpleExecute(t, "func f(n) { n }; func g(n) { f(n + 1) + n }; g(1)", ni(3))
+
+	// 函数内未捕获的错误由调用方处理
+	simpleExecute(t, "func f() { 1 / 0 }; try { f() } catch (e) { m = e.msg }; m", ns("被除数为0"))
+	simpleExecute(t, "func f() { throw 'x' }; func g() { f() + 1 }; try { g() } catch (e) { v = e.value }; v", ns("x"))
+	simpleExecute(t, "func f() { try { 1 / 0 } catch { return 2 } }; f() + 1", ni(3))
+	simpleExecute(t, "func f(n) { if n == 0 { throw n }; f(n - 1) }; try { f(5) } catch (e) { 1 }; [1, 2][1]", ni(2))
+
+	vm := NewVM()
+	err := vm.Run("func f() { g() }; func g() { 2 / 0 }; f()")
+	if assert.Error(t, err) {
+		assert.Equal(t, "被除数为0", err.Error())
+	}
+
+	// 原生函数回调脚本函数
+	vm = NewVM()
+	vm.Attrs.Store("apply", NewNativeFunctionVal(&NativeFunctionData{
+		Name:   "apply",
+		Params: []string{"fn", "value"},
+		NativeFunc: func(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
+			return params[0].FuncInvoke(ctx, []*VMValue{params[1]})
+		},
+	}))
+	err = vm.Run("func f(x) { x * 2 }; func g(x) { apply(f, x) + 1 }; g(3)")
+	if assert.NoError(t, err) {
+		assert.True(t, valueEqual(vm.Ret, ni(7)))
+	}
+
+	// 无限递归受算力上限限制
+	vm = NewVM()
+	vm.Config.OpCountLimit = 30000
+	err = vm.Run("func f() { f() }; f()")
+	assert.ErrorIs(t, err, errOpCountLimit)
+}
+
 func TestBytecodeToString(t *testing.T) {
 	ops := []ByteCode{
-		{typePushIntNumber, IntType(1)},
-		{typePushFloatNumber, float64(1.2)},
-		{typePushString, "abc"},
-
-		{typeAdd, nil},
-		{typeSubtract, nil},
-		{typeMultiply, nil},
-		{typeDivide, nil},
-		{typeModulus, nil},
-		{typeExponentiation, nil},
-		{typeNullCoalescing, nil},
-
-		{typeCompLT, nil},
-		{typeCompLE, nil},
-		{typeCompEQ, nil},
-		{typeCompNE, nil},
-		{typeCompGE, nil},
-		{typeCompGT, nil},
-
-		{typeLogicAnd, nil},
-		{typeLogicOr, nil},
-
-		{typeNop, nil},
-
-		{typeBitwiseAnd, nil},
-		{typeBitwiseOr, nil},
-
-		{typeDiceInit, nil},
-		{typeDiceSetTimes, nil},
-		{typeDiceSetKeepLowNum, nil},
-		{typeDiceSetKeepHighNum, nil},
-		{typeDiceSetDropLowNum, nil},
-		{typeDiceSetDropHighNum, nil},
-		{typeDiceSetMin, nil},
-		{typeDiceSetMax, nil},
-
-		{typeJmp, IntType(0)},
-		{typeJe, IntType(0)},
-		{typeJne, IntType(0)},
+		{T: typePushIntNumber, Value: IntType(1)},
+		{T: typePushFloatNumber, Value: float64(1.2)},
+		{T: typePushString, Value: "abc"},
+
+		{T: typeAdd},
+		{T: typeSubtract},
+		{T: typeMultiply},
+		{T: typeDivide},
+		{T: typeModulus},
+		{T: typeExponentiation},
+		{T: typeNullCoalescing},
+
+		{T: typeCompLT},
+		{T: typeCompLE},
+		{T: typeCompEQ},
+		{T: typeCompNE},
+		{T: typeCompGE},
+		{T: typeCompGT},
+
+		{T: typeLogicAnd},
+		{T: typeLogicOr},
+
+		{T: typeNop},
+
+		{T: typeBitwiseAnd},
+		{T: typeBitwiseOr},
+
+		{T: typeDiceInit},
+		{T: typeDiceSetTimes},
+		{T: typeDiceSetKeepLowNum},
+		{T: typeDiceSetKeepHighNum},
+		{T: typeDiceSetDropLowNum},
+		{T: typeDiceSetDropHighNum},
+		{T: typeDiceSetMin},
+		{T: typeDiceSetMax},
+
+		{T: typeJmp},
+		{T: typeJe},
+		{T: typeJne},
 	}
 
 	for _, i := range ops {
@@ -1532,8 +1960,14 @@ func TestLogicAnd(t *testing.T) {
 
 func TestStackOverFlow(t *testing.T) {
 	vm := NewVM()
-	err := vm.Run("while 1 { 2 }")
-	assert.Error(t, err)
+	err := vm.Run("[" + strings.Repeat("1, ", vmStackLimit) + "1]")
+	assert.ErrorIs(t, err, errStackOverflow)
+
+	// 每轮循环结束时栈顶回到循环开始的位置，不会因为循环次数多而溢出
+	vm = NewVM()
+	vm.Config.OpCountLimit = 30000
+	err = vm.Run("while 1 { 2 }")
+	assert.ErrorIs(t, err, errOpCountLimit)
 }
 
 func TestSliceUnicode(t *testing.T) {
@@ -2105,3 +2539,285 @@ func TestGetDetailBug2(t *testing.T) {
 	assert.Equal(t, vm.RestInput, "(1+1+23=3")
 	assert.Equal(t, "", vm.GetDetailText())
 }
+
+func TestDestructuringArray(t *testing.T) {
+	simpleExecute(t, "[a, b] = [1, 2]; a * 10 + b", ni(12))
+	simpleExecute(t, "[a, ...r] = [1, 2, 3]; r", na(ni(2), ni(3)))
+	simpleExecute(t, "[a, ...r, z] = [1, 2, 3, 4]; [a, r, z]", na(ni(1), na(ni(2), ni(3)), ni(4)))
+	simpleExecute(t, "[...r, z] = [1]; [r, z]", na(na(), ni(1)))
+	simpleExecute(t, "func f() { return [1, 'x'] }; [v, s] = f(); [s, v]", na(ns("x"), ni(1)))
+
+	vm := NewVM()
+	err := vm.Run("[a, b] = [1]")
+	if assert.Error(t, err) {
+		assert.Equal(t, "解构失败: 需要2个元素，实际为1个", err.Error())
+	}
+
+	vm = NewVM()
+	err = vm.Run("[a, b, ...c] = [1]")
+	if assert.Error(t, err) {
+		assert.Equal(t, "解构失败: 至少需要2个元素，实际为1个", err.Error())
+	}
+
+	vm = NewVM()
+	err = vm.Run("[a, b] = 1")
+	if assert.Error(t, err) {
+		assert.Equal(t, "解构失败: 需要数组，不能为 int", err.Error())
+	}
+
+	vm = NewVM()
+	err = vm.Run("[...a, ...b] = [1]")
+	assert.Error(t, err)
+}
+
+func TestDestructuringDict(t *testing.T) {
+	simpleExecute(t, "{hp, mp} = {'hp': 10, 'mp': 5}; hp - mp", ni(5))
+	simpleExecute(t, "{hp: h} = {'hp': 10}; h", ni(10))
+	simpleExecute(t, "{hp, ...other} = {'hp': 10, 'mp': 5}; other.mp", ni(5))
+	simpleExecute(t, "{hp, ...other} = {'hp': 10}; other.len()", ni(0))
+
+	vm := NewVM()
+	err := vm.Run("{hp, mp} = {'hp': 10}")
+	if assert.Error(t, err) {
+		assert.Equal(t, "解构失败: 字典中没有键 mp", err.Error())
+	}
+
+	vm = NewVM()
+	err = vm.Run("{hp} = [1]")
+	if assert.Error(t, err) {
+		assert.Equal(t, "解构失败: 需要字典，不能为 array", err.Error())
+	}
+}
+
+func TestMultipleAssign(t *testing.T) {
+	simpleExecute(t, "a = 1; b = 2; a, b = b, a; [a, b]", na(ni(2), ni(1)))
+	simpleExecute(t, "a, b = [5, 6]; a + b", ni(11))
+	simpleExecute(t, "a, b, c = 1, 2, 3; [c, b, a]", na(ni(3), ni(2), ni(1)))
+}
+
+func TestLetBlockScope(t *testing.T) {
+	simpleExecute(t, "a = 1; if 1 { let a = 2; a = 3 }; a", ni(1))
+	simpleExecute(t, "r = 0; if 1 { let a = 2; r = a + 1 }; r", ni(3))
+	simpleExecute(t, "if 1 { let b = 2 }; b", NewNullVal())
+	simpleExecute(t, "r = 0; if 1 { let a = 1; if 1 { a = 2; let a = 5 }; r = a }; r", ni(2))
+	simpleExecute(t, "let x; x", NewNullVal())
+	simpleExecute(t, "try { if 1 { let q = 1; throw 1 } } catch { }; q", NewNullVal())
+}
+
+func TestConst(t *testing.T) {
+	simpleExecute(t, "const c = 1; c + 1", ni(2))
+	simpleExecute(t, "i = 0; while i < 3 { const c = i; i += 1 }; i", ni(3))
+
+	for _, expr := range []string{"const c = 1; c = 2", "const c = 1; c += 2", "if 1 { const c = 1; c = 2 }", "const c = 1; [c] = [2]"} {
+		vm := NewVM()
+		err := vm.Run(expr)
+		if assert.Error(t, err, expr) {
+			assert.Equal(t, "常量 c 不能被重新赋值", err.Error())
+		}
+	}
+
+	vm := NewVM()
+	err := vm.Run("const c")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "const 声明必须赋初值")
+	}
+}
+
+func TestGlobalStatement(t *testing.T) {
+	globals := map[string]*VMValue{}
+	newVM := func() *Context {
+		vm := NewVM()
+		vm.GlobalValueStoreFunc = func(name string, v *VMValue) { globals[name] = v }
+		vm.GlobalValueLoadFunc = func(name string) *VMValue { return globals[name] }
+		return vm
+	}
+
+	vm := newVM()
+	err := vm.Run("global hp; hp = 5; hp")
+	if assert.NoError(t, err) {
+		assert.True(t, valueEqual(vm.Ret, ni(5)))
+		assert.True(t, valueEqual(globals["hp"], ni(5)))
+	}
+
+	vm = newVM()
+	err = vm.Run("hp = 1; global hp, mp; [hp, mp]")
+	if assert.NoError(t, err) {
+		assert.True(t, valueEqual(vm.Ret, na(ni(5), NewNullVal())))
+	}
+
+	vm = newVM()
+	err = vm.Run("global mp = 3; if 1 { let mp = 0 }; mp")
+	if assert.NoError(t, err) {
+		assert.True(t, valueEqual(vm.Ret, ni(3)))
+		assert.True(t, valueEqual(globals["mp"], ni(3)))
+	}
+}
+
+func TestIntOverflow(t *testing.T) {
+	simpleExecute(t, "2^62", ni(4611686018427387904))
+	simpleExecute(t, "3^39", ni(4052555153018976267))
+	simpleExecute(t, "-9223372036854775807 - 1", ni(-9223372036854775807-1))
+
+	tests := map[string]string{
+		"2^80":                      "整数溢出: 2 ^ 80 的结果超出整数范围",
+		"9223372036854775807 + 1":   "整数溢出: 9223372036854775807 + 1 的结果超出整数范围",
+		"4611686018427387904 * 2":   "整数溢出: 4611686018427387904 * 2 的结果超出整数范围",
+		"99999999999999999999":      "整数溢出: 数字 99999999999999999999 超出整数范围",
+		"-(-9223372036854775807-1)": "整数溢出: 0 - -9223372036854775808 的结果超出整数范围",
+		"3d9223372036854775807":     "整数溢出: 3d9223372036854775807 的结果可能超出整数范围",
+	}
+	for expr, msg := range tests {
+		vm := NewVM()
+		err := vm.Run(expr)
+		if assert.Error(t, err, expr) {
+			assert.Equal(t, msg, err.Error())
+		}
+	}
+}
+
+func TestBigInt(t *testing.T) {
+	tests := map[string]string{
+		"2^80":                    "1208925819614629174706176",
+		"9223372036854775807 + 1": "9223372036854775808",
+		"-(2^70)":                 "-1180591620717411303424",
+		"2^80 / 2^79":             "2",
+		"2^80 % 7":                "4",
+		"99999999999999999999 - 99999999999999999998": "1",
+		"toInt('123456789012345678901234')":           "123456789012345678901234",
+	}
+	for expr, ret := range tests {
+		vm := NewVM()
+		vm.Config.EnableBigInt = true
+		err := vm.Run(expr)
+		if assert.NoError(t, err, expr) {
+			assert.Equal(t, ret, vm.Ret.ToString(), expr)
+		}
+	}
+
+	vm := NewVM()
+	vm.Config.EnableBigInt = true
+	err := vm.Run("2^80 / 2^79")
+	if assert.NoError(t, err) {
+		// 回到范围内时转回普通整数
+		assert.Equal(t, VMTypeInt, vm.Ret.TypeId)
+	}
+
+	vm = NewVM()
+	vm.Config.EnableBigInt = true
+	err = vm.Run("[2^80 == 2^80, 2^80 > 1, 2^70 + 0.5 >= 2^70]")
+	if assert.NoError(t, err) {
+		assert.True(t, valueEqual(vm.Ret, na(ni(1), ni(1), ni(1))))
+	}
+
+	vm = NewVM()
+	vm.Config.EnableBigInt = true
+	err = vm.Run("2^100000")
+	assert.Error(t, err)
+
+	// 位数估算不能溢出
+	for _, expr := range []string{"(2^64)^(2^40)", "(2^64)^(2^62)", "(-(2^64))^(2^62)"} {
+		err = vm.Run(expr)
+		assert.ErrorIs(t, err, errBigIntTooLarge, expr)
+	}
+}
+
+func TestNumericLiterals(t *testing.T) {
+	simpleExecute(t, "1e3", nf(1000))
+	simpleExecute(t, "2E+2", nf(200))
+	simpleExecute(t, "1.5e-3", nf(0.0015))
+	simpleExecute(t, ".5e1", nf(5))
+	simpleExecute(t, "0x1F", ni(31))
+	simpleExecute(t, "0XfF", ni(255))
+	simpleExecute(t, "0b1010", ni(10))
+	simpleExecute(t, "1_000_000", ni(1000000))
+	simpleExecute(t, "0xffff_ffff", ni(4294967295))
+	simpleExecute(t, "3.141_592", nf(3.141592))
+	simpleExecute(t, "1_0d1", ni(10))
+	simpleExecute(t, "x = 1; x ?.5 : 2", nf(0.5))
+
+	vm := NewVM()
+	err := vm.Run("0x8000000000000000")
+	if assert.Error(t, err) {
+		assert.Equal(t, "整数溢出: 数字 9223372036854775808 超出整数范围", err.Error())
+	}
+
+	vm = NewVM()
+	vm.Config.EnableBigInt = true
+	err = vm.Run("0x1_0000_0000_0000_0000")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "18446744073709551616", vm.Ret.ToString())
+	}
+}
+
+func TestNumericRepr(t *testing.T) {
+	tests := map[string]string{
+		"1000.0":   "1000.0",
+		"2.5":      "2.5",
+		"1e21":     "1e21",
+		"1.5e-7":   "1.5e-7",
+		"0.000001": "0.000001",
+		"-0.0":     "-0.0",
+		"0x10":     "16",
+		"[1.0, 2]": "[1.0, 2]",
+	}
+	for expr, repr := range tests {
+		vm := NewVM()
+		err := vm.Run(expr)
+		if assert.NoError(t, err, expr) {
+			assert.Equal(t, repr, vm.Ret.ToRepr(), expr)
+		}
+
+		// 规范写法重新解析后得到相同的值
+		vm2 := NewVM()
+		err = vm2.Run(repr)
+		if assert.NoError(t, err, repr) {
+			assert.True(t, valueEqual(vm.Ret, vm2.Ret), repr)
+		}
+	}
+}
+
+func TestChineseKeywords(t *testing.T) {
+	tests := map[string]*VMValue{
+		"a = 0; 如果 1 { a = 2 } 否则 { a = 3 }; a":                         ni(2),
+		"a = 0; 如果 0 { a = 1 } 否则 如果 1 { a = 2 }; a":                    ni(2),
+		"x = 0; 当 x < 5 { x = x + 1 }; x":                               ni(5),
+		"函数 f(a) { 返回 a * 2 }; f(3)":                                    ni(6),
+		"i = 0; 当 1 { i += 1; 如果 i > 3 { 跳出 } }; i":                     ni(4),
+		"n = 0; i = 0; 当 i < 5 { i += 1; 如果 i == 2 { 继续 }; n += i }; n": ni(13),
+		"当前 = 3; 当前":                                                    ni(3),
+		"a = 0; if 1 { a = 1 } 否则 { a = 2 }; a":                         ni(1),
+	}
+	for expr, ret := range tests {
+		vm := NewVM()
+		vm.Config.EnableChineseKeywords = true
+		err := vm.Run(expr)
+		if assert.NoError(t, err, expr) {
+			assert.True(t, valueEqual(vm.Ret, ret), expr)
+		}
+	}
+
+	// 报错时使用作者的写法
+	vm := NewVM()
+	vm.Config.EnableChineseKeywords = true
+	err := vm.Run("跳出")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "`跳出` is not allowed outside loop.")
+	}
+
+	vm = NewVM()
+	vm.Config.EnableChineseKeywords = true
+	err = vm.Run("如果 1")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "不符合如果语法: 如果 expr {...} [否则 {...}]")
+	}
+
+	vm = NewVM()
+	vm.Config.EnableChineseKeywords = true
+	err = vm.Run("如果 = 1")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "使用关键字作为变量名")
+	}
+
+	// 未开启时仍可作为变量名
+	simpleExecute(t, "如果 = 1; 当 = 2; 如果 + 当", ni(3))
+}
@@ -0,0 +1,117 @@
+package dicescript
+
+import "errors"
+
+// RuntimeErrorCode 运行时错误码，取值保持稳定，宿主可以据此翻译报错或分类处理
+type RuntimeErrorCode string
+
+const (
+	ErrCodeUnknown        RuntimeErrorCode = "unknown"          // 未分类的错误
+	ErrCodeTypeMismatch   RuntimeErrorCode = "type_mismatch"    // 算符不支持这些类型
+	ErrCodeDivideByZero   RuntimeErrorCode = "divide_by_zero"   // 除数或模数为0
+	ErrCodeIntOverflow    RuntimeErrorCode = "int_overflow"     // 整数溢出
+	ErrCodeDiceTimes      RuntimeErrorCode = "dice_times"       // 骰点次数不为正整数
+	ErrCodeDiceSides      RuntimeErrorCode = "dice_sides"       // 骰子面数不为正整数
+	ErrCodeDiceKeep       RuntimeErrorCode = "dice_keep"        // 取高、取低个数不为正整数
+	ErrCodeIndex          RuntimeErrorCode = "index"            // 下标类型错误、越界或不支持取下标
+	ErrCodeNotCallable    RuntimeErrorCode = "not_callable"     // 调用的不是函数
+	ErrCodeArgument       RuntimeErrorCode = "argument"         // 参数过多、缺少参数或参数名错误
+	ErrCodeDestructure    RuntimeErrorCode = "destructure"      // 解构赋值的形状不符
+	ErrCodeImport         RuntimeErrorCode = "import"           // 模块无法加载或缺少导入的名字
+	ErrCodeNativeFunction RuntimeErrorCode = "native_function"  // 原生函数报错
+	ErrCodeRangeLimit     RuntimeErrorCode = "range_limit"      // 区间 [a..b] 过长
+	ErrCodeThrow          RuntimeErrorCode = "throw"            // 脚本中 throw 抛出的错误
+	ErrCodeNestingDepth   RuntimeErrorCode = "nesting_depth"    // 语句块或字符串模板嵌套过深
+	ErrCodeOpCountLimit   RuntimeErrorCode = "op_count_limit"   // 超出算力上限
+	ErrCodeMemoryLimit    RuntimeErrorCode = "memory_limit"     // 超出内存上限
+	ErrCodeStackOverflow  RuntimeErrorCode = "stack_overflow"   // 执行栈溢出
+	ErrCodeCallDepthLimit RuntimeErrorCode = "call_depth_limit" // 超出调用层数上限
+	ErrCodeCanceled       RuntimeErrorCode = "canceled"         // 执行被取消或超时
+)
+
+// RuntimeError 执行期间的错误。Run/Exec 返回的运行时错误都可以用 errors.As 取得，
+// 原始错误保存在 Err 中，Error() 与原始错误相同
+type RuntimeError struct {
+	Code     RuntimeErrorCode
+	Op       string     // 出错的操作，如 "+"、"d"、"call"，未知时为空
+	Operands []string   // 操作数的类型名，如 ["int", "str"]
+	Span     BufferSpan // 出错位置，如出错的算符连同操作数、下标或调用处，相对于出错所在代码(函数体或输入)的原文
+	Text     string     // Span 对应的代码，位置未知时为空
+	Err      error
+
+	located bool // 已经确定过位置，传回调用方时不再重新定位
+}
+
+func (e *RuntimeError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RuntimeError) Unwrap() error {
+	return e.Err
+}
+
+func newRuntimeError(code RuntimeErrorCode, op string, err error, operands ...*VMValue) *RuntimeError {
+	e := &RuntimeError{Code: code, Op: op, Err: err}
+	if len(operands) > 0 {
+		e.Operands = make([]string, len(operands))
+		for i, v := range operands {
+			e.Operands[i] = v.GetTypeName()
+		}
+	}
+	return e
+}
+
+// errorCode 未标明错误码的错误按已知的错误类型归类
+func errorCode(err error) RuntimeErrorCode {
+	var te *ThrowError
+	switch {
+	case errors.As(err, &te):
+		return ErrCodeThrow
+	case errors.Is(err, errOpCountLimit):
+		return ErrCodeOpCountLimit
+	case errors.Is(err, errMemoryLimit):
+		return ErrCodeMemoryLimit
+	case errors.Is(err, errStackOverflow):
+		return ErrCodeStackOverflow
+	case errors.Is(err, errCallDepthLimit):
+		return ErrCodeCallDepthLimit
+	case errors.Is(err, ErrCanceled):
+		return ErrCodeCanceled
+	}
+	return ErrCodeUnknown
+}
+
+// locateError 确保 ctx.Error 中带有 RuntimeError，并在首次经过时记下出错位置。
+// 返回错误是否在 ctx 中出现并确定了位置，来自其他上下文的错误位置不能用于 ctx 的源码
+func (ctx *Context) locateError(span BufferSpan, known bool) bool {
+	var re *RuntimeError
+	if !errors.As(ctx.Error, &re) {
+		re = &RuntimeError{Code: errorCode(ctx.Error), Err: ctx.Error}
+		ctx.Error = re
+	}
+	if re.located {
+		return false
+	}
+	re.located = true
+	text, ok := ctx.spanText(span, known)
+	if ok {
+		re.Span, re.Text = BufferSpan{Begin: span.Begin, End: span.End}, text
+	}
+	return ok
+}
+
+var opSymbols = map[CodeType]string{
+	typeAdd: "+", typeSubtract: "-", typeMultiply: "*", typeDivide: "/", typeModulus: "%", typeExponentiation: "^",
+	typeNullCoalescing: "??", typeBitwiseAnd: "&", typeBitwiseOr: "|",
+	typeCompLT: "<", typeCompLE: "<=", typeCompEQ: "==", typeCompNE: "!=", typeCompGE: ">=", typeCompGT: ">",
+	typeNegation: "-", typePositive: "+", typeIn: "in", typeNotIn: "not in",
+}
+
+// opSymbol 算符指令在源码中的写法，与整数溢出等报错中的写法一致
+func opSymbol(t CodeType) string {
+	if s, ok := opSymbols[t]; ok {
+		return s
+	}
+	code := ByteCode{T: t}
+	return code.CodeString()
+}
@@ -0,0 +1,166 @@
+package dicescript
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuntimeError(t *testing.T) {
+	vm := NewVM()
+	var re *RuntimeError
+
+	err := vm.Run("1 + d0")
+	if assert.True(t, errors.As(err, &re)) {
+		assert.Equal(t, ErrCodeDiceSides, re.Code)
+		assert.Equal(t, "d", re.Op)
+		assert.Equal(t, []string{"int"}, re.Operands)
+		assert.Equal(t, "d0", re.Text)
+		assert.Equal(t, BufferSpan{Begin: 4, End: 6}, re.Span)
+		assert.Equal(t, re.Err.Error(), err.Error())
+	}
+
+	err = vm.Run("1 + 'a'")
+	if assert.True(t, errors.As(err, &re)) {
+		assert.Equal(t, ErrCodeTypeMismatch, re.Code)
+		assert.Equal(t, "+", re.Op)
+		assert.Equal(t, []string{"int", "str"}, re.Operands)
+		assert.Equal(t, "1 + 'a'", re.Text)
+		assert.Equal(t, BufferSpan{Begin: 0, End: 7}, re.Span)
+	}
+
+	err = vm.Run("1 + 2 * 'a' + 3")
+	if assert.True(t, errors.As(err, &re)) {
+		assert.Equal(t, "*", re.Op)
+		assert.Equal(t, "2 * 'a'", re.Text)
+	}
+
+	err = vm.Run("x = [1]; x[5]")
+	if assert.True(t, errors.As(err, &re)) {
+		assert.Equal(t, ErrCodeIndex, re.Code)
+		assert.Equal(t, "x[5]", re.Text)
+	}
+
+	err = vm.Run("throw 1")
+	if assert.True(t, errors.As(err, &re)) {
+		assert.Equal(t, ErrCodeThrow, re.Code)
+		assert.Equal(t, "throw 1", re.Text)
+	}
+
+	// 原生函数的报错记下函数名与参数类型，位置为调用处
+	err = vm.Run("1 + toInt('x')")
+	if assert.True(t, errors.As(err, &re)) {
+		assert.Equal(t, ErrCodeNativeFunction, re.Code)
+		assert.Equal(t, "toInt", re.Op)
+		assert.Equal(t, []string{"str"}, re.Operands)
+		assert.Equal(t, "toInt('x')", re.Text)
+	}
+
+	vm.Config.OpCountLimit = 100
+	err = vm.Run("while 1 {}")
+	if assert.True(t, errors.As(err, &re)) {
+		assert.Equal(t, ErrCodeOpCountLimit, re.Code)
+	}
+	vm.Config.OpCountLimit = 0
+
+	// 解析错误不是运行时错误
+	err = vm.Run("(1 +")
+	assert.Error(t, err)
+	assert.False(t, errors.As(err, &re))
+}
+
+func TestRuntimeErrorInFunction(t *testing.T) {
+	vm := NewVM()
+	err := vm.Run("func f(x) { x / 0 }; f(1)")
+	var re *RuntimeError
+	var se *StackTraceError
+	if assert.True(t, errors.As(err, &re)) && assert.True(t, errors.As(err, &se)) {
+		assert.Equal(t, ErrCodeDivideByZero, re.Code)
+		assert.Equal(t, "/", re.Op)
+		// 位置相对于函数体
+		assert.Equal(t, "x / 0", re.Text)
+		assert.Equal(t, BufferSpan{Begin: 0, End: 5}, re.Span)
+		assert.Equal(t, "f(1)", se.Stack[len(se.Stack)-1].Code)
+	}
+}
+
+func TestRuntimeErrorStatements(t *testing.T) {
+	vm := NewVM()
+	vm.Config.ModuleLoader = func(name string) (string, error) {
+		if name == "m" {
+			return "z = 1", nil
+		}
+		return "", errors.New("no such module")
+	}
+	var re *RuntimeError
+
+	err := vm.Run("a = 1; a.b + 1")
+	if assert.True(t, errors.As(err, &re)) {
+		assert.Equal(t, ErrCodeTypeMismatch, re.Code)
+		assert.Equal(t, ".", re.Op)
+		assert.Equal(t, []string{"int"}, re.Operands)
+		assert.Equal(t, "a.b", re.Text)
+	}
+
+	err = vm.Run("a = 1; a.b = 2")
+	if assert.True(t, errors.As(err, &re)) {
+		assert.Equal(t, ErrCodeTypeMismatch, re.Code)
+		assert.Equal(t, "a.b = 2", re.Text)
+	}
+
+	err = vm.Run("a = 1; a.b += 2")
+	if assert.True(t, errors.As(err, &re)) {
+		assert.Equal(t, ErrCodeTypeMismatch, re.Code)
+		assert.Equal(t, "a.b += 2", re.Text)
+	}
+
+	err = vm.Run("x = ['a'..2]")
+	if assert.True(t, errors.As(err, &re)) {
+		assert.Equal(t, ErrCodeTypeMismatch, re.Code)
+		assert.Equal(t, "..", re.Op)
+		assert.Equal(t, []string{"str", "int"}, re.Operands)
+		assert.Equal(t, "['a'..2]", re.Text)
+	}
+
+	err = vm.Run("[1..1000]")
+	if assert.True(t, errors.As(err, &re)) {
+		assert.Equal(t, ErrCodeRangeLimit, re.Code)
+	}
+
+	err = vm.Run("x = 1; [a, b] = [1]")
+	if assert.True(t, errors.As(err, &re)) {
+		assert.Equal(t, ErrCodeDestructure, re.Code)
+		assert.Equal(t, "[a, b] = [1]", re.Text)
+		assert.Equal(t, BufferSpan{Begin: 7, End: 19}, re.Span)
+	}
+
+	err = vm.Run("{x} = {'y': 1}")
+	if assert.True(t, errors.As(err, &re)) {
+		assert.Equal(t, []string{"dict"}, re.Operands)
+		assert.Equal(t, "{x} = {'y': 1}", re.Text)
+	}
+
+	err = vm.Run("a, b = 1")
+	if assert.True(t, errors.As(err, &re)) {
+		assert.Equal(t, "a, b = 1", re.Text)
+	}
+
+	err = vm.Run("import {q} from 'm'")
+	if assert.True(t, errors.As(err, &re)) {
+		assert.Equal(t, ErrCodeImport, re.Code)
+		assert.Equal(t, "import {q} from 'm'", re.Text)
+	}
+
+	err = vm.Run("1; import 'n'")
+	if assert.True(t, errors.As(err, &re)) {
+		assert.Equal(t, ErrCodeImport, re.Code)
+		assert.Equal(t, "import 'n'", re.Text)
+	}
+
+	// 函数体中的位置相对于函数体
+	err = vm.Run("func f() { [a] = 1 }; f()")
+	if assert.True(t, errors.As(err, &re)) {
+		assert.Equal(t, "[a] = 1", re.Text)
+	}
+}
@@ -0,0 +1,137 @@
+package dicescript
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var errCallDepthLimit = errors.New("函数调用层数超出上限")
+
+// 调用栈最多记录的层数，更外层的只计数
+const maxStackFrames = 64
+
+// StackFrame 脚本调用栈中的一层
+type StackFrame struct {
+	Function string     // 函数名，顶层代码为空，没有名字的函数为 "(匿名)"
+	Span     BufferSpan // 出错或发起调用的位置，相对于函数体或顶层代码的原文，位置未知时 Code 为空
+	Code     string     // Span 对应的代码
+}
+
+func (f StackFrame) String() string {
+	name := "主程序"
+	if f.Function != "" {
+		name = "函数 " + f.Function + " "
+	}
+	if f.Code == "" {
+		return "在" + name + "中"
+	}
+	return "在" + name + "中: " + f.Code
+}
+
+// StackTraceError 从脚本函数中抛出的运行时错误，附带脚本层面的调用栈。
+// Error() 与原错误相同，原错误可以通过 errors.Is/errors.As 取得
+type StackTraceError struct {
+	Err     error
+	Stack   []StackFrame // 由内向外，最内层为出错的位置
+	Omitted int          // 超出记录上限而省略的外层层数
+}
+
+func (e *StackTraceError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *StackTraceError) Unwrap() error {
+	return e.Err
+}
+
+// StackTrace 错误信息与调用栈，每层一行
+func (e *StackTraceError) StackTrace() string {
+	var sb strings.Builder
+	sb.WriteString(e.Err.Error())
+	for _, f := range e.Stack {
+		sb.WriteString("\n  ")
+		sb.WriteString(f.String())
+	}
+	if e.Omitted > 0 {
+		fmt.Fprintf(&sb, "\n  ...省略 %d 层", e.Omitted)
+	}
+	return sb.String()
+}
+
+// checkCallDepth 进入函数或计算类型前检查嵌套层数
+func (ctx *Context) checkCallDepth() bool {
+	if ctx.Config.MaxCallDepth > 0 && ctx.subThreadDepth >= ctx.Config.MaxCallDepth {
+		ctx.Error = errCallDepthLimit
+		return false
+	}
+	return true
+}
+
+// traceSource 当前代码的原文，调用栈中的位置相对于它
+func (ctx *Context) traceSource() string {
+	if ctx.parser != nil {
+		return string(ctx.sourceData())
+	}
+	if ctx.funcData != nil {
+		return ctx.funcData.Expr
+	}
+	return ""
+}
+
+// addStackFrame 错误离开 ctx 时记录这一层。只记录脚本函数，以及错误来自函数时的顶层代码，
+// 计算类型、模块等内部上下文不单独成层
+func (ctx *Context) addStackFrame(span BufferSpan, known bool) {
+	se, traced := ctx.Error.(*StackTraceError)
+	if ctx.funcData == nil && !(traced && ctx.subThreadDepth == 0) {
+		return
+	}
+	if !traced {
+		se = &StackTraceError{Err: ctx.Error}
+		ctx.Error = se
+	}
+	if len(se.Stack) >= maxStackFrames {
+		se.Omitted++
+		return
+	}
+
+	frame := StackFrame{}
+	if ctx.funcData != nil {
+		frame.Function = ctx.funcData.Name
+		if frame.Function == "" {
+			frame.Function = "(匿名)"
+		}
+	}
+	if text, ok := ctx.spanText(span, known); ok {
+		frame.Span, frame.Code = span, text
+	}
+	se.Stack = append(se.Stack, frame)
+}
+
+// spanText 取出 span 在当前代码原文中对应的文本
+func (ctx *Context) spanText(span BufferSpan, known bool) (string, bool) {
+	src := ctx.traceSource()
+	if !known || span.Begin < 0 || span.Begin > span.End || int(span.End) > len(src) {
+		return "", false
+	}
+	return src[span.Begin:span.End], true
+}
+
+// callSpan 调用与取下标指令记录的是括号部分的位置，向前扩展到紧挨着的名字
+func (ctx *Context) callSpan(span BufferSpan) BufferSpan {
+	span = ctx.sourceSpan(span)
+	src := ctx.traceSource()
+	if int(span.Begin) > len(src) {
+		return span
+	}
+	for span.Begin > 0 {
+		r, size := utf8.DecodeLastRuneInString(src[:span.Begin])
+		if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			break
+		}
+		span.Begin -= IntType(size)
+	}
+	return span
+}
@@ -0,0 +1,78 @@
+package dicescript
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaxCallDepth(t *testing.T) {
+	vm := NewVM()
+	vm.Config.MaxCallDepth = 50
+	err := vm.Run("func f(n) { f(n + 1) }; f(0)")
+	assert.ErrorIs(t, err, errCallDepthLimit)
+
+	// 不能被 try 捕获
+	err = vm.Run("func g(n) { try { g(n + 1) } catch (e) { 0 } }; g(0)")
+	assert.ErrorIs(t, err, errCallDepthLimit)
+
+	err = vm.Run("func h(n) { if n == 0 { return 0 }; h(n - 1) + 1 }; h(40)")
+	if assert.NoError(t, err) {
+		assert.True(t, valueEqual(vm.Ret, ni(40)))
+	}
+
+	var se *StackTraceError
+	vm.Config.MaxCallDepth = 100
+	err = vm.Run("func f(n) { f(n + 1) }; f(0)")
+	if assert.True(t, errors.As(err, &se)) {
+		assert.Len(t, se.Stack, maxStackFrames)
+		assert.Equal(t, 100+1-maxStackFrames, se.Omitted)
+	}
+}
+
+func TestStackTrace(t *testing.T) {
+	vm := NewVM()
+	err := vm.Run("func g(x) { throw x + 1 }\nfunc f(x) { g(x * 2) }\nf(1)")
+	var se *StackTraceError
+	if assert.True(t, errors.As(err, &se)) {
+		assert.Equal(t, "3", err.Error())
+		assert.Equal(t, []StackFrame{
+			{Function: "g", Span: BufferSpan{Begin: 0, End: 11}, Code: "throw x + 1"},
+			{Function: "f", Span: BufferSpan{Begin: 0, End: 8}, Code: "g(x * 2)"},
+			{Function: "", Span: BufferSpan{Begin: 49, End: 53}, Code: "f(1)"},
+		}, se.Stack)
+		assert.Equal(t, "3\n  在函数 g 中: throw x + 1\n  在函数 f 中: g(x * 2)\n  在主程序中: f(1)", se.StackTrace())
+
+		var te *ThrowError
+		assert.True(t, errors.As(err, &te))
+	}
+
+	// 外层的 try 仍能捕获，且取得 throw 的值
+	err = vm.Run("func g(x) { throw x + 1 }\ntry { g(1) } catch (e) { v = e.value }; v")
+	if assert.NoError(t, err) {
+		assert.True(t, valueEqual(vm.Ret, ni(2)))
+	}
+
+	// 命名参数调用与原生函数回调
+	vm.Attrs.Store("apply", NewNativeFunctionVal(&NativeFunctionData{
+		Name:   "apply",
+		Params: []string{"fn"},
+		NativeFunc: func(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
+			return params[0].FuncInvoke(ctx, nil)
+		},
+	}))
+	err = vm.Run("func k(a, b) { d20 + b.x }; func m() { k(1, b: 2) }; apply(m)")
+	se = nil
+	if assert.True(t, errors.As(err, &se)) && assert.Len(t, se.Stack, 3) {
+		assert.Equal(t, "k", se.Stack[0].Function)
+		assert.Equal(t, "m", se.Stack[1].Function)
+		assert.Equal(t, "k(1, b: 2)", se.Stack[1].Code)
+		assert.Equal(t, "apply(m)", se.Stack[2].Code)
+	}
+
+	// 没有经过函数的错误不附带调用栈
+	err = vm.Run("1 + [1]")
+	assert.Error(t, err)
+	assert.False(t, errors.As(err, &se))
+}
@@ -17,13 +17,16 @@
 package dicescript
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math"
+	"math/big"
 	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"golang.org/x/exp/rand"
 )
@@ -43,6 +46,8 @@ const (
 	VMTypeFunction       VMValueType = 8
 	VMTypeNativeFunction VMValueType = 9
 	VMTypeNativeObject   VMValueType = 10
+	VMTypeBool           VMValueType = 11
+	VMTypeBigInt         VMValueType = 12 // 高精度整数，仅在开启 EnableBigInt 且超出 IntType 范围时出现
 
 	// 内部对象
 	vmTypeLocal  VMValueType = 20
@@ -79,6 +84,20 @@ type RollConfig struct {
 	DisableStmts     bool // 禁用语句语法(如if while等)，仅允许表达式
 	DisableNDice     bool // 禁用Nd语法，即只能2d6这样写，不能写2d
 
+	EnableBoolType bool // 启用布尔类型，true/false 及比较、逻辑运算的结果为 bool。关闭时沿用旧行为，以 1/0 表示
+	EnableBigInt   bool // 整数运算溢出时自动转为高精度整数，关闭时溢出会报错
+
+	EnableChineseKeywords bool // 启用中文关键字别名，如 如果/否则/当/函数/返回/跳出/继续
+	EnableOptimizer       bool // 解析后优化字节码，包括常量折叠、跳转合并、删除不可达代码，不影响计算结果和计算过程
+
+	ModuleLoader ModuleLoader // import 语句的模块加载函数，返回模块源码
+
+	// 解析前对输入进行规范化，如 NormalizeFullWidth 将全角符号转为半角，为nil时不处理。
+	// 计算过程、RestInput 等仍对应用户输入的原文
+	InputNormalizer InputNormalizer
+
+	CodeCache CodeCache // 字节码缓存，编译和执行反序列化得到的计算类型、函数时，命中缓存可跳过解析
+
 	// 如果返回值为true，那么跳过剩下的储存流程。如果overwrite不为nil，对v进行覆盖。
 	// 另注: 钩子函数中含有ctx的原因是可能在函数中进行调用，此时ctx会发生变化
 	HookValueStore func(ctx *Context, name string, v *VMValue) (overwrite *VMValue, solved bool)
@@ -93,10 +112,14 @@ type RollConfig struct {
 	CustomDetailSpanRewriteFunc func(ctx *Context, defaultDetail string, detailSpan BufferSpan, isRoot bool, dataBuffer []byte, parsedOffset int) string // 自定义任意一项detail改写
 	CustomDetailRewriteFunc     func(ctx *Context, curDetail string, detailSpan BufferSpan, dataBuffer []byte, parsedOffset int) string                  // 自定义单项detail重写
 
-	ParseExprLimit               uint64   // 解析算力限制，防止构造特殊语句进行DOS攻击，0为无限，建议值1000万
-	OpCountLimit                 IntType  // 算力限制，超过这个值会报错，0为无限，建议值30000
-	DefaultDiceSideExpr          string   // 默认骰子面数
-	defaultDiceSideExprCacheFunc *VMValue // expr的缓存函数
+	ParseExprLimit               uint64        // 解析算力限制，防止构造特殊语句进行DOS攻击，0为无限，建议值1000万
+	OpCountLimit                 IntType       // 算力限制，超过这个值会报错，0为无限，建议值30000
+	MemoryLimit                  IntType       // 单次执行中新建字符串、数组与字典的内存上限(字节，估算)，0为无限，建议值1000万
+	MaxCallDepth                 int           // 函数调用与计算类型求值的嵌套层数上限，0为无限，建议值200
+	MaxNestingDepth              int           // 语句块与字符串模板的嵌套层数上限，按每层函数调用分别计算，0为无限
+	Timeout                      time.Duration // 单次执行(含解析)的时间上限，超时报错，0为无限
+	DefaultDiceSideExpr          string        // 默认骰子面数
+	defaultDiceSideExprCacheFunc *VMValue      // expr的缓存函数
 
 	PrintBytecode bool // 执行时打印字节码
 	IgnoreDiv0    bool // 当div0时暂不报错
@@ -148,6 +171,8 @@ type customDiceItem struct {
 
 type Context struct {
 	parser         *parser
+	source         []byte // 规范化之前的原文，未开启输入规范化时为nil
+	sourceOffsets  []int  // 解析文本中每个字节对应的原文偏移
 	subThreadDepth int
 	Attrs          *ValueMap
 	UpCtx          *Context
@@ -160,6 +185,7 @@ type Context struct {
 	top   int
 
 	NumOpCount IntType // 算力计数
+	MemoryUsed IntType // 内存计数，新建的字符串、数组与字典估算占用的字节数
 	// CocFlagVarPrefix string // 解析过程中出现，当VarNumber开启时有效，可以是困难极难常规大成功
 
 	Config RollConfig // 标记
@@ -186,6 +212,17 @@ type Context struct {
 	/** 全局变量 */
 	globalNames *ValueMap
 
+	// let/const 声明的块级变量，与 typeBlockPush/typeBlockPop 一一对应，未声明变量的语句块为nil
+	blockScopes []*blockScope
+	// 顶层 const 声明的变量名
+	constNames map[string]bool
+	// 已导入的模块
+	modules *moduleCache
+	// 本次执行的取消信号，不可取消时为nil，子上下文沿用外层的值
+	runCtx context.Context
+	// 正在执行的脚本函数，不在函数中时为nil
+	funcData *FunctionData
+
 	// 全局scope的写入回调
 	GlobalValueStoreFunc func(name string, v *VMValue)
 	// 全局scope的读取回调
@@ -234,6 +271,8 @@ func (ctx *Context) GetCurSeed() ([]byte, error) {
 	if ctx.RandSrc != nil {
 		return ctx.RandSrc.MarshalBinary()
 	}
+	randSourceMu.Lock()
+	defer randSourceMu.Unlock()
 	return randSource.MarshalBinary()
 }
 
@@ -241,42 +280,44 @@ func (ctx *Context) loadInnerVar(name string) *VMValue {
 	return builtinValues[name]
 }
 
-func (ctx *Context) solveLoadPostAndComputed(name string, val *VMValue, isRaw bool, detail *BufferSpan) *VMValue {
+// computeLoadedValue 计算读取到的值的真实结果
+func (ctx *Context) computeLoadedValue(val *VMValue, isRaw bool, detail *BufferSpan) *VMValue {
 	withDetail := detail != nil
-
-	// 计算真实结果
-	doCompute := func(val *VMValue) *VMValue {
-		if !isRaw && val.TypeId == VMTypeComputedValue {
-			if withDetail {
-				val = val.ComputedExecute(ctx, detail)
-			} else {
-				val = val.ComputedExecute(ctx, &BufferSpan{})
-			}
-			if ctx.Error != nil {
-				return nil
-			}
-		}
-
-		// 追加计算结果到detail
+	if !isRaw && val.TypeId == VMTypeComputedValue {
 		if withDetail {
-			detail.Ret = val
+			val = val.ComputedExecute(ctx, detail)
+		} else {
+			val = val.ComputedExecute(ctx, &BufferSpan{})
+		}
+		if ctx.Error != nil {
+			return nil
 		}
-		return val
 	}
 
-	if ctx.Config.HookValueLoadPost != nil {
-		if detail != nil {
-			oldRet := detail.Ret
-			val = ctx.Config.HookValueLoadPost(ctx, name, val, doCompute, detail)
-			if oldRet == detail.Ret {
-				// 如果ret发生变化才修改，顺便修改detail中的结果为最终结果
-				detail.Ret = val
-			}
-		} else {
-			val = ctx.Config.HookValueLoadPost(ctx, name, val, doCompute, &BufferSpan{})
+	// 追加计算结果到detail
+	if withDetail {
+		detail.Ret = val
+	}
+	return val
+}
+
+func (ctx *Context) solveLoadPostAndComputed(name string, val *VMValue, isRaw bool, detail *BufferSpan) *VMValue {
+	if ctx.Config.HookValueLoadPost == nil {
+		return ctx.computeLoadedValue(val, isRaw, detail)
+	}
+
+	doCompute := func(val *VMValue) *VMValue {
+		return ctx.computeLoadedValue(val, isRaw, detail)
+	}
+	if detail != nil {
+		oldRet := detail.Ret
+		val = ctx.Config.HookValueLoadPost(ctx, name, val, doCompute, detail)
+		if oldRet == detail.Ret {
+			// 如果ret发生变化才修改，顺便修改detail中的结果为最终结果
+			detail.Ret = val
 		}
 	} else {
-		val = doCompute(val)
+		val = ctx.Config.HookValueLoadPost(ctx, name, val, doCompute, &BufferSpan{})
 	}
 	return val
 }
@@ -327,7 +368,7 @@ func (ctx *Context) LoadNameLocalWithDetail(name string, isRaw bool, detail *Buf
 	//	return ctx.currentThis.AttrGet(ctx, name)
 	// } else {
 	// if ctx.subThreadDepth >= 1 {
-	val, exists := ctx.Attrs.Load(name)
+	val, exists := ctx.lookupLocal(name)
 	if !exists {
 		val = NewNullVal()
 	}
@@ -338,6 +379,14 @@ func (ctx *Context) LoadNameLocalWithDetail(name string, isRaw bool, detail *Buf
 	// }
 }
 
+// lookupLocal 查找当前上下文的局部变量，不经过钩子
+func (ctx *Context) lookupLocal(name string) (*VMValue, bool) {
+	if scope := ctx.lookupBlockScope(name); scope != nil {
+		return scope.vars[name], true
+	}
+	return ctx.Attrs.Load(name)
+}
+
 func (ctx *Context) LoadNameLocal(name string, isRaw bool) *VMValue {
 	return ctx.LoadNameLocalWithDetail(name, isRaw, nil)
 }
@@ -354,9 +403,26 @@ func (ctx *Context) LoadNameWithDetail(name string, isRaw bool, useHook bool, de
 		}
 	}
 
+	// 声明为global的变量跳过局部变量
+	if ctx.isGlobalName(name) && ctx.lookupBlockScope(name) == nil {
+		return ctx.LoadNameGlobalWithDetail(name, isRaw, detail)
+	}
+
 	// 先local再global
 	curCtx := ctx
 	for {
+		// 没有钩子时，未定义或为 null 的变量直接查找上一层，省去创建 null 值的开销。
+		// 函数递归调用时上下文链较长，这里是名字查找的主要开销
+		if curCtx.Config.HookValueLoadPost == nil {
+			if v, ok := curCtx.lookupLocal(name); !ok || v.TypeId == VMTypeNull {
+				if curCtx.UpCtx == nil {
+					break
+				}
+				curCtx = curCtx.UpCtx
+				continue
+			}
+		}
+
 		ret := curCtx.LoadNameLocalWithDetail(name, isRaw, detail)
 
 		if curCtx.Error != nil {
@@ -382,6 +448,20 @@ func (ctx *Context) LoadName(name string, isRaw bool, useHook bool) *VMValue {
 
 // StoreName 储存变量
 func (ctx *Context) StoreName(name string, v *VMValue, useHook bool) {
+	// let/const 声明的变量不经过钩子，避免误写宿主的属性
+	if scope := ctx.lookupBlockScope(name); scope != nil {
+		if scope.consts[name] {
+			ctx.Error = fmt.Errorf("常量 %s 不能被重新赋值", name)
+			return
+		}
+		scope.vars[name] = v
+		return
+	}
+	if ctx.constNames[name] {
+		ctx.Error = fmt.Errorf("常量 %s 不能被重新赋值", name)
+		return
+	}
+
 	if useHook && ctx.Config.HookValueStore != nil {
 		overwrite, solved := ctx.Config.HookValueStore(ctx, name, v)
 		if solved {
@@ -391,7 +471,7 @@ func (ctx *Context) StoreName(name string, v *VMValue, useHook bool) {
 			v = overwrite
 		}
 	}
-	if _, ok := ctx.globalNames.Load(name); ok {
+	if ctx.isGlobalName(name) {
 		ctx.StoreNameGlobal(name, v)
 	} else {
 		ctx.StoreNameLocal(name, v)
@@ -402,6 +482,69 @@ func (ctx *Context) StoreNameLocal(name string, v *VMValue) {
 	ctx.Attrs.Store(name, v)
 }
 
+// blockScope 语句块内 let/const 声明的变量
+type blockScope struct {
+	vars   map[string]*VMValue
+	consts map[string]bool
+}
+
+// lookupBlockScope 由内向外查找声明了 name 的语句块
+func (ctx *Context) lookupBlockScope(name string) *blockScope {
+	for i := len(ctx.blockScopes) - 1; i >= 0; i-- {
+		scope := ctx.blockScopes[i]
+		if scope != nil {
+			if _, ok := scope.vars[name]; ok {
+				return scope
+			}
+		}
+	}
+	return nil
+}
+
+// DeclareName let/const 声明变量。在语句块中声明时仅在块内可见，在顶层声明时等同于局部变量
+func (ctx *Context) DeclareName(name string, v *VMValue, isConst bool) {
+	if n := len(ctx.blockScopes); n > 0 {
+		scope := ctx.blockScopes[n-1]
+		if scope == nil {
+			scope = &blockScope{vars: map[string]*VMValue{}, consts: map[string]bool{}}
+			ctx.blockScopes[n-1] = scope
+		}
+		scope.vars[name] = v
+		scope.consts[name] = isConst
+		return
+	}
+
+	if ctx.globalNames != nil {
+		ctx.globalNames.Delete(name)
+	}
+	if isConst {
+		if ctx.constNames == nil {
+			ctx.constNames = map[string]bool{}
+		}
+		ctx.constNames[name] = true
+	} else {
+		delete(ctx.constNames, name)
+	}
+	ctx.StoreNameLocal(name, v)
+}
+
+// DeclareGlobal global 声明，此后当前上下文对 name 的读写都直接使用全局变量
+func (ctx *Context) DeclareGlobal(name string) {
+	if ctx.globalNames == nil {
+		ctx.globalNames = &ValueMap{}
+	}
+	ctx.globalNames.Store(name, NewNullVal())
+}
+
+// isGlobalName 是否已通过 global 声明，函数调用的上下文在首次声明时才创建 globalNames
+func (ctx *Context) isGlobalName(name string) bool {
+	if ctx.globalNames == nil {
+		return false
+	}
+	_, ok := ctx.globalNames.Load(name)
+	return ok
+}
+
 func (ctx *Context) StoreNameGlobal(name string, v *VMValue) {
 	storeFunc := ctx.GlobalValueStoreFunc
 	if storeFunc != nil {
@@ -465,22 +608,22 @@ type ComputedData struct {
 type FunctionData struct {
 	Expr     string
 	Name     string
-	Params   []string
-	Defaults []*VMValue
+	Params   []string   // 末位参数可以 ... 开头，用于收集多余的位置参数
+	Defaults []*VMValue // 默认值表达式(以函数形式储存)，调用时在函数作用域中求值
 
 	/* 缓存数据 */
 	Self      *VMValue // 若存在self，即为bound method
 	code      []ByteCode
 	codeIndex int
-	// ctx       *Context
+	module    *Context // 定义该函数的模块，函数体中的变量会在模块中查找
 }
 
 type NativeFunctionDef func(ctx *Context, this *VMValue, params []*VMValue) *VMValue
 
 type NativeFunctionData struct {
 	Name     string
-	Params   []string
-	Defaults []*VMValue
+	Params   []string   // 规则与 FunctionData 相同
+	Defaults []*VMValue // 默认值，为 nil 的参数必须传入
 
 	/* 缓存数据 */
 	Self       *VMValue // 若存在self，即为bound method
@@ -510,6 +653,10 @@ func (v *VMValue) AsBool() bool {
 	switch v.TypeId {
 	case VMTypeInt:
 		return v.Value != IntType(0)
+	case VMTypeBool:
+		return v.Value.(bool)
+	case VMTypeBigInt:
+		return v.Value.(*big.Int).Sign() != 0
 	case VMTypeFloat:
 		return v.Value != 0.0
 	case VMTypeString:
@@ -554,6 +701,10 @@ func (v *VMValue) toStringRaw(ri *recursionInfo) string {
 		return v.Value.(string)
 	case VMTypeNull:
 		return "null"
+	case VMTypeBool:
+		return strconv.FormatBool(v.Value.(bool))
+	case VMTypeBigInt:
+		return v.Value.(*big.Int).String()
 	case VMTypeArray:
 		// 避免循环重复
 		if _, exists := ri.exists[v.Value]; exists {
@@ -620,13 +771,34 @@ func (v *VMValue) toReprRaw(ri *recursionInfo) string {
 	case VMTypeString:
 		// TODO: 检测其中是否有"
 		return "'" + v.toStringRaw(ri) + "'"
-	case VMTypeInt, VMTypeFloat, VMTypeNull, VMTypeArray, VMTypeComputedValue, VMTypeDict, VMTypeFunction, VMTypeNativeFunction, VMTypeNativeObject:
+	case VMTypeFloat:
+		return floatRepr(v.Value.(float64))
+	case VMTypeInt, VMTypeBool, VMTypeBigInt, VMTypeNull, VMTypeArray, VMTypeComputedValue, VMTypeDict, VMTypeFunction, VMTypeNativeFunction, VMTypeNativeObject:
 		return v.toStringRaw(ri)
 	default:
 		return "<a value>"
 	}
 }
 
+// floatRepr 浮点数的规范写法，重新解析后仍是相同的浮点数：
+// 整数值保留 .0，过大或过小的数使用科学计数法，如 1.5e21、1e-7
+func floatRepr(f float64) string {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	if abs := math.Abs(f); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		s := strconv.FormatFloat(f, 'e', -1, 64)
+		mantissa, exp, _ := strings.Cut(s, "e")
+		n, _ := strconv.Atoi(exp)
+		return mantissa + "e" + strconv.Itoa(n)
+	}
+	s := strconv.FormatFloat(f, 'f', -1, 64)
+	if !strings.Contains(s, ".") {
+		s += ".0"
+	}
+	return s
+}
+
 func (v *VMValue) ToRepr() string {
 	ri := &recursionInfo{exists: map[any]bool{}}
 	return v.toReprRaw(ri)
@@ -646,6 +818,13 @@ func (v *VMValue) ReadFloat() (float64, bool) {
 	return 0, false
 }
 
+func (v *VMValue) ReadBool() (bool, bool) {
+	if v.TypeId == VMTypeBool {
+		return v.Value.(bool), true
+	}
+	return false, false
+}
+
 func (v *VMValue) ReadString() (string, bool) {
 	if v.TypeId == VMTypeString {
 		return v.Value.(string), true
@@ -726,12 +905,20 @@ func (v *VMValue) ReadNativeObjectData() (*NativeObjectData, bool) {
 }
 
 func (v *VMValue) OpAdd(ctx *Context, v2 *VMValue) *VMValue {
+	if v.TypeId == VMTypeBool || v2.TypeId == VMTypeBool {
+		return v.boolAsInt().OpAdd(ctx, v2.boolAsInt())
+	}
+	if v.TypeId == VMTypeBigInt || v2.TypeId == VMTypeBigInt {
+		return v.bigIntBinOp(ctx, "+", v2, (*VMValue).OpAdd)
+	}
 	switch v.TypeId {
 	case VMTypeInt:
 		switch v2.TypeId {
 		case VMTypeInt:
-			val := v.Value.(IntType) + v2.Value.(IntType)
-			return NewIntVal(val)
+			if val, ok := intAdd(v.Value.(IntType), v2.Value.(IntType)); ok {
+				return NewIntVal(val)
+			}
+			return intOverflow(ctx, v, "+", v2)
 		case VMTypeFloat:
 			val := float64(v.Value.(IntType)) + v2.Value.(float64)
 			return NewFloatVal(val)
@@ -748,8 +935,11 @@ func (v *VMValue) OpAdd(ctx *Context, v2 *VMValue) *VMValue {
 	case VMTypeString:
 		switch v2.TypeId {
 		case VMTypeString:
-			val := v.Value.(string) + v2.Value.(string)
-			return NewStrVal(val)
+			s1, s2 := v.Value.(string), v2.Value.(string)
+			if !ctx.allocString(len(s1) + len(s2)) {
+				return nil
+			}
+			return NewStrVal(s1 + s2)
 		}
 	case VMTypeArray:
 		switch v2.TypeId {
@@ -762,6 +952,9 @@ func (v *VMValue) OpAdd(ctx *Context, v2 *VMValue) *VMValue {
 				ctx.Error = errors.New("不能一次性创建过长的数组")
 				return nil
 			}
+			if !ctx.allocArray(IntType(length)) {
+				return nil
+			}
 
 			arrFinal := make([]*VMValue, len(arr.List)+len(arr2.List))
 			copy(arrFinal, arr.List)
@@ -776,12 +969,20 @@ func (v *VMValue) OpAdd(ctx *Context, v2 *VMValue) *VMValue {
 }
 
 func (v *VMValue) OpSub(ctx *Context, v2 *VMValue) *VMValue {
+	if v.TypeId == VMTypeBool || v2.TypeId == VMTypeBool {
+		return v.boolAsInt().OpSub(ctx, v2.boolAsInt())
+	}
+	if v.TypeId == VMTypeBigInt || v2.TypeId == VMTypeBigInt {
+		return v.bigIntBinOp(ctx, "-", v2, (*VMValue).OpSub)
+	}
 	switch v.TypeId {
 	case VMTypeInt:
 		switch v2.TypeId {
 		case VMTypeInt:
-			val := v.Value.(IntType) - v2.Value.(IntType)
-			return NewIntVal(val)
+			if val, ok := intSub(v.Value.(IntType), v2.Value.(IntType)); ok {
+				return NewIntVal(val)
+			}
+			return intOverflow(ctx, v, "-", v2)
 		case VMTypeFloat:
 			val := float64(v.Value.(IntType)) - v2.Value.(float64)
 			return NewFloatVal(val)
@@ -801,13 +1002,20 @@ func (v *VMValue) OpSub(ctx *Context, v2 *VMValue) *VMValue {
 }
 
 func (v *VMValue) OpMultiply(ctx *Context, v2 *VMValue) *VMValue {
+	if v.TypeId == VMTypeBool || v2.TypeId == VMTypeBool {
+		return v.boolAsInt().OpMultiply(ctx, v2.boolAsInt())
+	}
+	if v.TypeId == VMTypeBigInt || v2.TypeId == VMTypeBigInt {
+		return v.bigIntBinOp(ctx, "*", v2, (*VMValue).OpMultiply)
+	}
 	switch v.TypeId {
 	case VMTypeInt:
 		switch v2.TypeId {
 		case VMTypeInt:
-			// TODO: 溢出，均未考虑溢出
-			val := v.Value.(IntType) * v2.Value.(IntType)
-			return NewIntVal(val)
+			if val, ok := intMul(v.Value.(IntType), v2.Value.(IntType)); ok {
+				return NewIntVal(val)
+			}
+			return intOverflow(ctx, v, "*", v2)
 		case VMTypeFloat:
 			val := float64(v.Value.(IntType)) * v2.Value.(float64)
 			return NewFloatVal(val)
@@ -835,9 +1043,15 @@ func (v *VMValue) OpDivide(ctx *Context, v2 *VMValue) *VMValue {
 		if ctx.Config.IgnoreDiv0 {
 			return v
 		}
-		ctx.Error = errors.New("被除数为0")
+		ctx.Error = newRuntimeError(ErrCodeDivideByZero, "/", errors.New("被除数为0"), v, v2)
 		return nil
 	}
+	if v.TypeId == VMTypeBool || v2.TypeId == VMTypeBool {
+		return v.boolAsInt().OpDivide(ctx, v2.boolAsInt())
+	}
+	if v.TypeId == VMTypeBigInt || v2.TypeId == VMTypeBigInt {
+		return v.bigIntBinOp(ctx, "/", v2, (*VMValue).OpDivide)
+	}
 
 	switch v.TypeId {
 	case VMTypeInt:
@@ -846,6 +1060,9 @@ func (v *VMValue) OpDivide(ctx *Context, v2 *VMValue) *VMValue {
 			if v2.Value.(IntType) == 0 {
 				return setDivideZero()
 			}
+			if v.Value.(IntType) == minIntType && v2.Value.(IntType) == -1 {
+				return intOverflow(ctx, v, "/", v2)
+			}
 			val := v.Value.(IntType) / v2.Value.(IntType)
 			return NewIntVal(val)
 		case VMTypeFloat:
@@ -877,7 +1094,13 @@ func (v *VMValue) OpDivide(ctx *Context, v2 *VMValue) *VMValue {
 
 func (v *VMValue) OpModulus(ctx *Context, v2 *VMValue) *VMValue {
 	setDivideZero := func() {
-		ctx.Error = errors.New("被除数被0")
+		ctx.Error = newRuntimeError(ErrCodeDivideByZero, "%", errors.New("被除数被0"), v, v2)
+	}
+	if v.TypeId == VMTypeBool || v2.TypeId == VMTypeBool {
+		return v.boolAsInt().OpModulus(ctx, v2.boolAsInt())
+	}
+	if v.TypeId == VMTypeBigInt || v2.TypeId == VMTypeBigInt {
+		return v.bigIntBinOp(ctx, "%", v2, (*VMValue).OpModulus)
 	}
 
 	switch v.TypeId {
@@ -897,12 +1120,24 @@ func (v *VMValue) OpModulus(ctx *Context, v2 *VMValue) *VMValue {
 }
 
 func (v *VMValue) OpPower(ctx *Context, v2 *VMValue) *VMValue {
+	if v.TypeId == VMTypeBool || v2.TypeId == VMTypeBool {
+		return v.boolAsInt().OpPower(ctx, v2.boolAsInt())
+	}
+	if v.TypeId == VMTypeBigInt || v2.TypeId == VMTypeBigInt {
+		return v.bigIntBinOp(ctx, "^", v2, (*VMValue).OpPower)
+	}
 	switch v.TypeId {
 	case VMTypeInt:
 		switch v2.TypeId {
 		case VMTypeInt:
-			val := IntType(math.Pow(float64(v.Value.(IntType)), float64(v2.Value.(IntType))))
-			return NewIntVal(val)
+			if v2.Value.(IntType) < 0 {
+				val := IntType(math.Pow(float64(v.Value.(IntType)), float64(v2.Value.(IntType))))
+				return NewIntVal(val)
+			}
+			if val, ok := intPow(v.Value.(IntType), v2.Value.(IntType)); ok {
+				return NewIntVal(val)
+			}
+			return intOverflow(ctx, v, "^", v2)
 		case VMTypeFloat:
 			val := math.Pow(float64(v.Value.(IntType)), v2.Value.(float64))
 			return NewFloatVal(val)
@@ -929,7 +1164,11 @@ func (v *VMValue) OpNullCoalescing(ctx *Context, v2 *VMValue) *VMValue {
 	}
 }
 
-func boolToVMValue(v bool) *VMValue {
+// boolToVMValue 比较、逻辑运算的结果。未启用 EnableBoolType 时以 1/0 表示
+func boolToVMValue(ctx *Context, v bool) *VMValue {
+	if ctx != nil && ctx.Config.EnableBoolType {
+		return NewBoolVal(v)
+	}
 	var val IntType
 	if v {
 		val = 1
@@ -937,21 +1176,38 @@ func boolToVMValue(v bool) *VMValue {
 	return NewIntVal(val)
 }
 
+// boolAsInt 布尔值参与算术运算时视为 1/0，其他类型原样返回
+func (v *VMValue) boolAsInt() *VMValue {
+	if v.TypeId == VMTypeBool {
+		if v.Value.(bool) {
+			return NewIntVal(1)
+		}
+		return NewIntVal(0)
+	}
+	return v
+}
+
 func (v *VMValue) OpCompLT(ctx *Context, v2 *VMValue) *VMValue {
+	if v.TypeId == VMTypeBool || v2.TypeId == VMTypeBool {
+		return v.boolAsInt().OpCompLT(ctx, v2.boolAsInt())
+	}
+	if v.TypeId == VMTypeBigInt || v2.TypeId == VMTypeBigInt {
+		return v.bigIntBinOp(ctx, "<", v2, (*VMValue).OpCompLT)
+	}
 	switch v.TypeId {
 	case VMTypeInt:
 		switch v2.TypeId {
 		case VMTypeInt:
-			return boolToVMValue(v.Value.(IntType) < v2.Value.(IntType))
+			return boolToVMValue(ctx, v.Value.(IntType) < v2.Value.(IntType))
 		case VMTypeFloat:
-			return boolToVMValue(float64(v.Value.(IntType)) < v2.Value.(float64))
+			return boolToVMValue(ctx, float64(v.Value.(IntType)) < v2.Value.(float64))
 		}
 	case VMTypeFloat:
 		switch v2.TypeId {
 		case VMTypeInt:
-			return boolToVMValue(v.Value.(float64) < float64(v2.Value.(IntType)))
+			return boolToVMValue(ctx, v.Value.(float64) < float64(v2.Value.(IntType)))
 		case VMTypeFloat:
-			return boolToVMValue(v.Value.(float64) < v2.Value.(float64))
+			return boolToVMValue(ctx, v.Value.(float64) < v2.Value.(float64))
 		}
 	}
 
@@ -959,20 +1215,26 @@ func (v *VMValue) OpCompLT(ctx *Context, v2 *VMValue) *VMValue {
 }
 
 func (v *VMValue) OpCompLE(ctx *Context, v2 *VMValue) *VMValue {
+	if v.TypeId == VMTypeBool || v2.TypeId == VMTypeBool {
+		return v.boolAsInt().OpCompLE(ctx, v2.boolAsInt())
+	}
+	if v.TypeId == VMTypeBigInt || v2.TypeId == VMTypeBigInt {
+		return v.bigIntBinOp(ctx, "<=", v2, (*VMValue).OpCompLE)
+	}
 	switch v.TypeId {
 	case VMTypeInt:
 		switch v2.TypeId {
 		case VMTypeInt:
-			return boolToVMValue(v.Value.(IntType) <= v2.Value.(IntType))
+			return boolToVMValue(ctx, v.Value.(IntType) <= v2.Value.(IntType))
 		case VMTypeFloat:
-			return boolToVMValue(float64(v.Value.(IntType)) <= v2.Value.(float64))
+			return boolToVMValue(ctx, float64(v.Value.(IntType)) <= v2.Value.(float64))
 		}
 	case VMTypeFloat:
 		switch v2.TypeId {
 		case VMTypeInt:
-			return boolToVMValue(v.Value.(float64) <= float64(v2.Value.(IntType)))
+			return boolToVMValue(ctx, v.Value.(float64) <= float64(v2.Value.(IntType)))
 		case VMTypeFloat:
-			return boolToVMValue(v.Value.(float64) <= v2.Value.(float64))
+			return boolToVMValue(ctx, v.Value.(float64) <= v2.Value.(float64))
 		}
 	}
 
@@ -980,29 +1242,65 @@ func (v *VMValue) OpCompLE(ctx *Context, v2 *VMValue) *VMValue {
 }
 
 func (v *VMValue) OpCompEQ(ctx *Context, v2 *VMValue) *VMValue {
-	return boolToVMValue(ValueEqual(v, v2, true))
+	return boolToVMValue(ctx, ValueEqual(v, v2, true))
 }
 
 func (v *VMValue) OpCompNE(ctx *Context, v2 *VMValue) *VMValue {
 	ret := v.OpCompEQ(ctx, v2)
-	return boolToVMValue(!ret.AsBool())
+	return boolToVMValue(ctx, !ret.AsBool())
+}
+
+// OpIn 成员检测，v 为数组元素、字典键或子字符串。不支持的类型返回 nil
+func (v *VMValue) OpIn(ctx *Context, container *VMValue) *VMValue {
+	switch container.TypeId {
+	case VMTypeArray:
+		arr, _ := container.ReadArray()
+		for _, i := range arr.List {
+			if ValueEqual(v, i, true) {
+				return boolToVMValue(ctx, true)
+			}
+		}
+		return boolToVMValue(ctx, false)
+	case VMTypeDict:
+		key, err := v.AsDictKey()
+		if err != nil {
+			ctx.Error = err
+			return nil
+		}
+		_, exists := (*VMDictValue)(container).Load(key)
+		return boolToVMValue(ctx, exists)
+	case VMTypeString:
+		if v.TypeId != VMTypeString {
+			ctx.Error = fmt.Errorf("类型错误: 只能在字符串中查找字符串，不能为 %s", v.GetTypeName())
+			return nil
+		}
+		str, _ := container.ReadString()
+		return boolToVMValue(ctx, strings.Contains(str, v.Value.(string)))
+	}
+	return nil
 }
 
 func (v *VMValue) OpCompGE(ctx *Context, v2 *VMValue) *VMValue {
+	if v.TypeId == VMTypeBool || v2.TypeId == VMTypeBool {
+		return v.boolAsInt().OpCompGE(ctx, v2.boolAsInt())
+	}
+	if v.TypeId == VMTypeBigInt || v2.TypeId == VMTypeBigInt {
+		return v.bigIntBinOp(ctx, ">=", v2, (*VMValue).OpCompGE)
+	}
 	switch v.TypeId {
 	case VMTypeInt:
 		switch v2.TypeId {
 		case VMTypeInt:
-			return boolToVMValue(v.Value.(IntType) >= v2.Value.(IntType))
+			return boolToVMValue(ctx, v.Value.(IntType) >= v2.Value.(IntType))
 		case VMTypeFloat:
-			return boolToVMValue(float64(v.Value.(IntType)) >= v2.Value.(float64))
+			return boolToVMValue(ctx, float64(v.Value.(IntType)) >= v2.Value.(float64))
 		}
 	case VMTypeFloat:
 		switch v2.TypeId {
 		case VMTypeInt:
-			return boolToVMValue(v.Value.(float64) >= float64(v2.Value.(IntType)))
+			return boolToVMValue(ctx, v.Value.(float64) >= float64(v2.Value.(IntType)))
 		case VMTypeFloat:
-			return boolToVMValue(v.Value.(float64) >= v2.Value.(float64))
+			return boolToVMValue(ctx, v.Value.(float64) >= v2.Value.(float64))
 		}
 	}
 
@@ -1010,20 +1308,26 @@ func (v *VMValue) OpCompGE(ctx *Context, v2 *VMValue) *VMValue {
 }
 
 func (v *VMValue) OpCompGT(ctx *Context, v2 *VMValue) *VMValue {
+	if v.TypeId == VMTypeBool || v2.TypeId == VMTypeBool {
+		return v.boolAsInt().OpCompGT(ctx, v2.boolAsInt())
+	}
+	if v.TypeId == VMTypeBigInt || v2.TypeId == VMTypeBigInt {
+		return v.bigIntBinOp(ctx, ">", v2, (*VMValue).OpCompGT)
+	}
 	switch v.TypeId {
 	case VMTypeInt:
 		switch v2.TypeId {
 		case VMTypeInt:
-			return boolToVMValue(v.Value.(IntType) > v2.Value.(IntType))
+			return boolToVMValue(ctx, v.Value.(IntType) > v2.Value.(IntType))
 		case VMTypeFloat:
-			return boolToVMValue(float64(v.Value.(IntType)) > v2.Value.(float64))
+			return boolToVMValue(ctx, float64(v.Value.(IntType)) > v2.Value.(float64))
 		}
 	case VMTypeFloat:
 		switch v2.TypeId {
 		case VMTypeInt:
-			return boolToVMValue(v.Value.(float64) > float64(v2.Value.(IntType)))
+			return boolToVMValue(ctx, v.Value.(float64) > float64(v2.Value.(IntType)))
 		case VMTypeFloat:
-			return boolToVMValue(v.Value.(float64) > v2.Value.(float64))
+			return boolToVMValue(ctx, v.Value.(float64) > v2.Value.(float64))
 		}
 	}
 
@@ -1031,6 +1335,12 @@ func (v *VMValue) OpCompGT(ctx *Context, v2 *VMValue) *VMValue {
 }
 
 func (v *VMValue) OpBitwiseAnd(ctx *Context, v2 *VMValue) *VMValue {
+	if v.TypeId == VMTypeBool && v2.TypeId == VMTypeBool {
+		return NewBoolVal(v.Value.(bool) && v2.Value.(bool))
+	}
+	if v.TypeId == VMTypeBool || v2.TypeId == VMTypeBool {
+		return v.boolAsInt().OpBitwiseAnd(ctx, v2.boolAsInt())
+	}
 	switch v.TypeId {
 	case VMTypeInt:
 		switch v2.TypeId {
@@ -1042,6 +1352,12 @@ func (v *VMValue) OpBitwiseAnd(ctx *Context, v2 *VMValue) *VMValue {
 }
 
 func (v *VMValue) OpBitwiseOr(ctx *Context, v2 *VMValue) *VMValue {
+	if v.TypeId == VMTypeBool && v2.TypeId == VMTypeBool {
+		return NewBoolVal(v.Value.(bool) || v2.Value.(bool))
+	}
+	if v.TypeId == VMTypeBool || v2.TypeId == VMTypeBool {
+		return v.boolAsInt().OpBitwiseOr(ctx, v2.boolAsInt())
+	}
 	switch v.TypeId {
 	case VMTypeInt:
 		switch v2.TypeId {
@@ -1053,7 +1369,10 @@ func (v *VMValue) OpBitwiseOr(ctx *Context, v2 *VMValue) *VMValue {
 }
 
 func (v *VMValue) OpPositive() *VMValue {
+	v = v.boolAsInt()
 	switch v.TypeId {
+	case VMTypeBigInt:
+		return v
 	case VMTypeInt:
 		return NewIntVal(v.Value.(IntType))
 	case VMTypeFloat:
@@ -1063,7 +1382,10 @@ func (v *VMValue) OpPositive() *VMValue {
 }
 
 func (v *VMValue) OpNegation() *VMValue {
+	v = v.boolAsInt()
 	switch v.TypeId {
+	case VMTypeBigInt:
+		return NewBigIntVal(new(big.Int).Neg(v.Value.(*big.Int)))
 	case VMTypeInt:
 		return NewIntVal(-v.Value.(IntType))
 	case VMTypeFloat:
@@ -1082,8 +1404,9 @@ func (v *VMValue) AttrSet(ctx *Context, name string, val *VMValue) *VMValue {
 		cd.Attrs.Store(name, val.Clone())
 		return val
 	case VMTypeDict:
-		d := (*VMDictValue)(v)
-		d.Store(name, val)
+		if !ctx.dictStore((*VMDictValue)(v), name, val) {
+			return nil
+		}
 		return val
 	case VMTypeNativeObject:
 		od, _ := v.ReadNativeObjectData()
@@ -1179,7 +1502,7 @@ func (v *VMValue) ItemGet(ctx *Context, index *VMValue) *VMValue {
 	switch v.TypeId {
 	case VMTypeArray:
 		if index.TypeId != VMTypeInt {
-			ctx.Error = fmt.Errorf("类型错误: 数字下标必须为数字，不能为 %s", index.GetTypeName())
+			ctx.Error = newRuntimeError(ErrCodeIndex, "item", fmt.Errorf("类型错误: 数字下标必须为数字，不能为 %s", index.GetTypeName()), v, index)
 		} else {
 			return v.ArrayItemGet(ctx, index.MustReadInt())
 		}
@@ -1192,7 +1515,7 @@ func (v *VMValue) ItemGet(ctx *Context, index *VMValue) *VMValue {
 		}
 	case VMTypeString:
 		if index.TypeId != VMTypeInt {
-			ctx.Error = fmt.Errorf("类型错误: 数字下标必须为数字，不能为 %s", index.GetTypeName())
+			ctx.Error = newRuntimeError(ErrCodeIndex, "item", fmt.Errorf("类型错误: 数字下标必须为数字，不能为 %s", index.GetTypeName()), v, index)
 		} else {
 			str, _ := v.ReadString()
 			rstr := []rune(str)
@@ -1212,7 +1535,7 @@ func (v *VMValue) ItemGet(ctx *Context, index *VMValue) *VMValue {
 		return ret
 	default:
 		// case VMTypeUndefined, VMTypeNull:
-		ctx.Error = errors.New("此类型无法取下标")
+		ctx.Error = newRuntimeError(ErrCodeIndex, "item", errors.New("此类型无法取下标"), v, index)
 	}
 	return nil
 }
@@ -1221,7 +1544,7 @@ func (v *VMValue) ItemSet(ctx *Context, index *VMValue, val *VMValue) bool {
 	switch v.TypeId {
 	case VMTypeArray:
 		if index.TypeId != VMTypeInt {
-			ctx.Error = fmt.Errorf("类型错误: 数字下标必须为数字，不能为 %s", index.GetTypeName())
+			ctx.Error = newRuntimeError(ErrCodeIndex, "item", fmt.Errorf("类型错误: 数字下标必须为数字，不能为 %s", index.GetTypeName()), v, index)
 		} else {
 			return v.ArrayItemSet(ctx, index.MustReadInt(), val)
 		}
@@ -1229,8 +1552,7 @@ func (v *VMValue) ItemSet(ctx *Context, index *VMValue, val *VMValue) bool {
 		if key, err := index.AsDictKey(); err != nil {
 			ctx.Error = err
 		} else {
-			(*VMDictValue)(v).Store(key, val)
-			return true
+			return ctx.dictStore((*VMDictValue)(v), key, val)
 		}
 	case VMTypeNativeObject:
 		od, _ := v.ReadNativeObjectData()
@@ -1239,7 +1561,7 @@ func (v *VMValue) ItemSet(ctx *Context, index *VMValue, val *VMValue) bool {
 			return true
 		}
 	default:
-		ctx.Error = errors.New("此类型无法赋值下标")
+		ctx.Error = newRuntimeError(ErrCodeIndex, "item", errors.New("此类型无法赋值下标"), v, index)
 	}
 	return false
 }
@@ -1250,7 +1572,7 @@ func getRealIndex(ctx *Context, index IntType, length IntType) IntType {
 		index = length + index
 	}
 	if index >= length || index < 0 {
-		ctx.Error = errors.New("无法获取此下标")
+		ctx.Error = newRuntimeError(ErrCodeIndex, "item", errors.New("无法获取此下标"))
 	}
 	return index
 }
@@ -1287,8 +1609,14 @@ func (v *VMValue) GetSlice(ctx *Context, a IntType, b IntType, step IntType) *VM
 	case VMTypeString:
 		str, _ := v.ReadString()
 		newArr := string([]rune(str)[_a:_b])
+		if !ctx.allocString(len(newArr)) {
+			return nil
+		}
 		return NewStrVal(newArr)
 	case VMTypeArray:
+		if !ctx.allocArray(_b - _a) {
+			return nil
+		}
 		arr, _ := v.ReadArray()
 		newArr := arr.List[_a:_b]
 		return NewArrayVal(newArr...)
@@ -1421,12 +1749,18 @@ func (v *VMValue) ArrayRepeatTimesEx(ctx *Context, times *VMValue) *VMValue {
 	case VMTypeInt:
 		times, _ := times.ReadInt()
 		ad, _ := v.ReadArray()
-		length := IntType(len(ad.List)) * times
+		if times < 0 {
+			times = 0
+		}
+		length, ok := intMul(IntType(len(ad.List)), times)
 
-		if length > 512 {
+		if !ok || length > 512 {
 			ctx.Error = errors.New("不能一次性创建过长的数组")
 			return nil
 		}
+		if !ctx.allocArray(length) {
+			return nil
+		}
 
 		arr := make([]*VMValue, length)
 
@@ -1440,7 +1774,7 @@ func (v *VMValue) ArrayRepeatTimesEx(ctx *Context, times *VMValue) *VMValue {
 
 func (v *VMValue) GetTypeName() string {
 	switch v.TypeId {
-	case VMTypeInt:
+	case VMTypeInt, VMTypeBigInt:
 		return "int"
 	case VMTypeFloat:
 		return "float"
@@ -1448,10 +1782,14 @@ func (v *VMValue) GetTypeName() string {
 		return "str"
 	case VMTypeNull:
 		return "null"
+	case VMTypeBool:
+		return "bool"
 	case VMTypeComputedValue:
 		return "computed"
 	case VMTypeArray:
 		return "array"
+	case VMTypeDict:
+		return "dict"
 	case VMTypeFunction:
 		return "function"
 	case VMTypeNativeFunction:
@@ -1464,6 +1802,9 @@ func (v *VMValue) GetTypeName() string {
 
 func (v *VMValue) ComputedExecute(ctx *Context, detail *BufferSpan) *VMValue {
 	cd, _ := v.ReadComputed()
+	if !ctx.checkCallDepth() {
+		return nil
+	}
 
 	vm := NewVM()
 	vm.Config = ctx.Config
@@ -1479,19 +1820,27 @@ func (v *VMValue) ComputedExecute(ctx *Context, detail *BufferSpan) *VMValue {
 	vm.UpCtx = ctx
 	vm.NumOpCount = ctx.NumOpCount + 100
 	ctx.NumOpCount = vm.NumOpCount // 防止无限递归
+	vm.MemoryUsed = ctx.MemoryUsed
 	vm.RandSrc = ctx.RandSrc
 	vm.forceSolveDetail = true
 	vm.CustomFlag = ctx.CustomFlag
+	vm.runCtx = ctx.runCtx
+	vm.modules = ctx.getModuleCache()
 	if ctx.Config.OpCountLimit > 0 && vm.NumOpCount > vm.Config.OpCountLimit {
-		vm.Error = errors.New("允许算力上限")
+		vm.Error = errOpCountLimit
 		ctx.Error = vm.Error
 		return nil
 	}
 
 	if cd.code == nil {
-		_ = vm.Run(cd.Expr)
-		cd.code = vm.code
-		cd.codeIndex = vm.codeIndex
+		// 反序列化得到的值只有源码，编译后缓存起来
+		if prog, err := vm.compileCached(cd.Expr); err != nil {
+			vm.Error = err
+		} else {
+			cd.code = prog.code
+			cd.codeIndex = len(prog.code)
+			_ = vm.Exec(prog)
+		}
 	} else {
 		vm.code = cd.code
 		vm.codeIndex = cd.codeIndex
@@ -1518,6 +1867,7 @@ func (v *VMValue) ComputedExecute(ctx *Context, detail *BufferSpan) *VMValue {
 	}
 
 	ctx.NumOpCount = vm.NumOpCount
+	ctx.MemoryUsed = vm.MemoryUsed
 	ctx.IsComputedLoaded = true
 
 	if detail != nil {
@@ -1533,55 +1883,100 @@ func (v *VMValue) FuncInvoke(ctx *Context, params []*VMValue) *VMValue {
 }
 
 func (v *VMValue) FuncInvokeRaw(ctx *Context, params []*VMValue, useUpCtxLocal bool) *VMValue {
-	// TODO: 先复制computed代码修改，后续重构
+	return v.funcInvokeNamed(ctx, params, nil, useUpCtxLocal)
+}
 
-	vm := NewVM()
-	cd, _ := v.ReadFunctionData()
-	if useUpCtxLocal {
-		vm.Attrs = ctx.Attrs
-	} else {
-		vm.Attrs = &ValueMap{}
+// FuncInvokeWithNames 调用函数，params 末尾的 len(names) 个参数为命名参数，对脚本函数和原生函数均有效
+func (v *VMValue) FuncInvokeWithNames(ctx *Context, params []*VMValue, names []string) *VMValue {
+	switch v.TypeId {
+	case VMTypeFunction:
+		return v.funcInvokeNamed(ctx, params, names, false)
+	case VMTypeNativeFunction:
+		return v.funcInvokeNativeNamed(ctx, params, names)
 	}
+	ctx.Error = newRuntimeError(ErrCodeNotCallable, "call", fmt.Errorf("类型错误: [%s]无法被调用，必须是一个函数", v.ToString()), v)
+	return nil
+}
 
-	// 设置参数
-	if len(cd.Params) != len(params) {
-		ctx.Error = fmt.Errorf("调用参数个数与函数定义不符，需求%d，传入%d", len(cd.Params), len(params))
-		return nil
+func funcDisplayName(name string) string {
+	if name == "" {
+		return "匿名函数"
 	}
-	for index, i := range cd.Params {
-		// if index >= len(params) {
-		//	break
-		// }
-		vm.Attrs.Store(i, params[index])
+	return name + "()"
+}
+
+// funcArgsBind 将实参按参数定义排好，返回值与 params 一一对应，未传入的位置为 nil，由调用方填充默认值。
+// 以 ... 开头的末位参数收集多余的位置参数，args 末尾的 len(names) 个参数为命名参数
+func funcArgsBind(ctx *Context, funcName string, params []string, hasDefault func(i int) bool, args []*VMValue, names []string) []*VMValue {
+	restIndex := -1
+	num := len(params)
+	if num > 0 && strings.HasPrefix(params[num-1], "...") {
+		restIndex = num - 1
+		num -= 1
 	}
 
-	vm.Config = ctx.Config
-	// vm.Config.PrintBytecode = false
-	vm.GlobalValueStoreFunc = ctx.GlobalValueStoreFunc
-	vm.GlobalValueLoadFunc = ctx.GlobalValueLoadFunc
-	vm.GlobalValueLoadOverwriteFunc = ctx.GlobalValueLoadOverwriteFunc
-	vm.subThreadDepth = ctx.subThreadDepth + 1
-	vm.UpCtx = ctx
-	vm.NumOpCount = ctx.NumOpCount + 100 // 递归视为消耗 + 100
-	ctx.NumOpCount = vm.NumOpCount       // 防止无限递归
-	vm.RandSrc = ctx.RandSrc
-	vm.CustomFlag = ctx.CustomFlag
-	if ctx.Config.OpCountLimit > 0 && vm.NumOpCount > vm.Config.OpCountLimit {
-		vm.Error = errors.New("允许算力上限")
-		ctx.Error = vm.Error
+	positional := args[:len(args)-len(names)]
+	named := args[len(args)-len(names):]
+	if len(positional) > num && restIndex == -1 {
+		ctx.Error = newRuntimeError(ErrCodeArgument, "call", fmt.Errorf("参数过多: %s 最多接受%d个参数，传入%d个", funcDisplayName(funcName), num, len(positional)))
 		return nil
 	}
 
-	if cd.code == nil {
-		_ = vm.Run(cd.Expr)
-		cd.code = vm.code
-		cd.codeIndex = vm.codeIndex
-	} else {
-		vm.code = cd.code
-		vm.codeIndex = cd.codeIndex
-		vm.evaluate()
+	ret := make([]*VMValue, len(params))
+	for i := 0; i < len(positional) && i < num; i++ {
+		ret[i] = positional[i]
+	}
+	if restIndex != -1 {
+		var rest []*VMValue
+		if len(positional) > num {
+			rest = positional[num:]
+		}
+		ret[restIndex] = NewArrayVal(rest...)
 	}
 
+	for j, name := range names {
+		index := -1
+		for i := 0; i < num; i++ {
+			if params[i] == name {
+				index = i
+				break
+			}
+		}
+		if index == -1 {
+			ctx.Error = newRuntimeError(ErrCodeArgument, "call", fmt.Errorf("未知的参数名: %s 没有名为 %s 的参数", funcDisplayName(funcName), name))
+			return nil
+		}
+		if ret[index] != nil {
+			ctx.Error = newRuntimeError(ErrCodeArgument, "call", fmt.Errorf("参数重复: %s 的参数 %s 被多次赋值", funcDisplayName(funcName), name))
+			return nil
+		}
+		ret[index] = named[j]
+	}
+
+	for i := 0; i < num; i++ {
+		if ret[i] == nil && !hasDefault(i) {
+			ctx.Error = newRuntimeError(ErrCodeArgument, "call", fmt.Errorf("缺少参数: %s 需要参数 %s", funcDisplayName(funcName), params[i]))
+			return nil
+		}
+	}
+	return ret
+}
+
+// paramName 去掉剩余参数的 ... 前缀
+func paramName(name string) string {
+	return strings.TrimPrefix(name, "...")
+}
+
+func (v *VMValue) funcInvokeNamed(ctx *Context, params []*VMValue, names []string, useUpCtxLocal bool) *VMValue {
+	if err := ctx.checkCanceled(); err != nil {
+		ctx.Error = err
+		return nil
+	}
+	vm := v.newCallContext(ctx, params, names, useUpCtxLocal)
+	if vm == nil {
+		return nil
+	}
+	vm.evaluate()
 	if vm.Error != nil {
 		ctx.Error = vm.Error
 		return nil
@@ -1595,35 +1990,132 @@ func (v *VMValue) FuncInvokeRaw(ctx *Context, params []*VMValue, useUpCtxLocal b
 	}
 
 	ctx.NumOpCount = vm.NumOpCount
-	if !useUpCtxLocal {
-		vm.Attrs = &ValueMap{} // 清空
-	}
+	ctx.MemoryUsed = vm.MemoryUsed
 	ctx.IsComputedLoaded = true
 	return ret
 }
 
-func (v *VMValue) FuncInvokeNative(ctx *Context, params []*VMValue) *VMValue {
-	cd, _ := v.ReadNativeFunctionData()
+// newCallContext 创建执行脚本函数的上下文并绑定参数，出错时设置 ctx.Error 并返回 nil。
+// 只准备执行函数体所需的状态，函数体由调用方执行: 虚拟机内的调用以调用帧的形式在同一个循环中执行，
+// 宿主和原生函数发起的调用则单独执行
+func (v *VMValue) newCallContext(ctx *Context, params []*VMValue, names []string, useUpCtxLocal bool) *Context {
+	cd, _ := v.ReadFunctionData()
+	if !ctx.checkCallDepth() {
+		return nil
+	}
+	vm := &Context{funcData: cd}
+	if useUpCtxLocal {
+		vm.Attrs = ctx.Attrs
+	}
 
-	// 设置参数
-	if cd.Defaults != nil {
-		// 参数填充
-		for i := 0; i < len(cd.Defaults); i++ {
-			if cd.Defaults[i] != nil {
-				if len(params) <= i {
-					params = append(params, cd.Defaults[i])
-				}
+	hasDefault := func(i int) bool {
+		return i < len(cd.Defaults) && cd.Defaults[i] != nil
+	}
+	args := funcArgsBind(ctx, cd.Name, cd.Params, hasDefault, params, names)
+	if ctx.Error != nil {
+		return nil
+	}
+
+	vm.Config = ctx.Config
+	vm.runCtx = ctx.runCtx
+	if cd.code == nil {
+		// 反序列化得到的值只有源码，编译后缓存起来
+		prog, err := vm.compileCached(cd.Expr)
+		if err != nil {
+			ctx.Error = err
+			return nil
+		}
+		cd.code = prog.code
+		cd.codeIndex = len(prog.code)
+		vm.parser = &parser{data: prog.data}
+		vm.source = prog.source
+		vm.sourceOffsets = prog.sourceOffsets
+	}
+	vm.code = cd.code
+	vm.codeIndex = cd.codeIndex
+
+	vm.GlobalValueStoreFunc = ctx.GlobalValueStoreFunc
+	vm.GlobalValueLoadFunc = ctx.GlobalValueLoadFunc
+	vm.GlobalValueLoadOverwriteFunc = ctx.GlobalValueLoadOverwriteFunc
+	vm.subThreadDepth = ctx.subThreadDepth + 1
+	vm.UpCtx = ctx
+	if cd.module != nil {
+		vm.UpCtx = cd.module
+	}
+	vm.NumOpCount = ctx.NumOpCount + 100 // 递归视为消耗 + 100
+	ctx.NumOpCount = vm.NumOpCount       // 防止无限递归
+	vm.MemoryUsed = ctx.MemoryUsed
+	vm.RandSrc = ctx.RandSrc
+	vm.CustomFlag = ctx.CustomFlag
+	vm.modules = ctx.getModuleCache()
+	if ctx.Config.OpCountLimit > 0 && vm.NumOpCount > vm.Config.OpCountLimit {
+		ctx.Error = errOpCountLimit
+		return nil
+	}
+
+	if vm.Attrs == nil {
+		complete := true
+		for _, i := range args {
+			if i == nil {
+				complete = false
+				break
+			}
+		}
+		if complete {
+			keys := make([]string, len(cd.Params))
+			for index, i := range cd.Params {
+				keys[index] = paramName(i)
+			}
+			vm.Attrs = newValueMapWith(keys, args)
+			return vm
+		}
+		vm.Attrs = &ValueMap{}
+	}
+
+	// 设置参数，默认值表达式在函数作用域中依次求值，可以引用前面的参数
+	for index, i := range cd.Params {
+		val := args[index]
+		if val == nil {
+			val = cd.Defaults[index].FuncInvokeRaw(vm, nil, true)
+			if vm.Error != nil {
+				ctx.Error = vm.Error
+				return nil
 			}
 		}
+		vm.Attrs.Store(paramName(i), val)
 	}
+	return vm
+}
+
+func (v *VMValue) FuncInvokeNative(ctx *Context, params []*VMValue) *VMValue {
+	return v.funcInvokeNativeNamed(ctx, params, nil)
+}
 
-	if len(cd.Params) != len(params) {
-		ctx.Error = fmt.Errorf("调用参数个数与函数定义不符，需求%d，传入%d", len(cd.Params), len(params))
+func (v *VMValue) funcInvokeNativeNamed(ctx *Context, params []*VMValue, names []string) *VMValue {
+	cd, _ := v.ReadNativeFunctionData()
+
+	// 设置参数
+	hasDefault := func(i int) bool {
+		return i < len(cd.Defaults) && cd.Defaults[i] != nil
+	}
+	args := funcArgsBind(ctx, cd.Name, cd.Params, hasDefault, params, names)
+	if ctx.Error != nil {
 		return nil
 	}
-	ret := cd.NativeFunc(ctx, cd.Self, params)
+	for index, i := range args {
+		if i == nil {
+			args[index] = cd.Defaults[index]
+		}
+	}
+
+	ret := cd.NativeFunc(ctx, cd.Self, args)
 
 	if ctx.Error != nil {
+		// 原生函数直接返回的错误没有错误码，记下函数名与参数类型
+		var re *RuntimeError
+		if !errors.As(ctx.Error, &re) && errorCode(ctx.Error) == ErrCodeUnknown {
+			ctx.Error = newRuntimeError(ErrCodeNativeFunction, cd.Name, ctx.Error, args...)
+		}
 		return nil
 	}
 
@@ -1634,8 +2126,11 @@ func (v *VMValue) FuncInvokeNative(ctx *Context, params []*VMValue) *VMValue {
 }
 
 func (v *VMValue) AsDictKey() (string, error) {
-	if v.TypeId == VMTypeString || v.TypeId == VMTypeInt || v.TypeId == VMTypeFloat {
+	if v.TypeId == VMTypeString || v.TypeId == VMTypeInt || v.TypeId == VMTypeFloat || v.TypeId == VMTypeBigInt {
 		return v.ToString(), nil
+	} else if v.TypeId == VMTypeBool {
+		// 与 == 一致，布尔值作为键时视为 1/0
+		return v.boolAsInt().ToString(), nil
 	} else {
 		return "", fmt.Errorf("类型错误: 字典键只能为字符串或数字，不支持 %s", v.GetTypeName())
 	}
@@ -1683,6 +2178,8 @@ func ValueEqual(a *VMValue, b *VMValue, autoConvert bool) bool {
 			c1, _ := a.ReadComputed()
 			c2, _ := b.ReadComputed()
 			return c1.Expr == c2.Expr
+		case VMTypeBigInt:
+			return a.Value.(*big.Int).Cmp(b.Value.(*big.Int)) == 0
 		case VMTypeNativeFunction:
 			fd1, _ := a.ReadNativeFunctionData()
 			fd2, _ := b.ReadNativeFunctionData()
@@ -1692,6 +2189,24 @@ func ValueEqual(a *VMValue, b *VMValue, autoConvert bool) bool {
 		}
 	} else {
 		if autoConvert {
+			// 布尔值与数字比较时视为 1/0
+			if a.TypeId == VMTypeBool || b.TypeId == VMTypeBool {
+				a, b = a.boolAsInt(), b.boolAsInt()
+				if a.TypeId == b.TypeId {
+					return a.Value == b.Value
+				}
+			}
+			if a.TypeId == VMTypeBigInt || b.TypeId == VMTypeBigInt {
+				if x, ok := a.ReadBigInt(); ok {
+					if y, ok := b.ReadBigInt(); ok {
+						return x.Cmp(y) == 0
+					}
+					return b.TypeId == VMTypeFloat && bigIntToFloat(x) == b.Value.(float64)
+				}
+				if y, ok := b.ReadBigInt(); ok {
+					return a.TypeId == VMTypeFloat && a.Value.(float64) == bigIntToFloat(y)
+				}
+			}
 			switch a.TypeId {
 			case VMTypeInt:
 				switch b.TypeId {
@@ -1714,6 +2229,10 @@ func NewIntVal(i IntType) *VMValue {
 	return &VMValue{TypeId: VMTypeInt, Value: i}
 }
 
+func NewBoolVal(b bool) *VMValue {
+	return &VMValue{TypeId: VMTypeBool, Value: b}
+}
+
 func NewFloatVal(i float64) *VMValue {
 	return &VMValue{TypeId: VMTypeFloat, Value: i}
 }
@@ -0,0 +1,179 @@
+package dicescript
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+const (
+	maxIntType = IntType(math.MaxInt)
+	minIntType = IntType(math.MinInt)
+
+	// 高精度整数的位数上限，避免 2^99999999 这样的运算耗尽内存
+	bigIntMaxBits = 1 << 16
+)
+
+var errBigIntTooLarge = fmt.Errorf("整数溢出: 结果超过了%d位二进制的上限", bigIntMaxBits)
+
+// NewBigIntVal 创建高精度整数。能用 IntType 表示的值会返回普通整数
+func NewBigIntVal(i *big.Int) *VMValue {
+	if i.IsInt64() {
+		n := i.Int64()
+		if n >= int64(minIntType) && n <= int64(maxIntType) {
+			return NewIntVal(IntType(n))
+		}
+	}
+	return &VMValue{TypeId: VMTypeBigInt, Value: i}
+}
+
+// ReadBigInt 读取整数，对普通整数同样有效
+func (v *VMValue) ReadBigInt() (*big.Int, bool) {
+	switch v.TypeId {
+	case VMTypeInt:
+		return big.NewInt(int64(v.Value.(IntType))), true
+	case VMTypeBigInt:
+		return v.Value.(*big.Int), true
+	}
+	return nil, false
+}
+
+func bigIntToFloat(i *big.Int) float64 {
+	f, _ := new(big.Float).SetInt(i).Float64()
+	return f
+}
+
+func intAdd(a, b IntType) (IntType, bool) {
+	c := a + b
+	return c, (c > a) == (b > 0)
+}
+
+func intSub(a, b IntType) (IntType, bool) {
+	c := a - b
+	return c, (c < a) == (b > 0)
+}
+
+func intMul(a, b IntType) (IntType, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	c := a * b
+	if (a == -1 && b == minIntType) || (b == -1 && a == minIntType) || c/b != a {
+		return c, false
+	}
+	return c, true
+}
+
+// intPow 快速幂，b 需为非负数
+func intPow(a, b IntType) (IntType, bool) {
+	ret := IntType(1)
+	var ok bool
+	for b > 0 {
+		if b&1 == 1 {
+			if ret, ok = intMul(ret, a); !ok {
+				return 0, false
+			}
+		}
+		b >>= 1
+		if b > 0 {
+			if a, ok = intMul(a, a); !ok {
+				return 0, false
+			}
+		}
+	}
+	return ret, true
+}
+
+// intOverflow 整数运算溢出。开启 EnableBigInt 时改用高精度整数计算，否则报错
+func intOverflow(ctx *Context, a *VMValue, op string, b *VMValue) *VMValue {
+	if ctx.Config.EnableBigInt {
+		return bigIntOp(ctx, a, op, b)
+	}
+	ctx.Error = newRuntimeError(ErrCodeIntOverflow, op, fmt.Errorf("整数溢出: %s %s %s 的结果超出整数范围", a.ToString(), op, b.ToString()), a, b)
+	return nil
+}
+
+// bigIntBinOp 高精度整数参与的二元运算，与浮点数运算时转为浮点数，交给 fallback 处理
+func (v *VMValue) bigIntBinOp(ctx *Context, op string, v2 *VMValue, fallback func(*VMValue, *Context, *VMValue) *VMValue) *VMValue {
+	a, b := v.boolAsInt(), v2.boolAsInt()
+	if a.TypeId == VMTypeFloat || b.TypeId == VMTypeFloat {
+		if x, ok := a.ReadBigInt(); ok {
+			a = NewFloatVal(bigIntToFloat(x))
+		}
+		if x, ok := b.ReadBigInt(); ok {
+			b = NewFloatVal(bigIntToFloat(x))
+		}
+		return fallback(a, ctx, b)
+	}
+	if _, ok := a.ReadBigInt(); !ok {
+		return nil
+	}
+	if _, ok := b.ReadBigInt(); !ok {
+		return nil
+	}
+	return bigIntOp(ctx, a, op, b)
+}
+
+// bigIntOp 以高精度整数进行运算，a 和 b 都必须是整数
+func bigIntOp(ctx *Context, a *VMValue, op string, b *VMValue) *VMValue {
+	x, _ := a.ReadBigInt()
+	y, _ := b.ReadBigInt()
+	ret := new(big.Int)
+
+	switch op {
+	case "+":
+		ret.Add(x, y)
+	case "-":
+		ret.Sub(x, y)
+	case "*":
+		if x.BitLen()+y.BitLen() > bigIntMaxBits+1 {
+			ctx.Error = errBigIntTooLarge
+			return nil
+		}
+		ret.Mul(x, y)
+	case "/", "%":
+		if y.Sign() == 0 {
+			if op == "/" && ctx.Config.IgnoreDiv0 {
+				return a
+			}
+			if op == "/" {
+				ctx.Error = newRuntimeError(ErrCodeDivideByZero, op, errors.New("被除数为0"), a, b)
+			} else {
+				ctx.Error = newRuntimeError(ErrCodeDivideByZero, op, errors.New("被除数被0"), a, b)
+			}
+			return nil
+		}
+		if op == "/" {
+			ret.Quo(x, y)
+		} else {
+			ret.Rem(x, y)
+		}
+	case "^":
+		if y.Sign() < 0 {
+			// 与普通整数保持一致，负指数的结果取整
+			return NewIntVal(IntType(math.Pow(bigIntToFloat(x), bigIntToFloat(y))))
+		}
+		if x.CmpAbs(big.NewInt(1)) > 0 && (!y.IsInt64() || y.Int64() > bigIntMaxBits/int64(x.BitLen()-1)) {
+			ctx.Error = errBigIntTooLarge
+			return nil
+		}
+		ret.Exp(x, y, nil)
+	case "<":
+		return boolToVMValue(ctx, x.Cmp(y) < 0)
+	case "<=":
+		return boolToVMValue(ctx, x.Cmp(y) <= 0)
+	case ">=":
+		return boolToVMValue(ctx, x.Cmp(y) >= 0)
+	case ">":
+		return boolToVMValue(ctx, x.Cmp(y) > 0)
+	default:
+		return nil
+	}
+
+	if ret.BitLen() > bigIntMaxBits {
+		ctx.Error = errBigIntTooLarge
+		return nil
+	}
+	return NewBigIntVal(ret)
+}
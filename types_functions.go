@@ -42,7 +42,7 @@ func (v *VMValue) ArrayItemGet(ctx *Context, index IntType) *VMValue {
 		}
 		return arr.List[index]
 	}
-	ctx.Error = errors.New("此类型无法取下标")
+	ctx.Error = newRuntimeError(ErrCodeIndex, "item", errors.New("此类型无法取下标"), v)
 	return nil
 }
 
@@ -56,7 +56,7 @@ func (v *VMValue) ArrayItemSet(ctx *Context, index IntType, val *VMValue) bool {
 		arr.List[index] = val.Clone()
 		return true
 	}
-	ctx.Error = errors.New("此类型无法赋值下标")
+	ctx.Error = newRuntimeError(ErrCodeIndex, "item", errors.New("此类型无法赋值下标"), v)
 	return false
 }
 
@@ -2,6 +2,8 @@ package dicescript
 
 import (
 	"errors"
+	"strings"
+	"unicode/utf8"
 
 	"golang.org/x/exp/rand"
 )
@@ -37,6 +39,8 @@ func funcArraySum(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
 		switch i.TypeId {
 		case VMTypeInt:
 			sumNum += float64(i.MustReadInt())
+		case VMTypeBool:
+			sumNum += float64(i.boolAsInt().MustReadInt())
 		case VMTypeFloat:
 			isAllInt = false
 			sumNum += i.MustReadFloat()
@@ -107,12 +111,18 @@ func funcArrayShift(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
 
 func funcArrayPush(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
 	arr, _ := this.ReadArray()
+	if !ctx.allocArray(1) {
+		return nil
+	}
 	arr.List = append(arr.List, params[0])
 	return this
 }
 
 func funcDictKeys(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
 	d := this.MustReadDictData()
+	if !ctx.allocArray(IntType(d.Dict.Length())) {
+		return nil
+	}
 	var arr []*VMValue
 	d.Dict.Range(func(key string, value *VMValue) bool {
 		arr = append(arr, NewStrVal(key))
@@ -123,6 +133,9 @@ func funcDictKeys(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
 
 func funcDictValues(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
 	d := this.MustReadDictData()
+	if !ctx.allocArray(IntType(d.Dict.Length())) {
+		return nil
+	}
 	var arr []*VMValue
 	d.Dict.Range(func(key string, value *VMValue) bool {
 		arr = append(arr, value)
@@ -133,6 +146,10 @@ func funcDictValues(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
 
 func funcDictItems(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
 	d := this.MustReadDictData()
+	// 每一项是一个含两个元素的数组
+	if !ctx.allocArray(IntType(d.Dict.Length()) * 3) {
+		return nil
+	}
 	var arr []*VMValue
 	d.Dict.Range(func(key string, value *VMValue) bool {
 		arr = append(arr, NewArrayVal(NewStrVal(key), value))
@@ -146,6 +163,207 @@ func funcDictLen(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
 	return NewIntVal(IntType(d.Dict.Length()))
 }
 
+func funcStrLen(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
+	s, _ := this.ReadString()
+	return NewIntVal(IntType(utf8.RuneCountInString(s)))
+}
+
+func funcStrSplit(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
+	s, _ := this.ReadString()
+	sep, ok := params[0].ReadString()
+	if !ok {
+		ctx.Error = errors.New("(str.split)类型错误: 分隔符必须为str")
+		return nil
+	}
+
+	var parts []string
+	if sep == "" {
+		parts = strings.Fields(s)
+	} else {
+		parts = strings.Split(s, sep)
+	}
+	if !ctx.allocArray(IntType(len(parts))) {
+		return nil
+	}
+
+	arr := make([]*VMValue, len(parts))
+	for i, part := range parts {
+		arr[i] = NewStrVal(part)
+	}
+	return NewArrayValRaw(arr)
+}
+
+func funcStrJoin(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
+	sep, _ := this.ReadString()
+	arr, ok := params[0].ReadArray()
+	if !ok {
+		ctx.Error = errors.New("(str.join)类型错误: 参数必须为array")
+		return nil
+	}
+
+	items := make([]string, len(arr.List))
+	length := len(sep) * (len(items) - 1)
+	for i, item := range arr.List {
+		items[i] = item.ToString()
+		length += len(items[i])
+	}
+	if !ctx.allocString(length) {
+		return nil
+	}
+	return NewStrVal(strings.Join(items, sep))
+}
+
+func funcStrReplace(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
+	s, _ := this.ReadString()
+	old, ok1 := params[0].ReadString()
+	repl, ok2 := params[1].ReadString()
+	if !ok1 || !ok2 {
+		ctx.Error = errors.New("(str.replace)类型错误: 参数必须为str")
+		return nil
+	}
+
+	// old 为空时会在每个字符之间插入，次数为字符数加一
+	count := strings.Count(s, old)
+	if !ctx.allocString(len(s) + count*(len(repl)-len(old))) {
+		return nil
+	}
+	return NewStrVal(strings.ReplaceAll(s, old, repl))
+}
+
+func funcStrTrim(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
+	s, _ := this.ReadString()
+	return NewStrVal(strings.TrimSpace(s))
+}
+
+func funcStrUpper(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
+	s, _ := this.ReadString()
+	if !ctx.allocString(len(s)) {
+		return nil
+	}
+	return NewStrVal(strings.ToUpper(s))
+}
+
+func funcStrLower(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
+	s, _ := this.ReadString()
+	if !ctx.allocString(len(s)) {
+		return nil
+	}
+	return NewStrVal(strings.ToLower(s))
+}
+
+func funcStrStartsWith(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
+	s, _ := this.ReadString()
+	prefix, ok := params[0].ReadString()
+	if !ok {
+		ctx.Error = errors.New("(str.startsWith)类型错误: 参数必须为str")
+		return nil
+	}
+	return boolToVMValue(ctx, strings.HasPrefix(s, prefix))
+}
+
+func funcStrEndsWith(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
+	s, _ := this.ReadString()
+	suffix, ok := params[0].ReadString()
+	if !ok {
+		ctx.Error = errors.New("(str.endsWith)类型错误: 参数必须为str")
+		return nil
+	}
+	return boolToVMValue(ctx, strings.HasSuffix(s, suffix))
+}
+
+func funcStrContains(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
+	s, _ := this.ReadString()
+	sub, ok := params[0].ReadString()
+	if !ok {
+		ctx.Error = errors.New("(str.contains)类型错误: 参数必须为str")
+		return nil
+	}
+	return boolToVMValue(ctx, strings.Contains(s, sub))
+}
+
+func funcStrIndexOf(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
+	s, _ := this.ReadString()
+	sub, ok := params[0].ReadString()
+	if !ok {
+		ctx.Error = errors.New("(str.indexOf)类型错误: 参数必须为str")
+		return nil
+	}
+
+	// 返回的是字符下标而非字节下标，与取下标、分片的行为保持一致
+	index := strings.Index(s, sub)
+	if index == -1 {
+		return NewIntVal(-1)
+	}
+	return NewIntVal(IntType(utf8.RuneCountInString(s[:index])))
+}
+
+func funcStrRepeat(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
+	s, _ := this.ReadString()
+	times, ok := params[0].ReadInt()
+	if !ok {
+		ctx.Error = errors.New("(str.repeat)类型错误: 次数必须为int")
+		return nil
+	}
+	if times < 0 {
+		times = 0
+	}
+
+	// 先除后比，避免次数过大时乘积溢出
+	if n := IntType(utf8.RuneCountInString(s)); n > 0 && times > 10000/n {
+		ctx.Error = errors.New("不能一次性创建过长的字符串")
+		return nil
+	}
+	if !ctx.allocString(len(s) * int(times)) {
+		return nil
+	}
+	return NewStrVal(strings.Repeat(s, int(times)))
+}
+
+func funcStrPadStart(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
+	s, _ := this.ReadString()
+	length, ok := params[0].ReadInt()
+	if !ok {
+		ctx.Error = errors.New("(str.padStart)类型错误: 长度必须为int")
+		return nil
+	}
+	fill, ok := params[1].ReadString()
+	if !ok {
+		ctx.Error = errors.New("(str.padStart)类型错误: 填充内容必须为str")
+		return nil
+	}
+	if length > 10000 {
+		ctx.Error = errors.New("不能一次性创建过长的字符串")
+		return nil
+	}
+
+	cur := IntType(utf8.RuneCountInString(s))
+	if fill == "" || cur >= length {
+		return NewStrVal(s)
+	}
+
+	fillRunes := []rune(fill)
+	if !ctx.allocString(len(s) + int(length-cur)*len(fill)/len(fillRunes)) {
+		return nil
+	}
+	pad := make([]rune, length-cur)
+	for i := range pad {
+		pad[i] = fillRunes[i%len(fillRunes)]
+	}
+	return NewStrVal(string(pad) + s)
+}
+
+func funcStrChars(ctx *Context, this *VMValue, params []*VMValue) *VMValue {
+	s, _ := this.ReadString()
+	if !ctx.allocArray(IntType(utf8.RuneCountInString(s))) {
+		return nil
+	}
+	var arr []*VMValue
+	for _, r := range s {
+		arr = append(arr, NewStrVal(string(r)))
+	}
+	return NewArrayValRaw(arr)
+}
+
 var builtinProto = map[VMValueType]*VMDictValue{
 	VMTypeComputedValue: NewDictValWithArrayMust(
 		NewStrVal("compute"), nnf(&ndf{"Computed.compute", []string{}, nil, nil, nil}),
@@ -162,6 +380,22 @@ var builtinProto = map[VMValueType]*VMDictValue{
 		NewStrVal("shift"), nnf(&ndf{"Array.shift", []string{}, nil, nil, funcArrayShift}),
 		NewStrVal("push"), nnf(&ndf{"Array.push", []string{"value"}, nil, nil, funcArrayPush}),
 	),
+	VMTypeString: NewDictValWithArrayMust(
+		NewStrVal("len"), nnf(&ndf{"Str.len", []string{}, nil, nil, funcStrLen}),
+		NewStrVal("split"), nnf(&ndf{"Str.split", []string{"sep"}, []*VMValue{NewStrVal("")}, nil, funcStrSplit}),
+		NewStrVal("join"), nnf(&ndf{"Str.join", []string{"items"}, nil, nil, funcStrJoin}),
+		NewStrVal("replace"), nnf(&ndf{"Str.replace", []string{"old", "new"}, nil, nil, funcStrReplace}),
+		NewStrVal("trim"), nnf(&ndf{"Str.trim", []string{}, nil, nil, funcStrTrim}),
+		NewStrVal("upper"), nnf(&ndf{"Str.upper", []string{}, nil, nil, funcStrUpper}),
+		NewStrVal("lower"), nnf(&ndf{"Str.lower", []string{}, nil, nil, funcStrLower}),
+		NewStrVal("startsWith"), nnf(&ndf{"Str.startsWith", []string{"prefix"}, nil, nil, funcStrStartsWith}),
+		NewStrVal("endsWith"), nnf(&ndf{"Str.endsWith", []string{"suffix"}, nil, nil, funcStrEndsWith}),
+		NewStrVal("contains"), nnf(&ndf{"Str.contains", []string{"sub"}, nil, nil, funcStrContains}),
+		NewStrVal("indexOf"), nnf(&ndf{"Str.indexOf", []string{"sub"}, nil, nil, funcStrIndexOf}),
+		NewStrVal("repeat"), nnf(&ndf{"Str.repeat", []string{"times"}, nil, nil, funcStrRepeat}),
+		NewStrVal("padStart"), nnf(&ndf{"Str.padStart", []string{"length", "fill"}, []*VMValue{nil, NewStrVal(" ")}, nil, funcStrPadStart}),
+		NewStrVal("chars"), nnf(&ndf{"Str.chars", []string{}, nil, nil, funcStrChars}),
+	),
 	VMTypeDict: NewDictValWithArrayMust(
 		NewStrVal("keys"), nnf(&ndf{"Dict.keys", []string{}, nil, nil, funcDictKeys}),
 		NewStrVal("values"), nnf(&ndf{"Dict.values", []string{}, nil, nil, funcDictValues}),
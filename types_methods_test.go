@@ -66,3 +66,69 @@ func TestTypesMethodDictLen(t *testing.T) {
 	v := funcDictLen(nil, d.V(), nil)
 	assert.Equal(t, v.MustReadInt(), IntType(2))
 }
+
+func TestTypesMethodStrBasic(t *testing.T) {
+	vm := NewVM()
+	s := ns(" 力量60 ")
+	assert.Equal(t, funcStrLen(vm, s, nil).MustReadInt(), IntType(6))
+	assert.Equal(t, funcStrTrim(vm, s, nil).ToString(), "力量60")
+	assert.Equal(t, funcStrUpper(vm, ns("abc中"), nil).ToString(), "ABC中")
+	assert.Equal(t, funcStrLower(vm, ns("ABC中"), nil).ToString(), "abc中")
+	assert.Equal(t, funcStrReplace(vm, ns("a-b-c"), []*VMValue{ns("-"), ns("+")}).ToString(), "a+b+c")
+	assert.Equal(t, funcStrRepeat(vm, ns("啊"), []*VMValue{ni(3)}).ToString(), "啊啊啊")
+}
+
+func TestTypesMethodStrSplitJoin(t *testing.T) {
+	vm := NewVM()
+	v := funcStrSplit(vm, ns("力量,敏捷,体质"), []*VMValue{ns(",")})
+	assert.True(t, valueEqual(v, na(ns("力量"), ns("敏捷"), ns("体质"))))
+
+	v = funcStrSplit(vm, ns(" a  b "), []*VMValue{ns("")})
+	assert.True(t, valueEqual(v, na(ns("a"), ns("b"))))
+
+	v = funcStrJoin(vm, ns("、"), []*VMValue{na(ns("a"), ni(1))})
+	assert.Equal(t, v.ToString(), "a、1")
+
+	funcStrJoin(vm, ns(","), []*VMValue{ns("a")})
+	assert.Error(t, vm.Error)
+}
+
+func TestTypesMethodStrSearch(t *testing.T) {
+	vm := NewVM()
+	s := ns("力量60敏捷70")
+	assert.Equal(t, funcStrIndexOf(vm, s, []*VMValue{ns("敏捷")}).MustReadInt(), IntType(4))
+	assert.Equal(t, funcStrIndexOf(vm, s, []*VMValue{ns("体质")}).MustReadInt(), IntType(-1))
+	assert.True(t, funcStrContains(vm, s, []*VMValue{ns("60")}).AsBool())
+	assert.True(t, funcStrStartsWith(vm, s, []*VMValue{ns("力量")}).AsBool())
+	assert.False(t, funcStrEndsWith(vm, s, []*VMValue{ns("力量")}).AsBool())
+}
+
+func TestTypesMethodStrPadAndChars(t *testing.T) {
+	vm := NewVM()
+	assert.Equal(t, funcStrPadStart(vm, ns("7"), []*VMValue{ni(3), ns("0")}).ToString(), "007")
+	assert.Equal(t, funcStrPadStart(vm, ns("中文"), []*VMValue{ni(4), ns("＊")}).ToString(), "＊＊中文")
+	assert.Equal(t, funcStrPadStart(vm, ns("abc"), []*VMValue{ni(2), ns(" ")}).ToString(), "abc")
+
+	v := funcStrChars(vm, ns("骰子d20"), nil)
+	assert.True(t, valueEqual(v, na(ns("骰"), ns("子"), ns("d"), ns("2"), ns("0"))))
+}
+
+func TestTypesMethodStrScript(t *testing.T) {
+	simpleExecute(t, "'你好世界'.len()", ni(4))
+	simpleExecute(t, "'a,b,c'.split(',')", na(ns("a"), ns("b"), ns("c")))
+	// 不传分隔符或分隔符为空时按空白拆分，连续空白视为一处，首尾空白忽略
+	simpleExecute(t, "' a  b\tc '.split()", na(ns("a"), ns("b"), ns("c")))
+	simpleExecute(t, "' a  b\tc '.split('')", na(ns("a"), ns("b"), ns("c")))
+	simpleExecute(t, "'a,,b'.split(',')", na(ns("a"), ns(""), ns("b")))
+	simpleExecute(t, "'-'.join(['a', 'b'])", ns("a-b"))
+	simpleExecute(t, "s = '7'; s.padStart(3, '0')", ns("007"))
+	simpleExecute(t, "'d20'.padStart(5)", ns("  d20"))
+	simpleExecute(t, "'敏捷70'.indexOf('70')", ni(2))
+
+	vm := NewVM()
+	err := vm.Run("'abc'.repeat(100000)")
+	assert.Error(t, err)
+	err = vm.Run("'ab'.repeat(4611686018427387904)")
+	assert.EqualError(t, err, "不能一次性创建过长的字符串")
+	simpleExecute(t, "''.repeat(4611686018427387904)", ns(""))
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"math/big"
 )
 
 func (v *VMValue) ToJSONRaw(save map[*VMValue]bool) ([]byte, error) {
@@ -11,7 +12,7 @@ func (v *VMValue) ToJSONRaw(save map[*VMValue]bool) ([]byte, error) {
 		return nil, errors.New("nil pointer")
 	}
 	switch v.TypeId {
-	case VMTypeInt:
+	case VMTypeInt, VMTypeBool:
 		fallthrough
 	case VMTypeFloat:
 		fallthrough
@@ -23,6 +24,13 @@ func (v *VMValue) ToJSONRaw(save map[*VMValue]bool) ([]byte, error) {
 			TypeId VMValueType `json:"t"`
 		}{v.TypeId})
 
+	case VMTypeBigInt:
+		// 以字符串保存，避免JSON解析时丢失精度
+		return json.Marshal(struct {
+			TypeId VMValueType `json:"t"`
+			Value  string      `json:"v"`
+		}{v.TypeId, v.ToString()})
+
 	case VMTypeComputedValue:
 		cd, _ := v.ReadComputed()
 		x := struct {
@@ -90,20 +98,32 @@ func (v *VMValue) ToJSONRaw(save map[*VMValue]bool) ([]byte, error) {
 
 	case VMTypeFunction:
 		cd, _ := v.ReadFunctionData()
+		// 默认值只保存表达式，空字符串表示没有默认值
+		var defaults []string
+		for _, i := range cd.Defaults {
+			expr := ""
+			if i != nil {
+				fd, _ := i.ReadFunctionData()
+				expr = fd.Expr
+			}
+			defaults = append(defaults, expr)
+		}
 		return json.Marshal(struct {
 			TypeId VMValueType `json:"t"`
 			Value  struct {
-				Expr   string   `json:"expr"`
-				Name   string   `json:"name"`
-				Params []string `json:"params"`
+				Expr     string   `json:"expr"`
+				Name     string   `json:"name"`
+				Params   []string `json:"params"`
+				Defaults []string `json:"defaults,omitempty"`
 			} `json:"v"`
 		}{
 			v.TypeId,
 			struct {
-				Expr   string   `json:"expr"`
-				Name   string   `json:"name"`
-				Params []string `json:"params"`
-			}{cd.Expr, cd.Name, cd.Params},
+				Expr     string   `json:"expr"`
+				Name     string   `json:"name"`
+				Params   []string `json:"params"`
+				Defaults []string `json:"defaults,omitempty"`
+			}{cd.Expr, cd.Name, cd.Params, defaults},
 		})
 
 	case VMTypeNativeFunction:
@@ -162,6 +182,28 @@ func (v *VMValue) UnmarshalJSON(input []byte) error {
 			v.Value = NewIntVal(v1.Value).Value
 		}
 		return err
+	case VMTypeBigInt:
+		var v1 struct {
+			Value string `json:"v"`
+		}
+		if err := json.Unmarshal(input, &v1); err != nil {
+			return err
+		}
+		i, ok := new(big.Int).SetString(v1.Value, 10)
+		if !ok {
+			return errors.New("值错误: 无法解析的高精度整数 " + v1.Value)
+		}
+		*v = *NewBigIntVal(i)
+		return nil
+	case VMTypeBool:
+		var v1 struct {
+			Value bool `json:"v"`
+		}
+		err := json.Unmarshal(input, &v1)
+		if err == nil {
+			v.Value = NewBoolVal(v1.Value).Value
+		}
+		return err
 	case VMTypeFloat:
 		var v1 struct {
 			Value float64 `json:"v"`
@@ -230,14 +272,22 @@ func (v *VMValue) UnmarshalJSON(input []byte) error {
 	case VMTypeFunction:
 		var v1 struct {
 			Value struct {
-				Expr   string   `json:"expr"`
-				Name   string   `json:"name"`
-				Params []string `json:"params"`
+				Expr     string   `json:"expr"`
+				Name     string   `json:"name"`
+				Params   []string `json:"params"`
+				Defaults []string `json:"defaults"`
 			} `json:"v"`
 		}
 		err := json.Unmarshal(input, &v1)
 		if err == nil {
 			fd := &FunctionData{Expr: v1.Value.Expr, Name: v1.Value.Name, Params: v1.Value.Params}
+			for _, expr := range v1.Value.Defaults {
+				var val *VMValue
+				if expr != "" {
+					val = NewFunctionValRaw(&FunctionData{Expr: expr})
+				}
+				fd.Defaults = append(fd.Defaults, val)
+			}
 			v.Value = fd
 			return nil
 		}
@@ -1,8 +1,10 @@
 package dicescript
 
 import (
-	"github.com/stretchr/testify/assert"
+	"math/big"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestDumps(t *testing.T) {
@@ -29,6 +31,16 @@ func TestDumps(t *testing.T) {
 		assert.Equal(t, `{"t":4}`, string(v))
 	}
 
+	v, err = NewBigIntVal(new(big.Int).Lsh(big.NewInt(1), 80)).ToJSON()
+	if assert.NoError(t, err) {
+		assert.Equal(t, `{"t":12,"v":"1208925819614629174706176"}`, string(v))
+	}
+
+	v, err = NewBoolVal(true).ToJSON()
+	if assert.NoError(t, err) {
+		assert.Equal(t, `{"t":11,"v":true}`, string(v))
+	}
+
 	v, err = NewComputedVal("1 + this.x + d10").ToJSON()
 	if assert.NoError(t, err) {
 		assert.Equal(t, `{"t":5,"v":{"expr":"1 + this.x + d10"}}`, string(v))
@@ -91,6 +103,25 @@ func TestLoads(t *testing.T) {
 		assert.Equal(t, IntType(123), v.Value)
 	}
 
+	v, err = VMValueFromJSON([]byte(`{"t":12,"v":"1208925819614629174706176"}`))
+	if assert.NoError(t, err) {
+		assert.Equal(t, VMTypeBigInt, v.TypeId)
+		assert.Equal(t, "1208925819614629174706176", v.ToString())
+	}
+
+	// 范围内的值还原为普通整数
+	v, err = VMValueFromJSON([]byte(`{"t":12,"v":"12"}`))
+	if assert.NoError(t, err) {
+		assert.Equal(t, VMTypeInt, v.TypeId)
+		assert.Equal(t, IntType(12), v.Value)
+	}
+
+	v, err = VMValueFromJSON([]byte(`{"t":11,"v":false}`))
+	if assert.NoError(t, err) {
+		assert.Equal(t, VMTypeBool, v.TypeId)
+		assert.Equal(t, false, v.Value)
+	}
+
 	v, err = VMValueFromJSON([]byte(`{"t":1,"v":3.2}`))
 	if assert.NoError(t, err) {
 		assert.Equal(t, VMTypeFloat, v.TypeId)
@@ -131,6 +162,16 @@ func TestLoads(t *testing.T) {
 		assert.Equal(t, []string{"x"}, fd.Params)
 	}
 
+	v, err = VMValueFromJSON([]byte(`{"t":8,"v":{"expr":"x + y","name":"a","params":["x","y"],"defaults":["","x * 2"]}}`))
+	if assert.NoError(t, err) {
+		fd, _ := v.ReadFunctionData()
+		assert.Nil(t, fd.Defaults[0])
+		vm := NewVM()
+		ret := v.FuncInvoke(vm, []*VMValue{ni(3)})
+		assert.NoError(t, vm.Error)
+		assert.True(t, valueEqual(ret, ni(9)))
+	}
+
 	v, err = VMValueFromJSON([]byte(`{"t":6,"v":{"list":[{"t":0,"v":1},{"t":1,"v":2},{"t":2,"v":"test"}]}}`))
 	if assert.NoError(t, err) {
 		assert.Equal(t, v.TypeId, VMTypeArray)
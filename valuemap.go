@@ -162,6 +162,17 @@ func (m *ValueMap) MustLoad(key string) *VMValue {
 	return v
 }
 
+// newValueMapWith 创建含有给定键值的 ValueMap，键值直接放入只读部分，省去逐个 Store 时的加锁和复制
+func newValueMapWith(keys []string, values []*VMValue) *ValueMap {
+	read := make(map[string]*entryValueMap, len(keys))
+	for i, k := range keys {
+		read[k] = newEntryValueMap(values[i])
+	}
+	m := &ValueMap{}
+	m.read.Store(readOnlyValueMap{m: read})
+	return m
+}
+
 func (e *entryValueMap) load() (value *VMValue, ok bool) {
 	p := atomic.LoadPointer(&e.p)
 	if p == nil || p == expungedValueMap {